@@ -1,9 +1,26 @@
 package main
 
 import (
+	"flag"
+
 	"github.com/faiface/pixel"
 	"github.com/faiface/pixel/pixelgl"
 	"github.com/remmakoshino/kirby-inspired-go/internal/game"
+	"github.com/remmakoshino/kirby-inspired-go/internal/menu"
+	"github.com/remmakoshino/kirby-inspired-go/internal/netcode"
+)
+
+// ネット対戦の設定（-net-peer を指定するとメニューを飛ばして試合を始める）
+var (
+	netListen = flag.String("net-listen", ":7000", "ネット対戦で待ち受けるアドレス")
+	netPeer   = flag.String("net-peer", "", "ネット対戦の相手のアドレス（例: 192.168.0.2:7000）")
+	netPlayer = flag.Int("net-player", 1, "このマシンで操作するプレイヤー（1 または 2）")
+	netDelay  = flag.Int("net-delay", netcode.DefaultInputDelay, "入力遅延（フレーム、両方で同じ値にする）")
+	netSeed   = flag.Int64("net-seed", 1, "乱数シード（両方で同じ値にする）")
+	netMode   = flag.String("net-mode", "versus", "versus または story")
+	netP1     = flag.String("net-p1", "Kirby", "1Pのキャラクター")
+	netP2     = flag.String("net-p2", "MetaKnight", "2Pのキャラクター")
+	netStage  = flag.Int("net-stage", 1, "ストーリーのステージ番号")
 )
 
 func run() {
@@ -20,9 +37,36 @@ func run() {
 	
 	// ゲーム作成と実行
 	g := game.NewGame(win)
+	if *netPeer != "" {
+		if err := g.StartNetplay(netplayConfig()); err != nil {
+			panic(err)
+		}
+	}
 	g.Run()
 }
 
+// netplayConfig はコマンドライン引数からネット対戦の設定を作ります
+func netplayConfig() game.NetplayConfig {
+	cfg := game.NetplayConfig{
+		Listen:      *netListen,
+		Peer:        *netPeer,
+		LocalPlayer: *netPlayer - 1,
+		InputDelay:  *netDelay,
+		Seed:        *netSeed,
+		Mode:        menu.ModeVersus,
+		Characters:  []string{*netP1, *netP2},
+		Stage:       *netStage,
+		Rule:        menu.RuleStock,
+		Stocks:      3,
+		Minutes:     2,
+	}
+	if *netMode == "story" {
+		cfg.Mode = menu.ModeStory
+	}
+	return cfg
+}
+
 func main() {
+	flag.Parse()
 	pixelgl.Run(run)
 }
@@ -0,0 +1,213 @@
+// netharness は2つのゲームをループバックのUDPでつなぎ、
+// 遅延・ゆらぎ・パケットロスのある回線でロールバックネットコードを検証します。
+// 両者の確定フレームのチェックサムが、同じ入力で進めたオフラインの結果と一致するかを確認します
+package main
+
+import (
+	"flag"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"time"
+
+	"github.com/remmakoshino/kirby-inspired-go/internal/game"
+	"github.com/remmakoshino/kirby-inspired-go/internal/menu"
+	"github.com/remmakoshino/kirby-inspired-go/internal/netcode"
+	"github.com/remmakoshino/kirby-inspired-go/internal/rng"
+)
+
+// peer は片方のマシンに相当します
+type peer struct {
+	Game      *game.Game
+	Session   *netcode.Session
+	Lossy     *netcode.LossyTransport
+	Checksums map[int]uint32
+	
+	// 2つのゲームが同じプロセスにあるので、共有の乱数の状態をそれぞれ持っておく
+	rngState  uint64
+	nextCheck int
+}
+
+// advance は共有の乱数を自分の状態に入れ替えてから1フレーム進めます
+func (p *peer) advance(input netcode.Input) (bool, error) {
+	rng.SetState(p.rngState)
+	advanced, err := p.Session.AdvanceFrame(input)
+	p.rngState = rng.State()
+	
+	for {
+		sum, ok := p.Session.Checksum(p.nextCheck)
+		if !ok {
+			break
+		}
+		p.Checksums[p.nextCheck] = sum
+		p.nextCheck++
+	}
+	return advanced, err
+}
+
+// scriptedInput はプレイヤーとフレームから決まる入力を返します。
+// 数フレームずつ同じ入力を続けるので、予測が当たる場合と外れる場合の両方が起きます
+func scriptedInput(seed int64, player, frame int) netcode.Input {
+	r := rng.New(seed + int64(player)<<32 + int64(frame/12))
+	return netcode.Input(r.Uint64()) & (netcode.InputLeft | netcode.InputRight | netcode.InputUp |
+		netcode.InputDown | netcode.InputJump | netcode.InputAttack | netcode.InputUseAbility |
+		netcode.InputGuard | netcode.InputRun | netcode.InputEvade)
+}
+
+func main() {
+	frames := flag.Int("frames", 600, "進めるフレーム数")
+	latency := flag.Duration("latency", 60*time.Millisecond, "片道の遅延")
+	jitter := flag.Duration("jitter", 20*time.Millisecond, "遅延のゆらぎ")
+	loss := flag.Float64("loss", 0.05, "パケットロス率（0〜1）")
+	delay := flag.Int("delay", netcode.DefaultInputDelay, "入力遅延（フレーム）")
+	seed := flag.Int64("seed", 1, "試合と入力の乱数シード")
+	mode := flag.String("mode", "versus", "versus または story")
+	flag.Parse()
+	
+	cfg := game.NetplayConfig{
+		InputDelay: *delay,
+		Seed:       *seed,
+		Mode:       menu.ModeVersus,
+		Characters: []string{"Kirby", "MetaKnight"},
+		Rule:       menu.RuleStock,
+		Stocks:     3,
+		Minutes:    2,
+	}
+	if *mode == "story" {
+		cfg.Mode = menu.ModeStory
+	}
+	
+	if err := run(cfg, *frames, netcode.LinkConditions{Latency: *latency, Jitter: *jitter, Loss: *loss}); err != nil {
+		fmt.Fprintln(os.Stderr, "FAIL:", err)
+		os.Exit(1)
+	}
+	fmt.Println("OK")
+}
+
+// run は2つのピアを目標フレームまで進め、結果を検証します
+func run(cfg game.NetplayConfig, frames int, link netcode.LinkConditions) error {
+	udp := make([]*netcode.UDPTransport, netcode.Players)
+	for i := range udp {
+		t, err := netcode.NewUDPTransport("127.0.0.1:0")
+		if err != nil {
+			return err
+		}
+		defer t.Close()
+		udp[i] = t
+	}
+	for i, t := range udp {
+		if err := t.Connect(udp[1-i].LocalAddr()); err != nil {
+			return err
+		}
+	}
+	
+	peers := make([]*peer, netcode.Players)
+	for i := range peers {
+		conditions := link
+		conditions.Seed = cfg.Seed + int64(i) + 1
+		lossy := netcode.NewLossyTransport(udp[i], conditions)
+		
+		g := game.NewHeadlessGame()
+		g.StartMatch(cfg)
+		session, err := netcode.NewSession(g.Simulation(), lossy, netcode.Config{
+			LocalPlayer: i,
+			InputDelay:  cfg.InputDelay,
+		})
+		if err != nil {
+			return err
+		}
+		peers[i] = &peer{
+			Game:      g,
+			Session:   session,
+			Lossy:     lossy,
+			Checksums: make(map[int]uint32),
+			rngState:  rng.State(),
+		}
+	}
+	
+	// 60Hzで両方を進める（実際のマシンと同じく、待たされた側は止まる）
+	ticker := time.NewTicker(time.Second / 60)
+	defer ticker.Stop()
+	deadline := time.Now().Add(time.Duration(frames)*time.Second/60*4 + 10*time.Second)
+	for peers[0].Session.Frame() < frames || peers[1].Session.Frame() < frames {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out at frames %d / %d", peers[0].Session.Frame(), peers[1].Session.Frame())
+		}
+		<-ticker.C
+		// 目標に着いた側も、相手が失った入力を再送するために進め続ける
+		for i, p := range peers {
+			if _, err := p.advance(scriptedInput(cfg.Seed, i, p.Session.Frame())); err != nil {
+				return fmt.Errorf("peer %d: %v", i+1, err)
+			}
+		}
+	}
+	
+	for i, p := range peers {
+		s := p.Session.Stats
+		fmt.Printf("peer %d: frames=%d confirmed=%d rollbacks=%d resimulated=%d stalls=%d syncwaits=%d sent=%d recv=%d bad=%d dropped=%d\n",
+			i+1, p.Session.Frame(), p.Session.ConfirmedFrame(), s.Rollbacks, s.ResimulatedFrames,
+			s.Stalls, s.SyncWaits, s.PacketsSent, s.PacketsReceived, s.BadPackets, p.Lossy.Dropped)
+	}
+	
+	reference, err := referenceChecksums(cfg, frames)
+	if err != nil {
+		return err
+	}
+	return verify(peers, reference)
+}
+
+// referenceChecksums は通信なしで同じ入力を与えた場合の各フレーム開始時のチェックサムを返します
+func referenceChecksums(cfg game.NetplayConfig, frames int) (map[int]uint32, error) {
+	g := game.NewHeadlessGame()
+	g.StartMatch(cfg)
+	sim := g.Simulation()
+	
+	checksums := make(map[int]uint32, frames)
+	inputs := make([]netcode.Input, netcode.Players)
+	for f := 0; f < frames; f++ {
+		state, err := sim.SaveState()
+		if err != nil {
+			return nil, err
+		}
+		checksums[f] = crc32.ChecksumIEEE(state)
+		
+		// 入力は入力遅延の分だけ後のフレームに反映される
+		for player := range inputs {
+			inputs[player] = 0
+			if f >= cfg.InputDelay {
+				inputs[player] = scriptedInput(cfg.Seed, player, f-cfg.InputDelay)
+			}
+		}
+		sim.Step(inputs)
+	}
+	return checksums, nil
+}
+
+// verify は両ピアのチェックサムが互いに、そしてオフラインの結果と一致するかを確かめます
+func verify(peers []*peer, reference map[int]uint32) error {
+	for i, p := range peers {
+		if frame, desynced := p.Session.Desynced(); desynced {
+			return fmt.Errorf("peer %d detected desync at frame %d", i+1, frame)
+		}
+	}
+	
+	compared := 0
+	for f, want := range reference {
+		a, okA := peers[0].Checksums[f]
+		b, okB := peers[1].Checksums[f]
+		if okA && a != want {
+			return fmt.Errorf("peer 1 differs from reference at frame %d", f)
+		}
+		if okB && b != want {
+			return fmt.Errorf("peer 2 differs from reference at frame %d", f)
+		}
+		if okA && okB {
+			compared++
+		}
+	}
+	if compared == 0 {
+		return fmt.Errorf("no confirmed frames to compare")
+	}
+	fmt.Printf("checksums match on %d confirmed frames\n", compared)
+	return nil
+}
@@ -105,6 +105,10 @@ func CreateAbility(abilityType string) Ability {
 		return NewFlyAbility()
 	case "jump":
 		return NewJumpAbility()
+	case "fire":
+		return NewFireAbility()
+	case "stone":
+		return NewStoneAbility()
 	default:
 		return nil
 	}
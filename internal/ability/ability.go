@@ -31,6 +31,11 @@ type BaseAbility struct {
 
 // Update は能力の状態を更新します
 func (a *BaseAbility) Update(dt float64) {
+	a.AdvanceCooldown(dt)
+}
+
+// AdvanceCooldown はクールダウンだけを dt 秒進めます（装備でクールダウンを早める時に使う）
+func (a *BaseAbility) AdvanceCooldown(dt float64) {
 	if a.CurrentCooldown > 0 {
 		a.CurrentCooldown -= dt
 		if a.CurrentCooldown < 0 {
@@ -126,6 +126,64 @@ func (a *SwordAbility) Use(player AbilityUser) {
 	a.StartCooldown()
 }
 
+// FireAbility はファイア能力です（近くを焼く火の息。導火線に火をつけられる）
+type FireAbility struct {
+	BaseAbility
+	AttackRange  float64
+	AttackDamage int
+}
+
+// NewFireAbility は新しいファイア能力を作成します
+func NewFireAbility() *FireAbility {
+	return &FireAbility{
+		BaseAbility: BaseAbility{
+			Name:     "Fire",
+			Color:    color.RGBA{R: 255, G: 90, B: 40, A: 255},
+			Cooldown: 0.3,
+		},
+		AttackRange:  70.0,
+		AttackDamage: 15,
+	}
+}
+
+// Use は火の息を使用します
+func (a *FireAbility) Use(player AbilityUser) {
+	if !a.IsReady() {
+		return
+	}
+	
+	a.StartCooldown()
+}
+
+// StoneAbility はストーン能力です（重い体当たり。メタルブロックも壊せる）
+type StoneAbility struct {
+	BaseAbility
+	AttackRange  float64
+	AttackDamage int
+}
+
+// NewStoneAbility は新しいストーン能力を作成します
+func NewStoneAbility() *StoneAbility {
+	return &StoneAbility{
+		BaseAbility: BaseAbility{
+			Name:     "Stone",
+			Color:    color.RGBA{R: 150, G: 140, B: 130, A: 255},
+			Cooldown: 1.0,
+		},
+		AttackRange:  40.0,
+		AttackDamage: 35,
+	}
+}
+
+// Use はストーンの体当たりを使用します
+func (a *StoneAbility) Use(player AbilityUser) {
+	if !a.IsReady() {
+		return
+	}
+	
+	a.StartCooldown()
+}
+
 // TornadoAbility はトルネード突進能力です
 type TornadoAbility struct {
 	BaseAbility
@@ -235,15 +293,60 @@ func CreateAbilityFromType(abilityType string) Ability {
 		return NewHammerAbility()
 	case "sword":
 		return NewSwordAbility()
+	case "fire":
+		return NewFireAbility()
+	case "stone":
+		return NewStoneAbility()
 	case "tornado":
 		return NewTornadoAbility()
 	case "cape":
 		return NewCapeBarrierAbility()
+	case "spear":
+		return NewSpearThrowAbility()
+	case "copter":
+		return NewSpearCopterAbility()
+	case "parasol":
+		return NewParasolAbility()
 	default:
 		return nil
 	}
 }
 
+// TypeOf は能力の種類名を返します（CreateAbilityFromType で同じ能力を作れる名前）。
+// 能力がない場合や未知の能力は空文字列を返します
+func TypeOf(a Ability) string {
+	switch a.(type) {
+	case *SpeedAbility:
+		return "speed"
+	case *FlyAbility:
+		return "fly"
+	case *JumpAbility:
+		return "jump"
+	case *InhaleAbility:
+		return "inhale"
+	case *HammerAbility:
+		return "hammer"
+	case *SwordAbility:
+		return "sword"
+	case *FireAbility:
+		return "fire"
+	case *StoneAbility:
+		return "stone"
+	case *TornadoAbility:
+		return "tornado"
+	case *CapeBarrierAbility:
+		return "cape"
+	case *SpearThrowAbility:
+		return "spear"
+	case *SpearCopterAbility:
+		return "copter"
+	case *ParasolAbility:
+		return "parasol"
+	default:
+		return ""
+	}
+}
+
 // InhaleEffect は吸い込みエフェクトを計算します
 func InhaleEffect(playerPos, enemyPos pixel.Vec, inhaleForce, inhaleRange float64) pixel.Vec {
 	distance := playerPos.Sub(enemyPos).Len()
@@ -0,0 +1,127 @@
+package ability
+
+import (
+	"image/color"
+)
+
+// SpearThrowAbility は槍投げ能力です
+type SpearThrowAbility struct {
+	BaseAbility
+	Thrown bool // このフレームで投げたかどうか（使用側が回収してリセットする）
+}
+
+// NewSpearThrowAbility は新しい槍投げ能力を作成します
+func NewSpearThrowAbility() *SpearThrowAbility {
+	return &SpearThrowAbility{
+		BaseAbility: BaseAbility{
+			Name:     "Spear Throw",
+			Color:    color.RGBA{R: 230, G: 200, B: 120, A: 255},
+			Cooldown: 0.6,
+		},
+		Thrown: false,
+	}
+}
+
+// Use は槍を投げます
+func (a *SpearThrowAbility) Use(player AbilityUser) {
+	if !a.IsReady() {
+		return
+	}
+	
+	a.Thrown = true
+	a.StartCooldown()
+}
+
+// SpearCopterAbility は槍を回転させて空中に留まる能力です
+type SpearCopterAbility struct {
+	BaseAbility
+	IsActive      bool
+	Duration      float64
+	RemainingTime float64
+	LiftSpeed     float64
+}
+
+// NewSpearCopterAbility は新しいスピアコプター能力を作成します
+func NewSpearCopterAbility() *SpearCopterAbility {
+	return &SpearCopterAbility{
+		BaseAbility: BaseAbility{
+			Name:     "Spear Copter",
+			Color:    color.RGBA{R: 255, G: 170, B: 80, A: 255},
+			Cooldown: 2.5,
+		},
+		IsActive:      false,
+		Duration:      1.8,
+		RemainingTime: 0,
+		LiftSpeed:     60.0,
+	}
+}
+
+// Use はスピアコプターを使用します
+func (a *SpearCopterAbility) Use(player AbilityUser) {
+	if !a.IsReady() || a.IsActive {
+		return
+	}
+	
+	a.IsActive = true
+	a.RemainingTime = a.Duration
+	a.StartCooldown()
+}
+
+// Update はスピアコプターの状態を更新
+func (a *SpearCopterAbility) Update(dt float64) {
+	a.BaseAbility.Update(dt)
+	
+	if a.IsActive {
+		a.RemainingTime -= dt
+		if a.RemainingTime <= 0 {
+			a.IsActive = false
+			a.RemainingTime = 0
+		}
+	}
+}
+
+// ParasolAbility はパラソルで身を守る能力です
+type ParasolAbility struct {
+	BaseAbility
+	IsActive      bool
+	Duration      float64
+	RemainingTime float64
+}
+
+// NewParasolAbility は新しいパラソル能力を作成します
+func NewParasolAbility() *ParasolAbility {
+	return &ParasolAbility{
+		BaseAbility: BaseAbility{
+			Name:     "Parasol",
+			Color:    color.RGBA{R: 255, G: 120, B: 150, A: 255},
+			Cooldown: 3.0,
+		},
+		IsActive:      false,
+		Duration:      2.5,
+		RemainingTime: 0,
+	}
+}
+
+// Use はパラソルを開きます
+func (a *ParasolAbility) Use(player AbilityUser) {
+	if !a.IsReady() || a.IsActive {
+		return
+	}
+	
+	a.IsActive = true
+	a.RemainingTime = a.Duration
+	a.StartCooldown()
+}
+
+// Update はパラソルの状態を更新
+func (a *ParasolAbility) Update(dt float64) {
+	a.BaseAbility.Update(dt)
+	
+	if a.IsActive {
+		a.RemainingTime -= dt
+		if a.RemainingTime <= 0 {
+			a.IsActive = false
+			a.RemainingTime = 0
+		}
+	}
+}
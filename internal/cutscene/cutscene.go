@@ -0,0 +1,147 @@
+// Package cutscene はステージの始まりやボス戦の前後に流す会話とカットシーンの台本を扱います。
+// 台本は scripts.json に書かれていて、ステップを上から順に進めます。
+// 会話の表示やカメラ・役の動きはゲーム側が Director として受け持ちます
+package cutscene
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+)
+
+//go:embed scripts.json
+var defaultData []byte
+
+// Kind はステップの種類です
+type Kind string
+
+const (
+	KindSay    Kind = "say"    // 話し手の名前と顔の絵付きで台詞を出す（選択肢があれば選ばせる）
+	KindWait   Kind = "wait"   // Time 秒待つ
+	KindCamera Kind = "camera" // Time 秒かけてカメラを役か位置へ寄せ、Zoom 倍にする
+	KindMove   Kind = "move"   // Time 秒かけて役を DX だけ横に歩かせる
+	KindEvent  Kind = "event"  // ゲームに出来事を知らせる（能力を渡すなど）
+	KindLabel  Kind = "label"  // 選択肢と goto の飛び先
+	KindGoto   Kind = "goto"   // Label へ飛ぶ
+	KindEnd    Kind = "end"    // 台本を終える
+)
+
+// Choice は台詞の後に出す選択肢です
+type Choice struct {
+	Text string `json:"text"`
+	Goto string `json:"goto"` // 選んだ時の飛び先のラベル（空なら次のステップへ）
+}
+
+// Step は台本の1ステップです。使う項目は種類ごとに違います
+type Step struct {
+	Kind     Kind     `json:"kind"`
+	Speaker  string   `json:"speaker"`  // say: 話し手の名前（"player" なら1Pのキャラクター）
+	Portrait string   `json:"portrait"` // say: 顔の絵（キャラクターかボスのID、"player" なら1Pのキャラクター）
+	Text     string   `json:"text"`     // say: 台詞
+	Choices  []Choice `json:"choices"`  // say: 選択肢
+	Actor    string   `json:"actor"`    // camera, move: 役（"boss" か "players"）
+	X        float64  `json:"x"`        // camera: 役がいない時に寄せる位置
+	Y        float64  `json:"y"`
+	DX       float64  `json:"dx"`       // move: 横に動く距離
+	Zoom     float64  `json:"zoom"`     // camera: 拡大率（0 なら1倍）
+	Time     float64  `json:"time"`     // wait, camera, move: 秒数
+	Label    string   `json:"label"`    // label: 名前、goto: 飛び先
+	Event    string   `json:"event"`    // event: 出来事の名前
+}
+
+// Script は台本1つです
+type Script struct {
+	ID    string `json:"id"`
+	Steps []Step `json:"steps"`
+}
+
+// Library は台本の一覧です
+type Library struct {
+	Scripts []Script `json:"scripts"`
+}
+
+// Parse は台本を読み込み、ステップの種類と飛び先のラベルを確かめます
+func Parse(data []byte) (*Library, error) {
+	var lib Library
+	if err := json.Unmarshal(data, &lib); err != nil {
+		return nil, err
+	}
+	
+	seen := make(map[string]bool)
+	for _, s := range lib.Scripts {
+		if s.ID == "" {
+			return nil, fmt.Errorf("cutscene: script has no id")
+		}
+		if seen[s.ID] {
+			return nil, fmt.Errorf("cutscene: script %q appears twice", s.ID)
+		}
+		seen[s.ID] = true
+		if err := s.validate(); err != nil {
+			return nil, err
+		}
+	}
+	return &lib, nil
+}
+
+// validate は台本のステップを確かめます
+func (s Script) validate() error {
+	labels := make(map[string]bool)
+	for _, st := range s.Steps {
+		if st.Kind != KindLabel {
+			continue
+		}
+		if labels[st.Label] {
+			return fmt.Errorf("cutscene: script %q has label %q twice", s.ID, st.Label)
+		}
+		labels[st.Label] = true
+	}
+	
+	for i, st := range s.Steps {
+		switch st.Kind {
+		case KindSay:
+			if st.Text == "" {
+				return fmt.Errorf("cutscene: script %q step %d has no text", s.ID, i)
+			}
+			for _, c := range st.Choices {
+				if c.Goto != "" && !labels[c.Goto] {
+					return fmt.Errorf("cutscene: script %q step %d jumps to unknown label %q", s.ID, i, c.Goto)
+				}
+			}
+		case KindGoto:
+			if !labels[st.Label] {
+				return fmt.Errorf("cutscene: script %q step %d jumps to unknown label %q", s.ID, i, st.Label)
+			}
+		case KindMove:
+			if st.Actor == "" {
+				return fmt.Errorf("cutscene: script %q step %d moves no actor", s.ID, i)
+			}
+		case KindEvent:
+			if st.Event == "" {
+				return fmt.Errorf("cutscene: script %q step %d has no event", s.ID, i)
+			}
+		case KindWait, KindCamera, KindLabel, KindEnd:
+		default:
+			return fmt.Errorf("cutscene: script %q step %d has unknown kind %q", s.ID, i, st.Kind)
+		}
+	}
+	return nil
+}
+
+// Default は組み込みの台本を返します
+func Default() *Library {
+	lib, err := Parse(defaultData)
+	if err != nil {
+		panic(err)
+	}
+	return lib
+}
+
+// Lookup はIDから台本を探します
+func (lib *Library) Lookup(id string) (Script, bool) {
+	for _, s := range lib.Scripts {
+		if s.ID == id {
+			return s, true
+		}
+	}
+	return Script{}, false
+}
@@ -0,0 +1,200 @@
+package cutscene
+
+import "math"
+
+// CharsPerSecond は台詞を1文字ずつ出す速さです
+const CharsPerSecond = 40.0
+
+// Input はカットシーン中のそのフレームの操作です
+type Input struct {
+	Advance bool // 台詞を送る・選択肢を決める
+	Up      bool // 上の選択肢へ
+	Down    bool // 下の選択肢へ
+}
+
+// Director はステップの演出をゲームの世界で受け持ちます
+type Director interface {
+	// Begin はステップの始まりに呼ばれます（動かす前の位置を覚える、出来事を起こすなど）
+	Begin(s Step)
+	// Animate は camera と move のステップを進めます（t は 0〜1 の進み具合）
+	Animate(s Step, t float64)
+}
+
+// Runner は台本を1ステップずつ進めます。値だけを持つのでスナップショットにそのまま保存できます
+type Runner struct {
+	Script  Script
+	Index   int     // 今のステップ
+	Time    float64 // 今のステップの経過時間
+	Started bool    // 今のステップの Begin を呼んだ
+	Choice  int     // 選んでいる選択肢
+	Done    bool
+}
+
+// NewRunner は台本の最初から進める Runner を作成します
+func NewRunner(s Script) *Runner {
+	r := &Runner{Script: s}
+	if len(s.Steps) == 0 {
+		r.Done = true
+	}
+	return r
+}
+
+// Current は今のステップを返します（終わっていれば false）
+func (r *Runner) Current() (Step, bool) {
+	if r.Done || r.Index >= len(r.Script.Steps) {
+		return Step{}, false
+	}
+	return r.Script.Steps[r.Index], true
+}
+
+// Visible は今の台詞のうち、もう表示した部分を返します
+func (r *Runner) Visible() string {
+	s, ok := r.Current()
+	if !ok || s.Kind != KindSay {
+		return ""
+	}
+	text := []rune(s.Text)
+	n := int(r.Time * CharsPerSecond)
+	if n >= len(text) {
+		return s.Text
+	}
+	return string(text[:n])
+}
+
+// TextDone は今の台詞を最後まで表示したかを返します
+func (r *Runner) TextDone() bool {
+	s, ok := r.Current()
+	return ok && s.Kind == KindSay && int(r.Time*CharsPerSecond) >= len([]rune(s.Text))
+}
+
+// Update は台本を dt 秒進めます。台詞は操作を待ち、待つ必要のないステップは続けて進めます
+func (r *Runner) Update(dt float64, in Input, d Director) {
+	// goto だけの繰り返しで止まらないように、1フレームに進めるステップの数を限る
+	for guard := 0; guard <= len(r.Script.Steps); guard++ {
+		s, ok := r.Current()
+		if !ok {
+			r.Done = true
+			return
+		}
+		if !r.Started {
+			r.Started = true
+			r.Time = 0
+			r.Choice = 0
+			d.Begin(s)
+		}
+		r.Time += dt
+		dt = 0
+		
+		switch s.Kind {
+		case KindSay:
+			if !r.updateSay(s, in) {
+				return
+			}
+			in = Input{}
+			continue
+		case KindWait:
+			if r.Time < s.Time {
+				return
+			}
+		case KindCamera, KindMove:
+			t := 1.0
+			if s.Time > 0 {
+				t = math.Min(r.Time/s.Time, 1)
+			}
+			d.Animate(s, t)
+			if t < 1 {
+				return
+			}
+		case KindGoto:
+			r.jump(s.Label)
+			continue
+		case KindEnd:
+			r.Done = true
+			return
+		}
+		r.goTo(r.Index + 1)
+		in = Input{}
+	}
+}
+
+// updateSay は台詞の表示と選択肢を処理し、次のステップへ進んだら true を返します
+func (r *Runner) updateSay(s Step, in Input) bool {
+	if r.TextDone() && len(s.Choices) > 0 {
+		if in.Up {
+			r.Choice = (r.Choice + len(s.Choices) - 1) % len(s.Choices)
+		}
+		if in.Down {
+			r.Choice = (r.Choice + 1) % len(s.Choices)
+		}
+	}
+	if !in.Advance {
+		return false
+	}
+	
+	// 表示の途中なら最後まで出す
+	if !r.TextDone() {
+		r.Time = float64(len([]rune(s.Text)))/CharsPerSecond + 1e-6
+		return false
+	}
+	if len(s.Choices) > 0 && s.Choices[r.Choice].Goto != "" {
+		r.jump(s.Choices[r.Choice].Goto)
+	} else {
+		r.goTo(r.Index + 1)
+	}
+	return true
+}
+
+// Skip は残りのステップを飛ばします。役とカメラは最後の位置へ動かし、出来事は起こし、
+// 選択肢は今選んでいるもの（まだ出ていなければ最初のもの）を選びます
+func (r *Runner) Skip(d Director) {
+	limit := 4 * (len(r.Script.Steps) + 1)
+	for guard := 0; guard < limit; guard++ {
+		s, ok := r.Current()
+		if !ok {
+			break
+		}
+		if !r.Started {
+			r.Started = true
+			r.Choice = 0
+			d.Begin(s)
+		}
+		switch s.Kind {
+		case KindCamera, KindMove:
+			d.Animate(s, 1)
+		case KindSay:
+			if len(s.Choices) > 0 && s.Choices[r.Choice].Goto != "" {
+				r.jump(s.Choices[r.Choice].Goto)
+				continue
+			}
+		case KindGoto:
+			r.jump(s.Label)
+			continue
+		case KindEnd:
+			r.Done = true
+			return
+		}
+		r.goTo(r.Index + 1)
+	}
+	r.Done = true
+}
+
+// goTo は i 番目のステップへ進みます（最後を過ぎたら終わり）
+func (r *Runner) goTo(i int) {
+	r.Index = i
+	r.Started = false
+	r.Time = 0
+	if i >= len(r.Script.Steps) {
+		r.Done = true
+	}
+}
+
+// jump はラベルのステップへ飛びます
+func (r *Runner) jump(label string) {
+	for i, s := range r.Script.Steps {
+		if s.Kind == KindLabel && s.Label == label {
+			r.goTo(i)
+			return
+		}
+	}
+	r.goTo(len(r.Script.Steps))
+}
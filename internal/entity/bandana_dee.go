@@ -0,0 +1,623 @@
+package entity
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+
+	"github.com/remmakoshino/kirby-inspired-go/internal/ability"
+)
+
+// BandanaDeePlayer はプレイアブルキャラクターとしてのバンダナワドルディを表します
+type BandanaDeePlayer struct {
+	Position     pixel.Vec
+	Velocity     pixel.Vec
+	Radius       float64
+	Health       int
+	MaxHealth    int
+	Color        color.RGBA
+	IsAlive      bool
+	IsJumping    bool
+	IsGrounded   bool
+	IsFacingLeft bool
+	
+	// 無敵時間（ダメージ後）
+	InvincibleTime float64
+	
+	// 無敵キャンディ
+	CandyEffect
+	
+	// 穴に落ちた時に戻る位置
+	FallRecovery
+	
+	// レベルから決まる能力値
+	StatBlock
+	
+	// 槍の技
+	CurrentAbility ability.Ability   `json:"-"`
+	Abilities      []ability.Ability `json:"-"`
+	
+	// アニメーション
+	AnimationTime float64
+	
+	// 攻撃関連
+	AttackCooldown float64
+	
+	// 行動状態
+	StateMachine *StateMachine
+	
+	// 投げた槍（ゲーム側が回収する）
+	Projectiles []*Projectile
+	
+	// いる場所の地形の効果（水中・氷・風）
+	Env Environment
+}
+
+const (
+	BandanaDeeRadius         = 18.0
+	BandanaDeeSpeed          = PlayerSpeed * 0.85 // カービィより少し遅い
+	BandanaDeeRunSpeed       = RunSpeed * 0.85
+	BandanaDeeJumpForce      = JumpForce * 0.95
+	BandanaDeeMaxHealth      = 70
+	BandanaDeePokeDuration   = 0.3   // 槍突きのモーション時間
+	BandanaDeePokeRange      = 70.0  // 槍突きのリーチ
+	BandanaDeePokeDamage     = 15    // 槍突きのダメージ
+	BandanaDeeParasolFall    = 90.0  // パラソル中の最大落下速度
+)
+
+// NewBandanaDeePlayer は新しいバンダナワドルディプレイヤーを作成します
+func NewBandanaDeePlayer(startPos pixel.Vec) *BandanaDeePlayer {
+	bd := &BandanaDeePlayer{
+		Position:       startPos,
+		Velocity:       pixel.ZV,
+		Radius:         BandanaDeeRadius,
+		Health:         BandanaDeeMaxHealth,
+		MaxHealth:      BandanaDeeMaxHealth,
+		Color:          color.RGBA{R: 240, G: 150, B: 80, A: 255},
+		IsAlive:        true,
+		IsJumping:      false,
+		IsGrounded:     false,
+		IsFacingLeft:   false,
+		InvincibleTime: 0,
+		AnimationTime:  0,
+		AttackCooldown: 0,
+		StateMachine:   NewStateMachine(PlayerStateFall),
+	}
+	bd.registerStateHooks()
+	
+	// バンダナワドルディ専用の技
+	bd.Abilities = []ability.Ability{
+		ability.NewSpearThrowAbility(),
+		ability.NewSpearCopterAbility(),
+		ability.NewParasolAbility(),
+	}
+	bd.CurrentAbility = bd.Abilities[0] // デフォルトは槍投げ
+	
+	return bd
+}
+
+// registerStateHooks は状態の開始・終了時の処理を登録します
+func (bd *BandanaDeePlayer) registerStateHooks() {
+	sm := bd.StateMachine
+	
+	// 槍突き開始で少し前に踏み込む
+	sm.OnEnter(PlayerStateAttack, func(from, to PlayerState) {
+		bd.AttackCooldown = BandanaDeePokeDuration
+		bd.Velocity.X = bd.facingDir() * BandanaDeeSpeed * 0.5
+	})
+	
+	// のけぞり開始で後ろへ弾かれる
+	sm.OnEnter(PlayerStateHurt, func(from, to PlayerState) {
+		bd.Velocity.X = -bd.facingDir() * HurtKnockback
+		bd.Velocity.Y = HurtKnockback
+	})
+	
+	// 戦闘不能
+	sm.OnEnter(PlayerStateDead, func(from, to PlayerState) {
+		bd.IsAlive = false
+		bd.Velocity = pixel.ZV
+	})
+}
+
+// State は現在の行動状態を返します
+func (bd *BandanaDeePlayer) State() PlayerState {
+	return bd.StateMachine.Current
+}
+
+// Celebrate は勝利ポーズに移行します
+func (bd *BandanaDeePlayer) Celebrate() {
+	bd.StateMachine.Transition(PlayerStateVictory)
+}
+
+// Update はバンダナワドルディの状態を更新します
+func (bd *BandanaDeePlayer) Update(dt float64, input PlayerInput, stageWidth, stageHeight float64) {
+	if !bd.IsAlive {
+		return
+	}
+	
+	bd.AnimationTime += dt
+	bd.StateMachine.Update(dt)
+	
+	// 無敵時間の更新
+	if bd.InvincibleTime > 0 {
+		bd.InvincibleTime -= dt
+	}
+	bd.updateCandy(dt)
+	
+	// 攻撃クールダウン
+	if bd.AttackCooldown > 0 {
+		bd.AttackCooldown -= dt
+	}
+	
+	// 全技のクールダウンと効果時間を進める
+	for _, a := range bd.Abilities {
+		a.Update(dt)
+		bd.hasteCooldown(dt, a)
+	}
+	
+	bd.updateState(input)
+	
+	// 重力適用（スピアコプター・パラソル中は落下速度を抑える）
+	maxFall := MaxFallSpeed
+	gravity := Gravity
+	if copter := bd.activeCopter(); copter != nil && !bd.IsGrounded {
+		// 回転する槍で少しずつ上昇する
+		gravity = 0
+		bd.Velocity.Y = math.Max(bd.Velocity.Y-Gravity*dt, copter.LiftSpeed)
+	} else if bd.parasolOpen() {
+		maxFall = BandanaDeeParasolFall
+		gravity = Gravity * 0.5
+	}
+	gravity = bd.Env.Gravity(gravity)
+	maxFall = bd.Env.MaxFall(maxFall)
+	bd.Velocity.Y -= gravity * dt
+	if bd.Velocity.Y < -maxFall {
+		bd.Velocity.Y = -maxFall
+	}
+	
+	// 位置更新（水中では遅くなり、風に流される）
+	bd.Position = bd.Position.Add(bd.Env.Move(bd.Velocity, dt))
+	
+	// 画面端の処理
+	if bd.Position.X-bd.Radius < 0 {
+		bd.Position.X = bd.Radius
+		bd.Velocity.X = 0
+	} else if bd.Position.X+bd.Radius > stageWidth {
+		bd.Position.X = stageWidth - bd.Radius
+		bd.Velocity.X = 0
+	}
+	
+	// 地面との衝突
+	if bd.Position.Y-bd.Radius <= 0 {
+		bd.Position.Y = bd.Radius
+		bd.Velocity.Y = 0
+		bd.Land()
+	} else {
+		bd.IsGrounded = false
+	}
+	
+	// 天井との衝突
+	if bd.Position.Y+bd.Radius > stageHeight {
+		bd.Position.Y = stageHeight - bd.Radius
+		bd.Velocity.Y = 0
+	}
+	
+	// 画面外に落ちた場合
+	if bd.Position.Y < -100 {
+		bd.TakeDamage(20)
+		bd.Position = bd.respawnPoint(stageWidth)
+		bd.Velocity = pixel.ZV
+	}
+}
+
+// updateState は入力と物理状態から行動状態を遷移させます
+func (bd *BandanaDeePlayer) updateState(input PlayerInput) {
+	sm := bd.StateMachine
+	
+	switch sm.Current {
+	case PlayerStateDead, PlayerStateVictory:
+		bd.Velocity.X = 0
+		return
+	case PlayerStateHurt:
+		if sm.Elapsed < HurtDuration {
+			return
+		}
+	}
+	
+	// 技の切り替え（1: 槍投げ, 2: スピアコプター, 3: パラソル）
+	if input.SwitchAbility > 0 && input.SwitchAbility <= len(bd.Abilities) {
+		bd.CurrentAbility = bd.Abilities[input.SwitchAbility-1]
+	}
+	
+	// 槍突き中はモーションが終わるまで移動しない
+	if sm.Current == PlayerStateAttack && bd.AttackCooldown > 0 {
+		return
+	}
+	
+	// 移動入力
+	moving := true
+	speed := BandanaDeeSpeed
+	if input.Run {
+		speed = BandanaDeeRunSpeed
+	}
+	if input.MoveLeft {
+		bd.Velocity.X = -speed * bd.moveScale()
+		bd.IsFacingLeft = true
+	} else if input.MoveRight {
+		bd.Velocity.X = speed * bd.moveScale()
+		bd.IsFacingLeft = false
+	} else {
+		bd.Velocity.X = bd.Env.Stop(bd.Velocity.X)
+		moving = false
+	}
+	
+	// 技の発動
+	if input.UseAbility {
+		bd.ActivateAbility()
+	}
+	
+	// ジャンプ（水中では空中でも泳げる）
+	if input.Jump && bd.Env.InWater && !bd.IsGrounded {
+		bd.Velocity.Y = SwimStrokeForce
+	}
+	if input.Jump && !bd.IsJumping && bd.IsGrounded {
+		bd.Velocity.Y = BandanaDeeJumpForce * bd.jumpScale()
+		bd.IsJumping = true
+		bd.IsGrounded = false
+		sm.Transition(PlayerStateJump)
+	}
+	
+	// 槍突き
+	if input.Attack && bd.AttackCooldown <= 0 {
+		sm.Transition(PlayerStateAttack)
+		return
+	}
+	
+	airborne := !bd.IsGrounded
+	switch {
+	case airborne && bd.activeCopter() != nil:
+		sm.Transition(PlayerStateFloat)
+	case airborne && bd.Velocity.Y > 0:
+		sm.Transition(PlayerStateJump)
+	case airborne:
+		sm.Transition(PlayerStateFall)
+	case moving && speed == BandanaDeeRunSpeed:
+		sm.Transition(PlayerStateRun)
+	case moving:
+		sm.Transition(PlayerStateWalk)
+	default:
+		sm.Transition(PlayerStateIdle)
+	}
+}
+
+// ActivateAbility は現在の技を発動します
+func (bd *BandanaDeePlayer) ActivateAbility() {
+	if bd.CurrentAbility == nil {
+		return
+	}
+	
+	bd.CurrentAbility.Use(bd)
+	
+	switch ab := bd.CurrentAbility.(type) {
+	case *ability.SpearThrowAbility:
+		// 投げた瞬間に槍を生成する
+		if ab.Thrown {
+			ab.Thrown = false
+			bd.Projectiles = append(bd.Projectiles, NewSpear(bd.Position, bd.facingDir()))
+		}
+		
+	case *ability.SpearCopterAbility:
+		// 地上で使うと軽く飛び上がる
+		if bd.IsGrounded {
+			bd.Velocity.Y = ab.LiftSpeed * 4
+			bd.IsGrounded = false
+		}
+	}
+}
+
+// activeCopter は作動中のスピアコプターを返します（作動していなければ nil）
+func (bd *BandanaDeePlayer) activeCopter() *ability.SpearCopterAbility {
+	for _, a := range bd.Abilities {
+		if copter, ok := a.(*ability.SpearCopterAbility); ok && copter.IsActive {
+			return copter
+		}
+	}
+	return nil
+}
+
+// parasolOpen はパラソルを開いているかどうかを返します
+func (bd *BandanaDeePlayer) parasolOpen() bool {
+	for _, a := range bd.Abilities {
+		if parasol, ok := a.(*ability.ParasolAbility); ok && parasol.IsActive {
+			return true
+		}
+	}
+	return false
+}
+
+// facingDir は向いている方向（右: 1, 左: -1）を返します
+func (bd *BandanaDeePlayer) facingDir() float64 {
+	if bd.IsFacingLeft {
+		return -1.0
+	}
+	return 1.0
+}
+
+// Draw はバンダナワドルディを描画します
+func (bd *BandanaDeePlayer) Draw(imd *imdraw.IMDraw) {
+	if !bd.IsAlive {
+		return
+	}
+	
+	// 無敵時間中は点滅
+	if bd.InvincibleTime > 0 && int(bd.InvincibleTime*10)%2 == 0 {
+		return
+	}
+	bd.drawCandy(imd, bd.Position, bd.Radius)
+	
+	dir := bd.facingDir()
+	bob := 0.0
+	if bd.State() == PlayerStateVictory {
+		bob = math.Abs(math.Sin(bd.AnimationTime*8)) * 8
+	}
+	center := bd.Position.Add(pixel.V(0, bob))
+	
+	// パラソル（頭上に開く）
+	if bd.parasolOpen() {
+		canopy := center.Add(pixel.V(0, bd.Radius*2.2))
+		imd.Color = color.RGBA{R: 150, G: 120, B: 90, A: 255}
+		imd.Push(center.Add(pixel.V(dir*bd.Radius*0.6, 0)), canopy)
+		imd.Line(2)
+		imd.Color = color.RGBA{R: 255, G: 120, B: 150, A: 255}
+		imd.Push(canopy.Add(pixel.V(-bd.Radius*1.6, -bd.Radius*0.5)))
+		imd.Push(canopy.Add(pixel.V(0, bd.Radius*0.6)))
+		imd.Push(canopy.Add(pixel.V(bd.Radius*1.6, -bd.Radius*0.5)))
+		imd.Polygon(0)
+	}
+	
+	// 足（茶色の楕円）
+	imd.Color = color.RGBA{R: 170, G: 80, B: 40, A: 255}
+	for _, side := range []float64{-1, 1} {
+		imd.Push(center.Add(pixel.V(side*bd.Radius*0.5, -bd.Radius*0.85)))
+		imd.Ellipse(pixel.V(bd.Radius*0.45, bd.Radius*0.25), 0)
+	}
+	
+	// 本体（オレンジの球体）
+	imd.Color = bd.Color
+	imd.Push(center)
+	imd.Circle(bd.Radius, 0)
+	
+	// 顔（肌色）
+	imd.Color = color.RGBA{R: 255, G: 220, B: 180, A: 255}
+	imd.Push(center.Add(pixel.V(dir*bd.Radius*0.25, -bd.Radius*0.1)))
+	imd.Ellipse(pixel.V(bd.Radius*0.6, bd.Radius*0.5), 0)
+	
+	// 目
+	imd.Color = color.RGBA{R: 30, G: 30, B: 60, A: 255}
+	for _, side := range []float64{-1, 1} {
+		imd.Push(center.Add(pixel.V(dir*bd.Radius*0.25+side*bd.Radius*0.2, 0)))
+		imd.Ellipse(pixel.V(bd.Radius*0.08, bd.Radius*0.18), 0)
+	}
+	
+	// バンダナ（青）
+	imd.Color = color.RGBA{R: 40, G: 90, B: 200, A: 255}
+	imd.Push(center.Add(pixel.V(-bd.Radius*0.95, bd.Radius*0.45)))
+	imd.Push(center.Add(pixel.V(bd.Radius*0.95, bd.Radius*0.45)))
+	imd.Push(center.Add(pixel.V(bd.Radius*0.8, bd.Radius*0.8)))
+	imd.Push(center.Add(pixel.V(-bd.Radius*0.8, bd.Radius*0.8)))
+	imd.Polygon(0)
+	knot := center.Add(pixel.V(-dir*bd.Radius*0.9, bd.Radius*0.6))
+	imd.Push(knot, knot.Add(pixel.V(-dir*bd.Radius*0.5, -bd.Radius*0.3+math.Sin(bd.AnimationTime*6)*3)))
+	imd.Line(4)
+	
+	// 槍（スピアコプター中は頭上で回転、槍突き中は前へ伸ばす）
+	shaftColor := color.RGBA{R: 160, G: 110, B: 60, A: 255}
+	tipColor := color.RGBA{R: 210, G: 210, B: 230, A: 255}
+	var grip, tip pixel.Vec
+	switch {
+	case bd.activeCopter() != nil:
+		angle := bd.AnimationTime * 25
+		hub := center.Add(pixel.V(0, bd.Radius*1.3))
+		half := pixel.V(math.Cos(angle), math.Sin(angle)*0.3).Scaled(bd.Radius * 1.6)
+		grip, tip = hub.Sub(half), hub.Add(half)
+	case bd.IsAttacking():
+		reach := BandanaDeePokeRange * math.Sin(math.Min(1, bd.StateMachine.Elapsed/BandanaDeePokeDuration)*math.Pi)
+		grip = center.Add(pixel.V(dir*bd.Radius*0.3, -bd.Radius*0.2))
+		tip = grip.Add(pixel.V(dir*math.Max(reach, bd.Radius), 0))
+	default:
+		grip = center.Add(pixel.V(dir*bd.Radius*0.9, -bd.Radius*0.8))
+		tip = grip.Add(pixel.V(dir*bd.Radius*0.2, bd.Radius*2.2))
+	}
+	imd.Color = shaftColor
+	imd.Push(grip, tip)
+	imd.Line(3)
+	imd.Color = tipColor
+	imd.Push(tip)
+	imd.Circle(4, 0)
+}
+
+// TakeDamage はダメージを受けます
+func (bd *BandanaDeePlayer) TakeDamage(damage int) {
+	if bd.InvincibleTime > 0 || bd.HasCandy() || !bd.IsAlive {
+		return
+	}
+	damage = bd.takeDamage(damage)
+	
+	// パラソル中はダメージ半減
+	if bd.parasolOpen() {
+		damage = damage / 2
+	}
+	
+	// 装備の効果で自動ガードした時はダメージを軽減し、のけぞらない
+	guarded := bd.autoGuard()
+	if guarded {
+		damage = damage / GuardDamageDivide
+	}
+	
+	bd.Health -= damage
+	if bd.Health <= 0 {
+		bd.Health = 0
+		bd.StateMachine.Transition(PlayerStateDead)
+		return
+	}
+	
+	// 無敵時間を設定
+	bd.InvincibleTime = 1.2
+	if !guarded {
+		bd.StateMachine.Transition(PlayerStateHurt)
+	}
+}
+
+// Heal は体力を回復します
+func (bd *BandanaDeePlayer) Heal(amount int) {
+	bd.Health += amount
+	if bd.Health > bd.MaxHealth {
+		bd.Health = bd.MaxHealth
+	}
+}
+
+// ApplyStats はレベルから決まる能力値を設定します
+func (bd *BandanaDeePlayer) ApplyStats(s Stats) {
+	bd.applyStats(s, &bd.Health, &bd.MaxHealth)
+}
+
+// Revive は戦闘不能から指定した体力で復帰します
+func (bd *BandanaDeePlayer) Revive(health int) {
+	bd.IsAlive = true
+	bd.Health = int(math.Min(float64(health), float64(bd.MaxHealth)))
+	bd.InvincibleTime = ReviveInvincibleTime
+	bd.StateMachine.Reset(PlayerStateFall)
+}
+
+// Land はジャンプ状態をリセットします（地面に着地した時）
+func (bd *BandanaDeePlayer) Land() {
+	bd.IsJumping = false
+	bd.IsGrounded = true
+	if bd.StateMachine.Is(PlayerStateJump, PlayerStateFall, PlayerStateFloat) {
+		bd.StateMachine.Transition(PlayerStateIdle)
+	}
+}
+
+// IsAttacking は攻撃中かどうかを返します
+func (bd *BandanaDeePlayer) IsAttacking() bool {
+	return bd.State().IsAttacking()
+}
+
+// IsDefeated は戦闘不能かどうかを返します
+func (bd *BandanaDeePlayer) IsDefeated() bool {
+	return !bd.IsAlive
+}
+
+// GetRadius は当たり判定の半径を返します
+func (bd *BandanaDeePlayer) GetRadius() float64 {
+	return bd.Radius
+}
+
+// CanCopyAbility は倒した敵の能力をコピーできるかを返します
+func (bd *BandanaDeePlayer) CanCopyAbility() bool {
+	return false
+}
+
+// TakeProjectiles は投げた槍を取り出します
+func (bd *BandanaDeePlayer) TakeProjectiles() []*Projectile {
+	projectiles := bd.Projectiles
+	bd.Projectiles = nil
+	return projectiles
+}
+
+// DeflectsProjectiles はパラソルで飛び道具を跳ね返せるかを返します
+func (bd *BandanaDeePlayer) DeflectsProjectiles() bool {
+	return bd.parasolOpen()
+}
+
+// Name はキャラクター名を返します
+func (bd *BandanaDeePlayer) Name() string {
+	return "Bandana Dee"
+}
+
+// AbilityLabel はHUDに表示する能力欄の見出しを返します
+func (bd *BandanaDeePlayer) AbilityLabel() string {
+	return "Spear"
+}
+
+// ControlsHelp は操作説明を返します
+func (bd *BandanaDeePlayer) ControlsHelp() string {
+	return "Arrow/WASD: Move  Space: Jump  X: Poke  Z: Spear Skill  1/2/3: Throw/Copter/Parasol  Shift: Run"
+}
+
+// SetEnvironment はいる場所の地形の効果を設定します
+func (bd *BandanaDeePlayer) SetEnvironment(env Environment) {
+	bd.Env = env
+}
+
+// GetBounds は当たり判定用の矩形を返します
+func (bd *BandanaDeePlayer) GetBounds() pixel.Rect {
+	return pixel.R(
+		bd.Position.X-bd.Radius,
+		bd.Position.Y-bd.Radius,
+		bd.Position.X+bd.Radius,
+		bd.Position.Y+bd.Radius,
+	)
+}
+
+// GetPosition は現在位置を返します
+func (bd *BandanaDeePlayer) GetPosition() pixel.Vec {
+	return bd.Position
+}
+
+// SetPosition は位置を設定します
+func (bd *BandanaDeePlayer) SetPosition(pos pixel.Vec) {
+	bd.Position = pos
+}
+
+// GetVelocity は速度を返します
+func (bd *BandanaDeePlayer) GetVelocity() pixel.Vec {
+	return bd.Velocity
+}
+
+// SetVelocity は速度を設定します
+func (bd *BandanaDeePlayer) SetVelocity(vel pixel.Vec) {
+	bd.Velocity = vel
+}
+
+// GetAbility は現在の技を返します
+func (bd *BandanaDeePlayer) GetAbility() ability.Ability {
+	return bd.CurrentAbility
+}
+
+// AbilitySlots は切り替えて使う専用技の一覧を返します
+func (bd *BandanaDeePlayer) AbilitySlots() []ability.Ability {
+	return bd.Abilities
+}
+
+// SetAbility は技を設定します（インターフェース実装）
+func (bd *BandanaDeePlayer) SetAbility(a ability.Ability) {
+	bd.CurrentAbility = a
+}
+
+// GetHealth は現在の体力を返します
+func (bd *BandanaDeePlayer) GetHealth() int {
+	return bd.Health
+}
+
+// GetMaxHealth は最大体力を返します
+func (bd *BandanaDeePlayer) GetMaxHealth() int {
+	return bd.MaxHealth
+}
+
+// GetAttackRange は攻撃範囲を返します
+func (bd *BandanaDeePlayer) GetAttackRange() float64 {
+	return BandanaDeePokeRange
+}
+
+// GetAttackDamage は攻撃力を反映した攻撃ダメージを返します
+func (bd *BandanaDeePlayer) GetAttackDamage() int {
+	return bd.dealDamage(bd.baseAttackDamage())
+}
+
+// baseAttackDamage は技の本来の攻撃ダメージを返します
+func (bd *BandanaDeePlayer) baseAttackDamage() int {
+	return BandanaDeePokeDamage
+}
@@ -3,11 +3,12 @@ package entity
 import (
 	"image/color"
 	"math"
-	"math/rand"
 
 	"github.com/faiface/pixel"
 	"github.com/faiface/pixel/imdraw"
 	"golang.org/x/image/colornames"
+
+	"github.com/remmakoshino/kirby-inspired-go/internal/rng"
 )
 
 // BossType はボスのタイプ
@@ -42,6 +43,15 @@ type Boss struct {
 	// 攻撃パターン
 	AttackPattern  int
 	PhaseLevel     int // 体力に応じたフェーズ
+	
+	// 近接攻撃の連続ヒット防止
+	HitCooldown    float64
+	
+	// 倒した時の経験値を渡し済み
+	Rewarded bool
+	
+	// いる場所の地形の効果（水中・氷・風）
+	Env Environment
 }
 
 // NewDededeBoss はデデデ大王ボスを作成します
@@ -97,6 +107,9 @@ func (b *Boss) Update(dt float64, playerPos pixel.Vec, stageWidth, stageHeight f
 	b.AnimationTime += dt
 	b.AITimer += dt
 	b.AttackTimer += dt
+	if b.HitCooldown > 0 {
+		b.HitCooldown -= dt
+	}
 	
 	// フェーズレベルの更新
 	healthPercent := float64(b.Health) / float64(b.MaxHealth)
@@ -116,14 +129,14 @@ func (b *Boss) Update(dt float64, playerPos pixel.Vec, stageWidth, stageHeight f
 		b.updateMetaKnightAI(dt, playerPos)
 	}
 	
-	// 重力適用
-	b.Velocity.Y -= Gravity * dt
-	if b.Velocity.Y < -MaxFallSpeed {
-		b.Velocity.Y = -MaxFallSpeed
+	// 重力適用（水中では弱まる）
+	b.Velocity.Y -= b.Env.Gravity(Gravity) * dt
+	if maxFall := b.Env.MaxFall(MaxFallSpeed); b.Velocity.Y < -maxFall {
+		b.Velocity.Y = -maxFall
 	}
 	
-	// 位置更新
-	b.Position = b.Position.Add(b.Velocity.Scaled(dt))
+	// 位置更新（水中では遅くなり、風に流される）
+	b.Position = b.Position.Add(b.Env.Move(b.Velocity, dt))
 	
 	// 地面との衝突
 	if b.Position.Y-b.Radius <= 0 {
@@ -149,7 +162,7 @@ func (b *Boss) updateDededeAI(dt float64, playerPos pixel.Vec) {
 	case "idle":
 		if b.AITimer > 1.0 {
 			// ランダムに攻撃パターンを選択
-			pattern := rand.Intn(3)
+			pattern := rng.Intn(3)
 			switch pattern {
 			case 0:
 				b.AIState = "hammer_attack"
@@ -206,7 +219,7 @@ func (b *Boss) updateMetaKnightAI(dt float64, playerPos pixel.Vec) {
 	switch b.AIState {
 	case "idle":
 		if b.AITimer > 0.5 {
-			pattern := rand.Intn(4)
+			pattern := rng.Intn(4)
 			switch pattern {
 			case 0:
 				b.AIState = "sword_combo"
@@ -411,6 +424,21 @@ func (b *Boss) TakeDamage(damage int) {
 	}
 }
 
+// MeleeHit は近接攻撃を受けます。連続ヒット防止中は false を返します
+func (b *Boss) MeleeHit(damage int) bool {
+	if b.HitCooldown > 0 {
+		return false
+	}
+	b.TakeDamage(damage)
+	b.HitCooldown = MeleeHitCooldown
+	return true
+}
+
+// SetEnvironment はいる場所の地形の効果を設定します
+func (b *Boss) SetEnvironment(env Environment) {
+	b.Env = env
+}
+
 // GetBounds は当たり判定用の矩形を返します
 func (b *Boss) GetBounds() pixel.Rect {
 	return pixel.R(
@@ -425,3 +453,77 @@ func (b *Boss) GetBounds() pixel.Rect {
 func (b *Boss) IsAttacking() bool {
 	return b.AIState != "idle" && b.AIState != "cape_defense"
 }
+
+// BossInfo はボスの登録情報です（ボスラッシュやサバイバルのボスの波で使う）
+type BossInfo struct {
+	ID          string
+	Type        BossType
+	DisplayName string // HPバーに出す名前
+	Exp         int    // 倒した時にもらえる経験値
+	Coins       int    // 倒した時にもらえるコイン
+	New         func(pos pixel.Vec) *Boss
+}
+
+// bossInfos は登録順に並んだボスの一覧です（ボスラッシュはこの順に戦う）
+var bossInfos []BossInfo
+
+// RegisterBoss はボスを登録します。同じIDは上書きされます
+func RegisterBoss(info BossInfo) {
+	for i := range bossInfos {
+		if bossInfos[i].ID == info.ID {
+			bossInfos[i] = info
+			return
+		}
+	}
+	bossInfos = append(bossInfos, info)
+}
+
+// BossInfos は登録済みのボスを登録順に返します
+func BossInfos() []BossInfo {
+	return bossInfos
+}
+
+// BossExp はボスのタイプを倒した時の経験値を返します（未登録なら0）
+func BossExp(t BossType) int {
+	for _, info := range bossInfos {
+		if info.Type == t {
+			return info.Exp
+		}
+	}
+	return 0
+}
+
+// BossCoins はボスのタイプを倒した時にもらえるコインを返します（未登録なら0）
+func BossCoins(t BossType) int {
+	for _, info := range bossInfos {
+		if info.Type == t {
+			return info.Coins
+		}
+	}
+	return 0
+}
+
+// BossID はボスのタイプの登録IDを返します（未登録なら空文字列）
+func BossID(t BossType) string {
+	for _, info := range bossInfos {
+		if info.Type == t {
+			return info.ID
+		}
+	}
+	return ""
+}
+
+// BossName はボスのタイプの表示名を返します（未登録なら "Boss"）
+func BossName(t BossType) string {
+	for _, info := range bossInfos {
+		if info.Type == t {
+			return info.DisplayName
+		}
+	}
+	return "Boss"
+}
+
+func init() {
+	RegisterBoss(BossInfo{ID: "Dedede", Type: BossDedede, DisplayName: "King Dedede", Exp: 150, Coins: 100, New: NewDededeBoss})
+	RegisterBoss(BossInfo{ID: "MetaKnight", Type: BossMetaKnight, DisplayName: "Meta Knight", Exp: 200, Coins: 150, New: NewMetaKnightBoss})
+}
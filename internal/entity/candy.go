@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+)
+
+// CandyEffect は無敵キャンディの効果です。キャラクターに埋め込んで使います。
+// 効果中はダメージを受けず、触れた敵を倒します
+type CandyEffect struct {
+	Candy float64 // 無敵キャンディの残り時間
+}
+
+// EatCandy は無敵キャンディの効果を duration 秒間にします
+func (ce *CandyEffect) EatCandy(duration float64) {
+	ce.Candy = math.Max(ce.Candy, duration)
+}
+
+// HasCandy は無敵キャンディの効果中かを返します
+func (ce *CandyEffect) HasCandy() bool {
+	return ce.Candy > 0
+}
+
+// updateCandy は効果の残り時間を減らします
+func (ce *CandyEffect) updateCandy(dt float64) {
+	if ce.Candy > 0 {
+		ce.Candy = math.Max(0, ce.Candy-dt)
+	}
+}
+
+// drawCandy は効果中のキャラクターの周りを回る虹色の星を描画します
+func (ce *CandyEffect) drawCandy(imd *imdraw.IMDraw, pos pixel.Vec, radius float64) {
+	if !ce.HasCandy() {
+		return
+	}
+	// 切れる直前は点滅で知らせる
+	if ce.Candy < ItemBlinkTime && int(ce.Candy*8)%2 == 0 {
+		return
+	}
+	
+	rainbow := []color.RGBA{
+		{R: 255, G: 80, B: 80, A: 220},
+		{R: 255, G: 200, B: 60, A: 220},
+		{R: 90, G: 220, B: 110, A: 220},
+		{R: 80, G: 160, B: 255, A: 220},
+		{R: 200, G: 110, B: 255, A: 220},
+	}
+	for i, c := range rainbow {
+		angle := ce.Candy*6 + float64(i)*2*math.Pi/float64(len(rainbow))
+		imd.Color = c
+		imd.Push(pos.Add(pixel.V(math.Cos(angle), math.Sin(angle)).Scaled(radius + 8)))
+		imd.Circle(4, 0)
+	}
+}
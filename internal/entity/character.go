@@ -0,0 +1,177 @@
+package entity
+
+import (
+	"image/color"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"github.com/remmakoshino/kirby-inspired-go/internal/ability"
+)
+
+// PlayableCharacter はプレイ可能なキャラクターの共通インターフェースです
+type PlayableCharacter interface {
+	ability.AbilityUser
+	
+	// 物理
+	SetPosition(pos pixel.Vec)
+	GetRadius() float64
+	GetBounds() pixel.Rect
+	Land()
+	SetEnvironment(env Environment)
+	SetRespawnPoint(pos pixel.Vec)
+	
+	// 体力
+	GetHealth() int
+	GetMaxHealth() int
+	TakeDamage(damage int)
+	Heal(amount int)
+	IsDefeated() bool
+	Revive(health int)
+	EatCandy(duration float64)
+	HasCandy() bool
+	
+	// 能力値（レベル）
+	GetStats() Stats
+	ApplyStats(s Stats)
+	
+	// 入力と更新
+	Update(dt float64, input PlayerInput, stageWidth, stageHeight float64)
+	State() PlayerState
+	Celebrate()
+	
+	// 能力
+	GetAbility() ability.Ability
+	SetAbility(a ability.Ability)
+	CanCopyAbility() bool
+	
+	// 攻撃
+	IsAttacking() bool
+	GetAttackRange() float64
+	GetAttackDamage() int
+	DeflectsProjectiles() bool
+	TakeProjectiles() []*Projectile
+	
+	// 描画とUI
+	Draw(imd *imdraw.IMDraw)
+	Name() string
+	AbilityLabel() string
+	ControlsHelp() string
+}
+
+// Teleporter は一瞬で離れた位置へ移動する技を持つキャラクターです。
+// 移動先が地形の向こうにならないように、ゲームがステージを見て移動先を直します
+type Teleporter interface {
+	TeleportTarget() (pixel.Vec, bool) // 移動中なら移動先と true
+	SetTeleportTarget(pos pixel.Vec)
+}
+
+// AbilitySlotOwner は切り替えて使う専用技を複数持つキャラクターです
+type AbilitySlotOwner interface {
+	AbilitySlots() []ability.Ability
+}
+
+// CharacterFactory は指定位置にキャラクターを生成する関数です
+type CharacterFactory func(pos pixel.Vec) PlayableCharacter
+
+// CharacterInfo はキャラクター選択画面に並べるキャラクターの情報です
+type CharacterInfo struct {
+	ID          string     // 生成用ID
+	DisplayName string     // 選択画面での表示名
+	Tagline     string     // 特徴の見出し
+	Description string     // 一言説明
+	Color       color.RGBA // 見出しの色
+	Growth      StatGrowth // レベルによる能力値の伸び方
+	New         CharacterFactory
+}
+
+// characters は登録順に並んだキャラクター一覧です
+var characters []CharacterInfo
+
+// RegisterCharacter はキャラクターを登録します。同じIDは上書きされます
+func RegisterCharacter(info CharacterInfo) {
+	for i := range characters {
+		if characters[i].ID == info.ID {
+			characters[i] = info
+			return
+		}
+	}
+	characters = append(characters, info)
+}
+
+// Characters は登録済みキャラクターを登録順に返します
+func Characters() []CharacterInfo {
+	return characters
+}
+
+// LookupCharacter はIDからキャラクター情報を探します
+func LookupCharacter(id string) (CharacterInfo, bool) {
+	for _, info := range characters {
+		if info.ID == id {
+			return info, true
+		}
+	}
+	return CharacterInfo{}, false
+}
+
+// NewPlayableCharacter はIDからキャラクターを生成します。未登録の場合は nil を返します
+func NewPlayableCharacter(id string, pos pixel.Vec) PlayableCharacter {
+	info, ok := LookupCharacter(id)
+	if !ok {
+		return nil
+	}
+	return info.New(pos)
+}
+
+// CharacterIDs は登録済みキャラクターのIDを登録順に返します
+func CharacterIDs() []string {
+	ids := make([]string, 0, len(characters))
+	for _, info := range characters {
+		ids = append(ids, info.ID)
+	}
+	return ids
+}
+
+func init() {
+	RegisterCharacter(CharacterInfo{
+		ID:          "Kirby",
+		DisplayName: "KIRBY",
+		Tagline:     "Copy Ability",
+		Description: "Versatile!",
+		Color:       color.RGBA{R: 255, G: 192, B: 203, A: 255},
+		Growth: StatGrowth{
+			Health: 100, HealthPerLevel: 8,
+			Attack: BaseAttack, AttackPerLevel: 0.5,
+			Defense: 0, DefensePerLevel: 2,
+			Speed: 1, SpeedPerLevel: 0.006,
+		},
+		New:         func(pos pixel.Vec) PlayableCharacter { return NewPlayer(pos) },
+	})
+	RegisterCharacter(CharacterInfo{
+		ID:          "MetaKnight",
+		DisplayName: "META KNIGHT",
+		Tagline:     "Sword Master",
+		Description: "Powerful!",
+		Color:       color.RGBA{R: 147, G: 112, B: 219, A: 255},
+		Growth: StatGrowth{
+			Health: 100, HealthPerLevel: 6,
+			Attack: BaseAttack, AttackPerLevel: 0.7,
+			Defense: 0, DefensePerLevel: 1.5,
+			Speed: 1, SpeedPerLevel: 0.008,
+		},
+		New:         func(pos pixel.Vec) PlayableCharacter { return NewMetaKnightPlayer(pos) },
+	})
+	RegisterCharacter(CharacterInfo{
+		ID:          "BandanaDee",
+		DisplayName: "BANDANA DEE",
+		Tagline:     "Spear Wielder",
+		Description: "Reach & Guard!",
+		Color:       color.RGBA{R: 120, G: 160, B: 255, A: 255},
+		Growth: StatGrowth{
+			Health: BandanaDeeMaxHealth, HealthPerLevel: 7,
+			Attack: BaseAttack, AttackPerLevel: 0.4,
+			Defense: 0, DefensePerLevel: 3,
+			Speed: 1, SpeedPerLevel: 0.005,
+		},
+		New:         func(pos pixel.Vec) PlayableCharacter { return NewBandanaDeePlayer(pos) },
+	})
+}
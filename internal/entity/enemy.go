@@ -3,10 +3,11 @@ package entity
 import (
 	"image/color"
 	"math"
-	"math/rand"
 
 	"github.com/faiface/pixel"
 	"github.com/faiface/pixel/imdraw"
+
+	"github.com/remmakoshino/kirby-inspired-go/internal/rng"
 )
 
 // EnemyType は敵のタイプを表します
@@ -16,6 +17,9 @@ const (
 	EnemyTypeWalker EnemyType = iota // 歩くタイプ
 	EnemyTypeFlyer                   // 飛ぶタイプ
 	EnemyTypeJumper                  // ジャンプするタイプ
+	EnemyTypeHothead                 // 火を吹くタイプ（ファイア能力）
+	EnemyTypeRocky                   // 岩のタイプ（ストーン能力）
+	EnemyTypeBronto                  // 波打ちながらまっすぐ飛ぶタイプ（能力なし）
 )
 
 // Enemy は敵キャラクターを表します
@@ -37,8 +41,25 @@ type Enemy struct {
 	
 	// アニメーション
 	AnimationTime  float64
+	
+	// 近接攻撃の連続ヒット防止
+	HitCooldown    float64
+	
+	// 倒された時のアイテムのドロップを抽選した
+	DropChecked bool
+	
+	// 出てきたスポナーの番号+1（ステージに置かれた敵は 0）
+	Spawner int
+	
+	// いる場所の地形の効果（水中・氷・風）
+	Env Environment
 }
 
+const (
+	StompDamage      = 30  // 踏みつけのダメージ
+	MeleeHitCooldown = 0.3 // 近接攻撃を受けた後の被弾無効時間
+)
+
 // NewEnemy は新しい敵を作成します
 func NewEnemy(pos pixel.Vec, enemyType EnemyType) *Enemy {
 	e := &Enemy{
@@ -67,6 +88,19 @@ func NewEnemy(pos pixel.Vec, enemyType EnemyType) *Enemy {
 	case EnemyTypeJumper:
 		e.Color = color.RGBA{R: 200, G: 200, B: 100, A: 255}
 		e.Radius = 18.0
+	case EnemyTypeHothead:
+		e.Color = color.RGBA{R: 240, G: 120, B: 60, A: 255}
+		e.Radius = 15.0
+	case EnemyTypeRocky:
+		e.Color = color.RGBA{R: 140, G: 130, B: 120, A: 255}
+		e.Radius = 17.0
+		e.Health = 45
+		e.MaxHealth = 45
+	case EnemyTypeBronto:
+		e.Color = color.RGBA{R: 240, G: 140, B: 170, A: 255}
+		e.Radius = 13.0
+		e.Health = 20
+		e.MaxHealth = 20
 	}
 	
 	return e
@@ -80,30 +114,35 @@ func (e *Enemy) Update(dt float64, playerPos pixel.Vec, stageWidth, stageHeight
 	
 	e.AnimationTime += dt
 	e.AITimer += dt
+	if e.HitCooldown > 0 {
+		e.HitCooldown -= dt
+	}
 	
 	// タイプ別のAI
 	switch e.Type {
-	case EnemyTypeWalker:
+	case EnemyTypeWalker, EnemyTypeHothead, EnemyTypeRocky:
 		e.updateWalkerAI(dt, playerPos)
 	case EnemyTypeFlyer:
 		e.updateFlyerAI(dt, playerPos)
 	case EnemyTypeJumper:
 		e.updateJumperAI(dt, playerPos)
+	case EnemyTypeBronto:
+		e.updateBrontoAI(dt)
 	}
 	
 	// 重力適用（飛行タイプ以外）
-	if e.Type != EnemyTypeFlyer {
-		e.Velocity.Y -= Gravity * dt
-		if e.Velocity.Y < -MaxFallSpeed {
-			e.Velocity.Y = -MaxFallSpeed
+	if !e.Flies() {
+		e.Velocity.Y -= e.Env.Gravity(Gravity) * dt
+		if maxFall := e.Env.MaxFall(MaxFallSpeed); e.Velocity.Y < -maxFall {
+			e.Velocity.Y = -maxFall
 		}
 	}
 	
-	// 位置更新
-	e.Position = e.Position.Add(e.Velocity.Scaled(dt))
+	// 位置更新（水中では遅くなり、風に流される）
+	e.Position = e.Position.Add(e.Env.Move(e.Velocity, dt))
 	
 	// 地面との衝突（簡易版）
-	if e.Position.Y-e.Radius <= 0 && e.Type != EnemyTypeFlyer {
+	if e.Position.Y-e.Radius <= 0 && !e.Flies() {
 		e.Position.Y = e.Radius
 		e.Velocity.Y = 0
 	}
@@ -125,7 +164,10 @@ func (e *Enemy) Update(dt float64, playerPos pixel.Vec, stageWidth, stageHeight
 
 // updateWalkerAI は歩行タイプのAIを更新します
 func (e *Enemy) updateWalkerAI(dt float64, playerPos pixel.Vec) {
-	const walkSpeed = 50.0
+	walkSpeed := 50.0
+	if e.Type == EnemyTypeRocky {
+		walkSpeed = 25.0 // 岩は重くて遅い
+	}
 	
 	// パトロール
 	distance := e.Position.X - e.StartPosition.X
@@ -159,6 +201,14 @@ func (e *Enemy) updateFlyerAI(dt float64, playerPos pixel.Vec) {
 	}
 }
 
+// updateBrontoAI は波打ちながら向いている方へまっすぐ飛ぶAIを更新します（プレイヤーは追わない）
+func (e *Enemy) updateBrontoAI(dt float64) {
+	const brontoSpeed = 110.0
+	
+	e.Velocity.X = brontoSpeed * e.MoveDirection
+	e.Velocity.Y = math.Cos(e.AnimationTime*3) * 60
+}
+
 // updateJumperAI はジャンプタイプのAIを更新します
 func (e *Enemy) updateJumperAI(dt float64, playerPos pixel.Vec) {
 	const jumpSpeed = 40.0
@@ -173,7 +223,7 @@ func (e *Enemy) updateJumperAI(dt float64, playerPos pixel.Vec) {
 			e.AITimer = 0
 			
 			// たまに方向転換
-			if rand.Float64() < 0.3 {
+			if rng.Float64() < 0.3 {
 				e.MoveDirection *= -1
 			}
 		}
@@ -194,6 +244,12 @@ func (e *Enemy) Draw(imd *imdraw.IMDraw) {
 		e.drawFlyer(imd)
 	case EnemyTypeJumper:
 		e.drawJumper(imd)
+	case EnemyTypeHothead:
+		e.drawHothead(imd)
+	case EnemyTypeRocky:
+		e.drawRocky(imd)
+	case EnemyTypeBronto:
+		e.drawBronto(imd)
 	}
 }
 
@@ -264,6 +320,66 @@ func (e *Enemy) drawJumper(imd *imdraw.IMDraw) {
 	imd.Circle(e.Radius*0.1, 0)
 }
 
+// drawHothead は火を吹くタイプの敵を描画します
+func (e *Enemy) drawHothead(imd *imdraw.IMDraw) {
+	// 頭の炎（揺らめく）
+	flicker := math.Sin(e.AnimationTime*12) * e.Radius * 0.15
+	imd.Color = color.RGBA{R: 255, G: 200, B: 40, A: 255}
+	imd.Push(
+		pixel.V(e.Position.X-e.Radius*0.6, e.Position.Y+e.Radius*0.5),
+		pixel.V(e.Position.X+e.Radius*0.6, e.Position.Y+e.Radius*0.5),
+		pixel.V(e.Position.X+flicker, e.Position.Y+e.Radius*1.6),
+	)
+	imd.Polygon(0)
+	
+	e.drawWalker(imd)
+}
+
+// drawRocky は岩のタイプの敵を描画します
+func (e *Enemy) drawRocky(imd *imdraw.IMDraw) {
+	// ごつごつした本体
+	imd.Color = e.Color
+	for i := 0; i < 8; i++ {
+		angle := float64(i) * math.Pi / 4
+		r := e.Radius
+		if i%2 == 1 {
+			r *= 0.85
+		}
+		imd.Push(e.Position.Add(pixel.V(math.Cos(angle)*r, math.Sin(angle)*r)))
+	}
+	imd.Polygon(0)
+	
+	// 太い眉と目
+	imd.Color = color.RGBA{R: 50, G: 50, B: 50, A: 255}
+	eyeY := e.Position.Y + e.Radius*0.2
+	imd.Push(pixel.V(e.Position.X-e.Radius*0.6, eyeY+e.Radius*0.3), pixel.V(e.Position.X+e.Radius*0.6, eyeY+e.Radius*0.3))
+	imd.Line(3)
+	imd.Push(pixel.V(e.Position.X-e.Radius*0.35, eyeY))
+	imd.Circle(e.Radius*0.12, 0)
+	imd.Push(pixel.V(e.Position.X+e.Radius*0.35, eyeY))
+	imd.Circle(e.Radius*0.12, 0)
+}
+
+// drawBronto は波打ちながら飛ぶタイプの敵を描画します
+func (e *Enemy) drawBronto(imd *imdraw.IMDraw) {
+	// はばたく白い羽
+	flap := math.Sin(e.AnimationTime*14) * e.Radius * 0.5
+	imd.Color = color.RGBA{R: 250, G: 250, B: 255, A: 255}
+	back := e.Position.Add(pixel.V(-e.MoveDirection*e.Radius*0.3, e.Radius*0.4))
+	imd.Push(back, back.Add(pixel.V(-e.MoveDirection*e.Radius*1.1, e.Radius*0.6+flap)), back.Add(pixel.V(-e.MoveDirection*e.Radius*0.2, e.Radius*0.9+flap)))
+	imd.Polygon(0)
+	
+	// 本体
+	imd.Color = e.Color
+	imd.Push(e.Position)
+	imd.Circle(e.Radius, 0)
+	
+	// 進む方を向いた目
+	imd.Color = color.RGBA{R: 40, G: 40, B: 40, A: 255}
+	imd.Push(e.Position.Add(pixel.V(e.MoveDirection*e.Radius*0.45, e.Radius*0.2)))
+	imd.Circle(e.Radius*0.15, 0)
+}
+
 // TakeDamage はダメージを受けます
 func (e *Enemy) TakeDamage(damage int) {
 	e.Health -= damage
@@ -273,6 +389,26 @@ func (e *Enemy) TakeDamage(damage int) {
 	}
 }
 
+// MeleeHit は近接攻撃を受けます。連続ヒット防止中は false を返します
+func (e *Enemy) MeleeHit(damage int) bool {
+	if e.HitCooldown > 0 {
+		return false
+	}
+	e.TakeDamage(damage)
+	e.HitCooldown = MeleeHitCooldown
+	return true
+}
+
+// SetEnvironment はいる場所の地形の効果を設定します
+func (e *Enemy) SetEnvironment(env Environment) {
+	e.Env = env
+}
+
+// Flies は重力を受けずに飛ぶタイプかを返します
+func (e *Enemy) Flies() bool {
+	return e.Type == EnemyTypeFlyer || e.Type == EnemyTypeBronto
+}
+
 // GetBounds は当たり判定用の矩形を返します
 func (e *Enemy) GetBounds() pixel.Rect {
 	return pixel.R(
@@ -292,7 +428,78 @@ func (e *Enemy) GetAbilityType() string {
 		return "fly"
 	case EnemyTypeJumper:
 		return "jump"
+	case EnemyTypeHothead:
+		return "fire"
+	case EnemyTypeRocky:
+		return "stone"
 	default:
 		return "none"
 	}
 }
+
+// EnemyInfo は敵の登録情報です（サバイバルの波などで出す敵を選ぶのに使う）
+type EnemyInfo struct {
+	ID    string
+	Type  EnemyType
+	Tier  int // 強さの段階（1 から。サバイバルでは波が進むほど高い段階の敵が混ざる）
+	Exp   int // 倒した時にもらえる経験値
+	Coins int // 倒した時に落とすコインの枚数
+}
+
+// enemyInfos は登録順に並んだ敵の一覧です
+var enemyInfos []EnemyInfo
+
+// RegisterEnemy は敵を登録します。同じIDは上書きされます
+func RegisterEnemy(info EnemyInfo) {
+	for i := range enemyInfos {
+		if enemyInfos[i].ID == info.ID {
+			enemyInfos[i] = info
+			return
+		}
+	}
+	enemyInfos = append(enemyInfos, info)
+}
+
+// EnemyInfos は登録済みの敵を登録順に返します
+func EnemyInfos() []EnemyInfo {
+	return enemyInfos
+}
+
+// EnemyExp は敵のタイプを倒した時の経験値を返します（未登録なら0）
+func EnemyExp(t EnemyType) int {
+	for _, info := range enemyInfos {
+		if info.Type == t {
+			return info.Exp
+		}
+	}
+	return 0
+}
+
+// EnemyCoins は敵のタイプを倒した時に落とすコインの枚数を返します（未登録なら0）
+func EnemyCoins(t EnemyType) int {
+	for _, info := range enemyInfos {
+		if info.Type == t {
+			return info.Coins
+		}
+	}
+	return 0
+}
+
+// NewEnemyByID はIDから敵を生成します。未登録の場合は nil を返します
+func NewEnemyByID(id string, pos pixel.Vec) *Enemy {
+	for _, info := range enemyInfos {
+		if info.ID == id {
+			return NewEnemy(pos, info.Type)
+		}
+	}
+	return nil
+}
+
+func init() {
+	RegisterEnemy(EnemyInfo{ID: "Walker", Type: EnemyTypeWalker, Tier: 1, Exp: 5, Coins: 2})
+	RegisterEnemy(EnemyInfo{ID: "Jumper", Type: EnemyTypeJumper, Tier: 1, Exp: 6, Coins: 2})
+	RegisterEnemy(EnemyInfo{ID: "Flyer", Type: EnemyTypeFlyer, Tier: 2, Exp: 10, Coins: 3})
+	RegisterEnemy(EnemyInfo{ID: "Bronto", Type: EnemyTypeBronto, Tier: 2, Exp: 8, Coins: 3})
+	RegisterEnemy(EnemyInfo{ID: "Hothead", Type: EnemyTypeHothead, Tier: 3, Exp: 15, Coins: 5})
+	RegisterEnemy(EnemyInfo{ID: "Rocky", Type: EnemyTypeRocky, Tier: 3, Exp: 15, Coins: 5})
+}
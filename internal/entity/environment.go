@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"math"
+
+	"github.com/faiface/pixel"
+)
+
+// 水中と氷の上の物理
+const (
+	WaterGravityScale = 0.3   // 水中の重力の倍率
+	WaterSpeedScale   = 0.6   // 水中の移動速度の倍率
+	WaterMaxFallSpeed = 90.0  // 水中の最大沈下速度
+	SwimStrokeForce   = 180.0 // 水中でジャンプした時の泳ぎの上昇速度
+	IceFriction       = 0.985 // 氷の上の摩擦（1に近いほど滑る）
+)
+
+// Environment はキャラクターや敵がいる場所の地形の効果です。
+// ゲーム側がステージの水・風・氷から求めて、毎フレーム Update の前に設定します
+type Environment struct {
+	InWater bool
+	OnIce   bool
+	Wind    pixel.Vec // 風に流される速さ（風の外ではゼロ）
+}
+
+// EnvironmentAware は地形の効果を受けるものです
+type EnvironmentAware interface {
+	SetEnvironment(env Environment)
+}
+
+// Apply は状態ごとの物理パラメータに水中と氷の効果を反映します
+func (env Environment) Apply(params MovementParams) MovementParams {
+	if env.InWater {
+		params.Gravity *= WaterGravityScale
+		params.MaxFallSpeed = math.Min(params.MaxFallSpeed, WaterMaxFallSpeed)
+		params.MaxSpeed *= WaterSpeedScale
+		params.Acceleration *= WaterSpeedScale
+	}
+	if env.OnIce {
+		params.Friction = math.Max(params.Friction, IceFriction)
+	}
+	return params
+}
+
+// Gravity は水中なら弱めた重力を返します
+func (env Environment) Gravity(gravity float64) float64 {
+	if env.InWater {
+		return gravity * WaterGravityScale
+	}
+	return gravity
+}
+
+// MaxFall は水中なら抑えた最大落下速度を返します
+func (env Environment) MaxFall(maxFall float64) float64 {
+	if env.InWater {
+		return math.Min(maxFall, WaterMaxFallSpeed)
+	}
+	return maxFall
+}
+
+// Stop は入力がない時の横方向の速度を返します（氷の上では止まらずに滑る）
+func (env Environment) Stop(vx float64) float64 {
+	if env.OnIce {
+		return vx * IceFriction
+	}
+	return 0
+}
+
+// Move は速度から dt 秒間の移動量を返します（水中では横に遅くなり、風に流される）
+func (env Environment) Move(velocity pixel.Vec, dt float64) pixel.Vec {
+	if env.InWater {
+		velocity.X *= WaterSpeedScale
+	}
+	return velocity.Scaled(dt).Add(env.Wind.Scaled(dt))
+}
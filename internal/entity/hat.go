@@ -0,0 +1,94 @@
+package entity
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+)
+
+// 帽子の形
+const (
+	HatCap    = "cap"    // つば付きの帽子
+	HatWizard = "wizard" // とんがり帽子
+	HatCrown  = "crown"  // 王冠
+)
+
+// Hat は装備した帽子の見た目です（ゼロ値なら何もかぶらない）
+type Hat struct {
+	Style string
+	Color color.RGBA
+}
+
+// HatWearer は装備した帽子を頭に描くキャラクターです
+type HatWearer interface {
+	SetHat(h Hat)
+}
+
+// drawHat は体の中心 center、半径 r、潰れ具合 squash のキャラクターの頭に帽子を描画します
+func drawHat(imd *imdraw.IMDraw, h Hat, center pixel.Vec, r, squash float64, facingLeft bool) {
+	if h.Style == "" {
+		return
+	}
+	top := center.Add(pixel.V(0, r*squash*0.8))
+	dir := 1.0
+	if facingLeft {
+		dir = -1
+	}
+	dark := color.RGBA{R: h.Color.R / 2, G: h.Color.G / 2, B: h.Color.B / 2, A: 255}
+	
+	switch h.Style {
+	case HatCap:
+		// 丸い帽子と、向いている方に出たつば
+		imd.Color = h.Color
+		imd.Push(top.Add(pixel.V(0, r*0.1)))
+		imd.Ellipse(pixel.V(r*0.65, r*0.35), 0)
+		imd.Color = dark
+		imd.Push(top.Add(pixel.V(dir*r*0.35, -r*0.05)))
+		imd.Ellipse(pixel.V(r*0.45, r*0.1), 0)
+		
+	case HatWizard:
+		// 少し後ろに傾いたとんがり帽子とつば
+		imd.Color = h.Color
+		imd.Push(
+			top.Add(pixel.V(-r*0.5, 0)),
+			top.Add(pixel.V(r*0.5, 0)),
+			top.Add(pixel.V(-dir*r*0.35, r*1.2)),
+		)
+		imd.Polygon(0)
+		imd.Color = dark
+		imd.Push(top)
+		imd.Ellipse(pixel.V(r*0.75, r*0.12), 0)
+		imd.Color = color.RGBA{R: 255, G: 230, B: 90, A: 255}
+		imd.Push(top.Add(pixel.V(-dir*r*0.08, r*0.55)))
+		imd.Circle(r*0.1, 0)
+		
+	case HatCrown:
+		// 3つの山の王冠と宝石
+		imd.Color = h.Color
+		w := r * 0.5
+		imd.Push(
+			top.Add(pixel.V(-w, 0)),
+			top.Add(pixel.V(w, 0)),
+			top.Add(pixel.V(w, r*0.55)),
+			top.Add(pixel.V(w*0.5, r*0.3)),
+			top.Add(pixel.V(0, r*0.6)),
+			top.Add(pixel.V(-w*0.5, r*0.3)),
+			top.Add(pixel.V(-w, r*0.55)),
+		)
+		imd.Polygon(0)
+		imd.Color = color.RGBA{R: 220, G: 40, B: 60, A: 255}
+		imd.Push(top.Add(pixel.V(0, r*0.15)))
+		imd.Circle(r*0.1, 0)
+		
+	default:
+		// 知らない形はリボンにする
+		imd.Color = h.Color
+		for _, side := range []float64{-1, 1} {
+			angle := math.Pi / 6 * side
+			imd.Push(top.Add(pixel.V(side*r*0.25, r*0.05).Rotated(angle)))
+			imd.Ellipse(pixel.V(r*0.25, r*0.15), 0)
+		}
+	}
+}
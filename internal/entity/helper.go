@@ -0,0 +1,510 @@
+package entity
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+
+	"github.com/remmakoshino/kirby-inspired-go/internal/ability"
+)
+
+// Helper はカービィのコピー能力から生み出された味方キャラクターを表します。
+// 普段は HelperBrain が操作し、2Pが操作を引き継ぐこともできます
+type Helper struct {
+	Position     pixel.Vec
+	Velocity     pixel.Vec
+	Radius       float64
+	Health       int
+	MaxHealth    int
+	Color        color.RGBA
+	IsAlive      bool
+	IsJumping    bool
+	IsGrounded   bool
+	IsFacingLeft bool
+	
+	// 無敵時間（ダメージ後）
+	InvincibleTime float64
+	
+	// 無敵キャンディ
+	CandyEffect
+	
+	// 穴に落ちた時に戻る位置
+	FallRecovery
+	
+	// レベルから決まる能力値
+	StatBlock
+	
+	// 元になったコピー能力
+	CurrentAbility ability.Ability `json:"-"`
+	
+	// アニメーション
+	AnimationTime float64
+	
+	// 攻撃関連
+	AttackCooldown float64
+	
+	// 行動状態
+	StateMachine *StateMachine
+	
+	// いる場所の地形の効果（水中・氷・風）
+	Env Environment
+}
+
+const (
+	HelperRadius         = 17.0
+	HelperSpeed          = PlayerSpeed * 0.9
+	HelperRunSpeed       = RunSpeed * 0.9
+	HelperJumpForce      = JumpForce * 0.95
+	HelperMaxHealth      = 60
+	HelperAttackDuration = 0.3  // 攻撃モーションの長さ
+	HelperAttackRange    = 45.0 // 能力に攻撃範囲がない場合のリーチ
+	HelperAttackDamage   = 12   // 能力に攻撃力がない場合のダメージ
+)
+
+// NewHelper は能力 ab を持つヘルパーを作成します
+func NewHelper(startPos pixel.Vec, ab ability.Ability) *Helper {
+	h := &Helper{
+		Position:       startPos,
+		Velocity:       pixel.ZV,
+		Radius:         HelperRadius,
+		Health:         HelperMaxHealth,
+		MaxHealth:      HelperMaxHealth,
+		Color:          color.RGBA{R: 120, G: 200, B: 120, A: 255},
+		IsAlive:        true,
+		IsFacingLeft:   false,
+		InvincibleTime: ReviveInvincibleTime,
+		CurrentAbility: ab,
+		StateMachine:   NewStateMachine(PlayerStateFall),
+	}
+	if ab != nil {
+		h.Color = ab.GetColor()
+	}
+	h.registerStateHooks()
+	
+	return h
+}
+
+// registerStateHooks は状態の開始・終了時の処理を登録します
+func (h *Helper) registerStateHooks() {
+	sm := h.StateMachine
+	
+	// 攻撃開始で能力を使用
+	sm.OnEnter(PlayerStateAttack, func(from, to PlayerState) {
+		h.AttackCooldown = HelperAttackDuration
+		if h.CurrentAbility != nil {
+			h.CurrentAbility.Use(h)
+		}
+	})
+	
+	// のけぞり開始で後ろへ弾かれる
+	sm.OnEnter(PlayerStateHurt, func(from, to PlayerState) {
+		h.Velocity.X = -h.facingDir() * HurtKnockback
+		h.Velocity.Y = HurtKnockback
+	})
+	
+	// 戦闘不能
+	sm.OnEnter(PlayerStateDead, func(from, to PlayerState) {
+		h.IsAlive = false
+		h.Velocity = pixel.ZV
+	})
+}
+
+// State は現在の行動状態を返します
+func (h *Helper) State() PlayerState {
+	return h.StateMachine.Current
+}
+
+// Celebrate は勝利ポーズに移行します
+func (h *Helper) Celebrate() {
+	h.StateMachine.Transition(PlayerStateVictory)
+}
+
+// Update はヘルパーの状態を更新します
+func (h *Helper) Update(dt float64, input PlayerInput, stageWidth, stageHeight float64) {
+	if !h.IsAlive {
+		return
+	}
+	
+	h.AnimationTime += dt
+	h.StateMachine.Update(dt)
+	
+	// 無敵時間の更新
+	if h.InvincibleTime > 0 {
+		h.InvincibleTime -= dt
+	}
+	h.updateCandy(dt)
+	
+	// 攻撃クールダウン
+	if h.AttackCooldown > 0 {
+		h.AttackCooldown -= dt
+	}
+	
+	// コピー能力のクールダウンと効果時間を進める
+	if h.CurrentAbility != nil {
+		h.CurrentAbility.Update(dt)
+	}
+	
+	h.updateState(input)
+	
+	// 重力適用（水中では弱まる）
+	h.Velocity.Y -= h.Env.Gravity(Gravity) * dt
+	if maxFall := h.Env.MaxFall(MaxFallSpeed); h.Velocity.Y < -maxFall {
+		h.Velocity.Y = -maxFall
+	}
+	
+	// 位置更新（水中では遅くなり、風に流される）
+	h.Position = h.Position.Add(h.Env.Move(h.Velocity, dt))
+	
+	// 画面端の処理
+	if h.Position.X-h.Radius < 0 {
+		h.Position.X = h.Radius
+		h.Velocity.X = 0
+	} else if h.Position.X+h.Radius > stageWidth {
+		h.Position.X = stageWidth - h.Radius
+		h.Velocity.X = 0
+	}
+	
+	// 地面との衝突
+	if h.Position.Y-h.Radius <= 0 {
+		h.Position.Y = h.Radius
+		h.Velocity.Y = 0
+		h.Land()
+	} else {
+		h.IsGrounded = false
+	}
+	
+	// 天井との衝突
+	if h.Position.Y+h.Radius > stageHeight {
+		h.Position.Y = stageHeight - h.Radius
+		h.Velocity.Y = 0
+	}
+	
+	// 画面外に落ちた場合
+	if h.Position.Y < -100 {
+		h.TakeDamage(20)
+		h.Position = h.respawnPoint(stageWidth)
+		h.Velocity = pixel.ZV
+	}
+}
+
+// updateState は入力と物理状態から行動状態を遷移させます
+func (h *Helper) updateState(input PlayerInput) {
+	sm := h.StateMachine
+	
+	switch sm.Current {
+	case PlayerStateDead, PlayerStateVictory:
+		h.Velocity.X = 0
+		return
+	case PlayerStateHurt:
+		if sm.Elapsed < HurtDuration {
+			return
+		}
+	}
+	
+	// 攻撃中はモーションが終わるまで移動しない
+	if sm.Current == PlayerStateAttack && h.AttackCooldown > 0 {
+		return
+	}
+	
+	// 移動入力
+	moving := true
+	speed := HelperSpeed
+	if input.Run {
+		speed = HelperRunSpeed
+	}
+	if input.MoveLeft {
+		h.Velocity.X = -speed * h.moveScale()
+		h.IsFacingLeft = true
+	} else if input.MoveRight {
+		h.Velocity.X = speed * h.moveScale()
+		h.IsFacingLeft = false
+	} else {
+		h.Velocity.X = h.Env.Stop(h.Velocity.X)
+		moving = false
+	}
+	
+	// 能力の発動
+	if input.UseAbility && h.CurrentAbility != nil {
+		h.CurrentAbility.Use(h)
+	}
+	
+	// ジャンプ（水中では空中でも泳げる）
+	if input.Jump && h.Env.InWater && !h.IsGrounded {
+		h.Velocity.Y = SwimStrokeForce
+	}
+	if input.Jump && !h.IsJumping && h.IsGrounded {
+		h.Velocity.Y = HelperJumpForce
+		h.IsJumping = true
+		h.IsGrounded = false
+		sm.Transition(PlayerStateJump)
+	}
+	
+	// 攻撃
+	if input.Attack && h.AttackCooldown <= 0 {
+		sm.Transition(PlayerStateAttack)
+		return
+	}
+	
+	airborne := !h.IsGrounded
+	switch {
+	case airborne && h.Velocity.Y > 0:
+		sm.Transition(PlayerStateJump)
+	case airborne:
+		sm.Transition(PlayerStateFall)
+	case moving && speed == HelperRunSpeed:
+		sm.Transition(PlayerStateRun)
+	case moving:
+		sm.Transition(PlayerStateWalk)
+	default:
+		sm.Transition(PlayerStateIdle)
+	}
+}
+
+// facingDir は向いている方向（右: 1, 左: -1）を返します
+func (h *Helper) facingDir() float64 {
+	if h.IsFacingLeft {
+		return -1.0
+	}
+	return 1.0
+}
+
+// Draw はヘルパーを描画します
+func (h *Helper) Draw(imd *imdraw.IMDraw) {
+	if !h.IsAlive {
+		return
+	}
+	
+	// 無敵時間中は点滅
+	if h.InvincibleTime > 0 && int(h.InvincibleTime*10)%2 == 0 {
+		return
+	}
+	h.drawCandy(imd, h.Position, h.Radius)
+	
+	dir := h.facingDir()
+	bob := math.Sin(h.AnimationTime*6) * 1.5
+	if h.State() == PlayerStateVictory {
+		bob = math.Abs(math.Sin(h.AnimationTime*8)) * 8
+	}
+	center := h.Position.Add(pixel.V(0, bob))
+	
+	// 本体（能力の色の球体）
+	imd.Color = h.Color
+	imd.Push(center)
+	imd.Circle(h.Radius, 0)
+	
+	// 目
+	imd.Color = color.RGBA{R: 30, G: 30, B: 30, A: 255}
+	for _, side := range []float64{-1, 1} {
+		imd.Push(center.Add(pixel.V(dir*h.Radius*0.3+side*h.Radius*0.2, h.Radius*0.2)))
+		imd.Ellipse(pixel.V(h.Radius*0.09, h.Radius*0.2), 0)
+	}
+	
+	// 味方の目印（頭上のハート）
+	heart := center.Add(pixel.V(0, h.Radius+12))
+	imd.Color = color.RGBA{R: 255, G: 120, B: 170, A: 255}
+	imd.Push(heart.Add(pixel.V(-3, 2)))
+	imd.Circle(4, 0)
+	imd.Push(heart.Add(pixel.V(3, 2)))
+	imd.Circle(4, 0)
+	imd.Push(heart.Add(pixel.V(-6.5, 1)), heart.Add(pixel.V(6.5, 1)), heart.Add(pixel.V(0, -6)))
+	imd.Polygon(0)
+	
+	// 攻撃中は前方に衝撃波
+	if h.IsAttacking() {
+		imd.Color = color.RGBA{R: 255, G: 255, B: 255, A: 160}
+		imd.Push(center.Add(pixel.V(dir*h.Radius*1.4, 0)))
+		imd.Circle(h.GetAttackRange()*0.4, 3)
+	}
+}
+
+// TakeDamage はダメージを受けます
+func (h *Helper) TakeDamage(damage int) {
+	if h.InvincibleTime > 0 || h.HasCandy() || !h.IsAlive {
+		return
+	}
+	damage = h.takeDamage(damage)
+	
+	h.Health -= damage
+	if h.Health <= 0 {
+		h.Health = 0
+		h.StateMachine.Transition(PlayerStateDead)
+		return
+	}
+	
+	// 無敵時間を設定
+	h.InvincibleTime = 1.2
+	h.StateMachine.Transition(PlayerStateHurt)
+}
+
+// Heal は体力を回復します
+func (h *Helper) Heal(amount int) {
+	h.Health += amount
+	if h.Health > h.MaxHealth {
+		h.Health = h.MaxHealth
+	}
+}
+
+// ApplyStats はレベルから決まる能力値を設定します
+func (h *Helper) ApplyStats(s Stats) {
+	h.applyStats(s, &h.Health, &h.MaxHealth)
+}
+
+// Revive は戦闘不能から指定した体力で復帰します
+func (h *Helper) Revive(health int) {
+	h.IsAlive = true
+	h.Health = int(math.Min(float64(health), float64(h.MaxHealth)))
+	h.InvincibleTime = ReviveInvincibleTime
+	h.StateMachine.Reset(PlayerStateFall)
+}
+
+// Land はジャンプ状態をリセットします（地面に着地した時）
+func (h *Helper) Land() {
+	h.IsJumping = false
+	h.IsGrounded = true
+	if h.StateMachine.Is(PlayerStateJump, PlayerStateFall) {
+		h.StateMachine.Transition(PlayerStateIdle)
+	}
+}
+
+// IsAttacking は攻撃中かどうかを返します
+func (h *Helper) IsAttacking() bool {
+	return h.State().IsAttacking()
+}
+
+// IsDefeated は戦闘不能かどうかを返します
+func (h *Helper) IsDefeated() bool {
+	return !h.IsAlive
+}
+
+// GetRadius は当たり判定の半径を返します
+func (h *Helper) GetRadius() float64 {
+	return h.Radius
+}
+
+// CanCopyAbility は倒した敵の能力をコピーできるかを返します
+func (h *Helper) CanCopyAbility() bool {
+	return false
+}
+
+// TakeProjectiles は飛び道具を取り出します（ヘルパーは撃たない）
+func (h *Helper) TakeProjectiles() []*Projectile {
+	return nil
+}
+
+// DeflectsProjectiles は飛び道具を跳ね返せるかを返します
+func (h *Helper) DeflectsProjectiles() bool {
+	return false
+}
+
+// Name はキャラクター名を返します
+func (h *Helper) Name() string {
+	if h.CurrentAbility != nil {
+		return h.CurrentAbility.GetName() + " Helper"
+	}
+	return "Helper"
+}
+
+// AbilityLabel はHUDに表示する能力欄の見出しを返します
+func (h *Helper) AbilityLabel() string {
+	return "Helper"
+}
+
+// ControlsHelp は操作説明を返します
+func (h *Helper) ControlsHelp() string {
+	return "Arrow/WASD: Move  Space: Jump  X: Attack  Z: Ability  Shift: Run"
+}
+
+// SetEnvironment はいる場所の地形の効果を設定します
+func (h *Helper) SetEnvironment(env Environment) {
+	h.Env = env
+}
+
+// GetBounds は当たり判定用の矩形を返します
+func (h *Helper) GetBounds() pixel.Rect {
+	return pixel.R(
+		h.Position.X-h.Radius,
+		h.Position.Y-h.Radius,
+		h.Position.X+h.Radius,
+		h.Position.Y+h.Radius,
+	)
+}
+
+// GetPosition は現在位置を返します
+func (h *Helper) GetPosition() pixel.Vec {
+	return h.Position
+}
+
+// SetPosition は位置を設定します
+func (h *Helper) SetPosition(pos pixel.Vec) {
+	h.Position = pos
+}
+
+// GetVelocity は速度を返します
+func (h *Helper) GetVelocity() pixel.Vec {
+	return h.Velocity
+}
+
+// SetVelocity は速度を設定します
+func (h *Helper) SetVelocity(vel pixel.Vec) {
+	h.Velocity = vel
+}
+
+// GetAbility は元になったコピー能力を返します
+func (h *Helper) GetAbility() ability.Ability {
+	return h.CurrentAbility
+}
+
+// SetAbility は能力を設定します（インターフェース実装）
+func (h *Helper) SetAbility(a ability.Ability) {
+	h.CurrentAbility = a
+	if a != nil {
+		h.Color = a.GetColor()
+	}
+}
+
+// GetHealth は現在の体力を返します
+func (h *Helper) GetHealth() int {
+	return h.Health
+}
+
+// GetMaxHealth は最大体力を返します
+func (h *Helper) GetMaxHealth() int {
+	return h.MaxHealth
+}
+
+// GetAttackRange は攻撃範囲を返します
+func (h *Helper) GetAttackRange() float64 {
+	switch ab := h.CurrentAbility.(type) {
+	case *ability.HammerAbility:
+		return ab.AttackRange
+	case *ability.SwordAbility:
+		return ab.AttackRange
+	case *ability.FireAbility:
+		return ab.AttackRange
+	case *ability.StoneAbility:
+		return ab.AttackRange
+	}
+	return HelperAttackRange
+}
+
+// GetAttackDamage は攻撃力を反映した攻撃ダメージを返します
+func (h *Helper) GetAttackDamage() int {
+	return h.dealDamage(h.baseAttackDamage())
+}
+
+// baseAttackDamage は技の本来の攻撃ダメージを返します
+func (h *Helper) baseAttackDamage() int {
+	switch ab := h.CurrentAbility.(type) {
+	case *ability.HammerAbility:
+		return ab.AttackDamage
+	case *ability.SwordAbility:
+		return ab.AttackDamage
+	case *ability.FireAbility:
+		return ab.AttackDamage
+	case *ability.StoneAbility:
+		return ab.AttackDamage
+	}
+	return HelperAttackDamage
+}
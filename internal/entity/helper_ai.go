@@ -0,0 +1,84 @@
+package entity
+
+import (
+	"math"
+
+	"github.com/faiface/pixel"
+)
+
+const (
+	HelperFollowDistance = 70.0  // リーダーからこれ以上離れたら追いかける
+	HelperRunDistance    = 220.0 // これ以上離れたらダッシュで追いかける
+	HelperEngageRange    = 260.0 // この距離内の敵に向かっていく
+	HelperLeashDistance  = 360.0 // リーダーからこれ以上離れてまで敵を追わない
+	HelperAttackInterval = 0.6   // 攻撃の間隔
+	HelperJumpInterval   = 0.8   // ジャンプの間隔
+)
+
+// HelperBrain はヘルパーをCPUで操作する思考ルーチンです。
+// 毎フレーム PlayerInput を組み立てるので、人間の入力と差し替えられます
+type HelperBrain struct {
+	AttackTimer float64
+	JumpTimer   float64
+}
+
+// NewHelperBrain は新しい思考ルーチンを作成します
+func NewHelperBrain() *HelperBrain {
+	return &HelperBrain{}
+}
+
+// Think はリーダーと敵の位置から次の入力を決めます。
+// hasTarget が false の場合は target を無視してリーダーについていきます
+func (b *HelperBrain) Think(dt float64, self PlayableCharacter, leader, target pixel.Vec, hasTarget bool) PlayerInput {
+	input := PlayerInput{}
+	if self.IsDefeated() {
+		return input
+	}
+	
+	if b.AttackTimer > 0 {
+		b.AttackTimer -= dt
+	}
+	if b.JumpTimer > 0 {
+		b.JumpTimer -= dt
+	}
+	
+	pos := self.GetPosition()
+	toLeader := leader.Sub(pos)
+	
+	// 近くに敵がいて、リーダーから離れすぎていなければ戦う
+	engaging := hasTarget &&
+		target.Sub(pos).Len() < HelperEngageRange &&
+		target.Sub(leader).Len() < HelperLeashDistance
+	
+	goal := leader
+	stopDistance := HelperFollowDistance
+	if engaging {
+		goal = target
+		stopDistance = self.GetAttackRange() * 0.6
+	}
+	
+	// 目標に向かって歩く
+	dx := goal.X - pos.X
+	if math.Abs(dx) > stopDistance {
+		input.MoveLeft = dx < 0
+		input.MoveRight = dx > 0
+		input.Run = !engaging && math.Abs(toLeader.X) > HelperRunDistance
+	}
+	
+	// 目標が高い所にいればジャンプ
+	if goal.Y > pos.Y+40 && b.JumpTimer <= 0 {
+		input.Jump = true
+		b.JumpTimer = HelperJumpInterval
+	}
+	
+	// 攻撃範囲に入った敵を攻撃
+	if engaging && b.AttackTimer <= 0 && target.Sub(pos).Len() < self.GetAttackRange()+self.GetRadius() {
+		// 敵の方を向いてから攻撃する
+		input.MoveLeft = dx < 0
+		input.MoveRight = dx > 0
+		input.Attack = true
+		b.AttackTimer = HelperAttackInterval
+	}
+	
+	return input
+}
@@ -0,0 +1,306 @@
+package entity
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+)
+
+// ItemKind はアイテムの種類を表します
+type ItemKind int
+
+const (
+	ItemFood      ItemKind = iota // 食べ物（少し回復）
+	ItemMaxTomato                 // マキシムトマト（全回復）
+	Item1Up                       // 1UP（残り人数が1増える）
+	ItemCandy                     // 無敵キャンディ（しばらく無敵になり、触れた敵を倒す）
+	ItemPointStar                 // ポイントスター（スコアが増える）
+	ItemTreasure                  // 宝袋（中身の装備品や消費アイテムが持ち物に入る）
+	ItemCoin                      // コイン（店で使える）
+)
+
+const (
+	ItemRadius    = 12.0
+	ItemLifetime  = 10.0  // 出現してから消えるまでの時間
+	ItemBlinkTime = 3.0   // 消える前に点滅し始める残り時間
+	ItemBounce    = 0.45  // 着地時の跳ね返り係数
+	ItemFoodHeal  = 20    // 食べ物の回復量
+	ItemCandyTime = 10.0  // 無敵キャンディの効果時間
+	ItemDropPop   = 260.0 // 倒した敵が落としたアイテムが跳ね上がる速さ
+	ItemCoinPop   = 90.0  // 倒した敵が落としたコインが横に飛ぶ速さ（ほかのドロップと重ならないように）
+	
+	PointStarScore = 100 // ポイントスターのスコア
+)
+
+// String はアイテムの種類名を返します（セーブデータの集計に使う）
+func (k ItemKind) String() string {
+	switch k {
+	case ItemFood:
+		return "Food"
+	case ItemMaxTomato:
+		return "MaxTomato"
+	case Item1Up:
+		return "1Up"
+	case ItemCandy:
+		return "Candy"
+	case ItemPointStar:
+		return "PointStar"
+	case ItemTreasure:
+		return "Treasure"
+	case ItemCoin:
+		return "Coin"
+	}
+	return "Unknown"
+}
+
+// Item はステージ上に落ちている拾えるアイテムを表します
+type Item struct {
+	Position pixel.Vec
+	Velocity pixel.Vec
+	Radius   float64
+	Kind     ItemKind
+	Lifetime float64
+	Despawns bool   // 時間で消える（ステージに置かれたアイテムは消えない）
+	IsAlive  bool
+	Treasure string // 宝袋の中身（持ち物のアイテムID）
+	Coins    int    // コインの枚数
+	
+	// アニメーション
+	AnimationTime float64
+}
+
+// NewItem は新しいアイテムを作成します
+func NewItem(pos pixel.Vec, kind ItemKind) *Item {
+	return &Item{
+		Position: pos,
+		Velocity: pixel.ZV,
+		Radius:   ItemRadius,
+		Kind:     kind,
+		Lifetime: ItemLifetime,
+		Despawns: true,
+		IsAlive:  true,
+	}
+}
+
+// NewPlacedItem はステージに置かれた、時間で消えないアイテムを作成します
+func NewPlacedItem(pos pixel.Vec, kind ItemKind) *Item {
+	it := NewItem(pos, kind)
+	it.Despawns = false
+	return it
+}
+
+// NewDroppedItem は倒した敵が落とすアイテムを作成します（跳ね上がってから落ちる）
+func NewDroppedItem(pos pixel.Vec, kind ItemKind) *Item {
+	it := NewItem(pos, kind)
+	it.Velocity = pixel.V(0, ItemDropPop)
+	return it
+}
+
+// Update はアイテムを落下させ、足場の上で跳ねて止まるようにします
+func (it *Item) Update(dt float64, solids []pixel.Rect, stageWidth, stageHeight float64) {
+	if !it.IsAlive {
+		return
+	}
+	
+	it.AnimationTime += dt
+	if it.Despawns {
+		it.Lifetime -= dt
+		if it.Lifetime <= 0 {
+			it.IsAlive = false
+			return
+		}
+	}
+	
+	// 重力
+	it.Velocity.Y -= Gravity * dt
+	if it.Velocity.Y < -MaxFallSpeed {
+		it.Velocity.Y = -MaxFallSpeed
+	}
+	it.Velocity.X *= AirFriction
+	
+	prev := it.Position
+	it.Position = it.Position.Add(it.Velocity.Scaled(dt))
+	
+	// 画面端
+	it.Position.X = math.Max(it.Radius, math.Min(stageWidth-it.Radius, it.Position.X))
+	
+	// 足場（上から落ちてきた場合のみ）と地面
+	floor := it.Radius
+	for _, r := range solids {
+		if it.Position.X < r.Min.X || it.Position.X > r.Max.X {
+			continue
+		}
+		if prev.Y-it.Radius >= r.Max.Y-1 && it.Position.Y-it.Radius < r.Max.Y {
+			floor = math.Max(floor, r.Max.Y+it.Radius)
+		}
+	}
+	if it.Position.Y < floor {
+		it.Position.Y = floor
+		it.Velocity.Y = -it.Velocity.Y * ItemBounce
+		it.Velocity.X *= GroundFriction
+		if it.Velocity.Y < 40 {
+			it.Velocity.Y = 0
+		}
+	}
+}
+
+// Apply はアイテムの効果をキャラクターに与えます。
+// 1UP とポイントスターと宝袋とコインは、残り人数とスコアと持ち物を持つゲーム側が効果を与えます
+func (it *Item) Apply(c PlayableCharacter) {
+	switch it.Kind {
+	case ItemFood:
+		c.Heal(ItemFoodHeal)
+	case ItemMaxTomato:
+		c.Heal(c.GetMaxHealth())
+	case ItemCandy:
+		c.EatCandy(ItemCandyTime)
+	}
+	it.IsAlive = false
+}
+
+// Draw はアイテムを描画します
+func (it *Item) Draw(imd *imdraw.IMDraw) {
+	if !it.IsAlive {
+		return
+	}
+	
+	// 消える直前は点滅
+	if it.Despawns && it.Lifetime < ItemBlinkTime && int(it.Lifetime*8)%2 == 0 {
+		return
+	}
+	
+	bob := pixel.V(0, math.Sin(it.AnimationTime*4)*2)
+	center := it.Position.Add(bob)
+	
+	switch it.Kind {
+	case ItemFood:
+		// リンゴ風の赤い実と葉
+		imd.Color = color.RGBA{R: 230, G: 60, B: 60, A: 255}
+		imd.Push(center)
+		imd.Circle(it.Radius*0.8, 0)
+		imd.Color = color.RGBA{R: 60, G: 170, B: 60, A: 255}
+		imd.Push(center.Add(pixel.V(3, it.Radius*0.8)))
+		imd.Ellipse(pixel.V(it.Radius*0.35, it.Radius*0.2), 0)
+		
+	case ItemMaxTomato:
+		// 「M」マーク付きの大きなトマト
+		imd.Color = color.RGBA{R: 255, G: 50, B: 40, A: 255}
+		imd.Push(center)
+		imd.Circle(it.Radius, 0)
+		imd.Color = color.RGBA{R: 40, G: 150, B: 40, A: 255}
+		imd.Push(center.Add(pixel.V(0, it.Radius*0.9)))
+		imd.Circle(it.Radius*0.35, 0)
+		imd.Color = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+		w := it.Radius * 0.45
+		imd.Push(
+			center.Add(pixel.V(-w, -w)),
+			center.Add(pixel.V(-w, w)),
+			center.Add(pixel.V(0, 0)),
+			center.Add(pixel.V(w, w)),
+			center.Add(pixel.V(w, -w)),
+		)
+		imd.Line(2)
+		
+	case Item1Up:
+		// 緑のカービィの顔
+		imd.Color = color.RGBA{R: 120, G: 220, B: 120, A: 255}
+		imd.Push(center)
+		imd.Circle(it.Radius, 0)
+		imd.Color = color.RGBA{R: 20, G: 20, B: 60, A: 255}
+		imd.Push(center.Add(pixel.V(-it.Radius*0.3, it.Radius*0.25)))
+		imd.Ellipse(pixel.V(it.Radius*0.1, it.Radius*0.25), 0)
+		imd.Push(center.Add(pixel.V(it.Radius*0.3, it.Radius*0.25)))
+		imd.Ellipse(pixel.V(it.Radius*0.1, it.Radius*0.25), 0)
+		imd.Color = color.RGBA{R: 255, G: 120, B: 150, A: 255}
+		imd.Push(center.Add(pixel.V(-it.Radius*0.55, -it.Radius*0.15)))
+		imd.Circle(it.Radius*0.15, 0)
+		imd.Push(center.Add(pixel.V(it.Radius*0.55, -it.Radius*0.15)))
+		imd.Circle(it.Radius*0.15, 0)
+		
+	case ItemCandy:
+		// 渦巻き模様の棒付きキャンディ
+		imd.Color = color.RGBA{R: 240, G: 240, B: 240, A: 255}
+		imd.Push(center, center.Sub(pixel.V(0, it.Radius*1.4)))
+		imd.Line(3)
+		imd.Color = color.RGBA{R: 255, G: 120, B: 200, A: 255}
+		imd.Push(center)
+		imd.Circle(it.Radius*0.8, 0)
+		imd.Color = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+		for i := 0; i < 3; i++ {
+			angle := it.AnimationTime*3 + float64(i)*2*math.Pi/3
+			imd.Push(center.Add(pixel.V(math.Cos(angle), math.Sin(angle)).Scaled(it.Radius * 0.4)))
+			imd.Circle(it.Radius*0.2, 0)
+		}
+		
+	case ItemPointStar:
+		// 回る黄色い星
+		imd.Color = color.RGBA{R: 255, G: 230, B: 60, A: 255}
+		for i := 0; i < 10; i++ {
+			r := it.Radius
+			if i%2 == 1 {
+				r = it.Radius * 0.45
+			}
+			angle := math.Pi/2 + float64(i)*math.Pi/5 + it.AnimationTime*2
+			imd.Push(center.Add(pixel.V(math.Cos(angle)*r, math.Sin(angle)*r)))
+		}
+		imd.Polygon(0)
+		
+	case ItemTreasure:
+		// 金のひもで口を縛った茶色の袋
+		imd.Color = color.RGBA{R: 150, G: 100, B: 50, A: 255}
+		imd.Push(center.Sub(pixel.V(0, it.Radius*0.2)))
+		imd.Circle(it.Radius*0.8, 0)
+		imd.Push(
+			center.Add(pixel.V(-it.Radius*0.35, it.Radius*0.45)),
+			center.Add(pixel.V(it.Radius*0.35, it.Radius*0.45)),
+			center.Add(pixel.V(it.Radius*0.55, it.Radius*0.9)),
+			center.Add(pixel.V(-it.Radius*0.55, it.Radius*0.9)),
+		)
+		imd.Polygon(0)
+		imd.Color = color.RGBA{R: 255, G: 210, B: 60, A: 255}
+		imd.Push(center.Add(pixel.V(-it.Radius*0.4, it.Radius*0.5)), center.Add(pixel.V(it.Radius*0.4, it.Radius*0.5)))
+		imd.Line(3)
+		
+		// 中身があることを知らせるきらめき
+		if int(it.AnimationTime*3)%2 == 0 {
+			imd.Color = color.RGBA{R: 255, G: 255, B: 200, A: 255}
+			imd.Push(center.Add(pixel.V(it.Radius*0.7, it.Radius*0.8)))
+			imd.Circle(2.5, 0)
+		}
+		
+	case ItemCoin:
+		// 回って見える星の刻印の金貨（横幅を縮めて回転を表す）
+		spin := math.Abs(math.Cos(it.AnimationTime * 5))
+		w := it.Radius * 0.8 * math.Max(spin, 0.15)
+		imd.Color = color.RGBA{R: 200, G: 150, B: 30, A: 255}
+		imd.Push(center)
+		imd.Ellipse(pixel.V(w, it.Radius*0.8), 0)
+		imd.Color = color.RGBA{R: 255, G: 215, B: 60, A: 255}
+		imd.Push(center)
+		imd.Ellipse(pixel.V(w*0.75, it.Radius*0.65), 0)
+		if spin > 0.5 {
+			imd.Color = color.RGBA{R: 255, G: 245, B: 170, A: 255}
+			for i := 0; i < 10; i++ {
+				r := it.Radius * 0.4
+				if i%2 == 1 {
+					r = it.Radius * 0.18
+				}
+				angle := math.Pi/2 + float64(i)*math.Pi/5
+				imd.Push(center.Add(pixel.V(math.Cos(angle)*r*spin, math.Sin(angle)*r)))
+			}
+			imd.Polygon(0)
+		}
+	}
+}
+
+// GetBounds は当たり判定用の矩形を返します
+func (it *Item) GetBounds() pixel.Rect {
+	return pixel.R(
+		it.Position.X-it.Radius,
+		it.Position.Y-it.Radius,
+		it.Position.X+it.Radius,
+		it.Position.Y+it.Radius,
+	)
+}
@@ -6,7 +6,6 @@ import (
 
 	"github.com/faiface/pixel"
 	"github.com/faiface/pixel/imdraw"
-	"github.com/faiface/pixel/pixelgl"
 	
 	"github.com/remmakoshino/kirby-inspired-go/internal/ability"
 )
@@ -21,23 +20,59 @@ type MetaKnightPlayer struct {
 	Color         color.RGBA
 	IsAlive       bool
 	IsJumping     bool
-	CanDoubleJump bool
-	HasDoubleJumped bool
+	IsGrounded    bool
+	IsFacingLeft  bool
+	FlapCount     int // 空中で使ったはばたき回数
+	
+	// 無敵時間（ダメージ後・ディメンジョンマント中）
+	InvincibleTime float64
+	
+	// 無敵キャンディ
+	CandyEffect
+	
+	// 穴に落ちた時に戻る位置
+	FallRecovery
+	
+	// レベルから決まる能力値
+	StatBlock
+	
+	// ディメンジョンマント
+	CapeCooldown float64
+	CapeTarget   pixel.Vec
 	
 	// メタナイト専用
-	CurrentAbility ability.Ability
-	Abilities      []ability.Ability
+	CurrentAbility ability.Ability   `json:"-"`
+	Abilities      []ability.Ability `json:"-"`
 	
 	// アニメーション
 	AnimationTime  float64
 	AnimationFrame int
 	
 	// 攻撃関連
-	IsAttacking    bool
 	AttackCooldown float64
 	ComboCount     int
+	ComboTimer     float64 // 攻撃が終わってから次の攻撃でコンボがつながる残り時間
+	
+	// 行動状態
+	StateMachine *StateMachine
+	
+	// いる場所の地形の効果（水中・氷・風）
+	Env Environment
 }
 
+const (
+	MetaKnightAttackDuration = 0.5   // 剣攻撃のモーション時間
+	MetaKnightComboWindow    = 0.4   // 攻撃が終わってから次の攻撃でコンボがつながる時間
+	MetaKnightMaxCombo       = 3     // コンボの段数（最後の段のあとは1段目に戻る）
+	MetaKnightMaxFlaps       = 5     // 空中ではばたける回数
+	MetaKnightFlapForce      = 260.0 // はばたきの上昇力
+	MetaKnightGlideFallSpeed = 70.0  // 滑空中の最大落下速度
+	MetaKnightGlideSpeed     = 260.0 // 滑空中の前進速度
+	DimensionalCapeDistance  = 160.0 // ディメンジョンマントの移動距離
+	DimensionalCapeDuration  = 0.3   // マントに隠れている時間
+	DimensionalCapeCooldown  = 1.5   // ディメンジョンマントの再使用時間
+)
+
 // NewMetaKnightPlayer は新しいメタナイトプレイヤーを作成します
 func NewMetaKnightPlayer(startPos pixel.Vec) *MetaKnightPlayer {
 	mk := &MetaKnightPlayer{
@@ -49,14 +84,18 @@ func NewMetaKnightPlayer(startPos pixel.Vec) *MetaKnightPlayer {
 		Color:           color.RGBA{R: 100, G: 50, B: 150, A: 255},
 		IsAlive:         true,
 		IsJumping:       false,
-		CanDoubleJump:   true,
-		HasDoubleJumped: false,
+		IsGrounded:      false,
+		IsFacingLeft:    false,
+		FlapCount:       0,
+		InvincibleTime:  0,
+		CapeCooldown:    0,
 		AnimationTime:   0,
 		AnimationFrame:  0,
-		IsAttacking:     false,
 		AttackCooldown:  0,
 		ComboCount:      0,
+		StateMachine:    NewStateMachine(PlayerStateFall),
 	}
+	mk.registerStateHooks()
 	
 	// メタナイト専用アビリティ
 	mk.Abilities = []ability.Ability{
@@ -69,76 +108,328 @@ func NewMetaKnightPlayer(startPos pixel.Vec) *MetaKnightPlayer {
 	return mk
 }
 
+// registerStateHooks は状態の開始・終了時の処理を登録します
+func (mk *MetaKnightPlayer) registerStateHooks() {
+	sm := mk.StateMachine
+	
+	// 攻撃開始でコンボを進める（前の攻撃から時間が空いたら1段目から）
+	sm.OnEnter(PlayerStateAttack, func(from, to PlayerState) {
+		mk.AttackCooldown = MetaKnightAttackDuration
+		if mk.ComboTimer <= 0 {
+			mk.ComboCount = 0
+		}
+		mk.ComboCount++
+		if mk.ComboCount > MetaKnightMaxCombo {
+			mk.ComboCount = 1
+		}
+		mk.ComboTimer = 0
+	})
+	sm.OnExit(PlayerStateAttack, func(from, to PlayerState) {
+		mk.ComboTimer = MetaKnightComboWindow
+	})
+	
+	// はばたき開始で上昇
+	sm.OnEnter(PlayerStateFloat, func(from, to PlayerState) {
+		mk.flap()
+	})
+	
+	// ディメンジョンマント：姿を消して無敵になり、終了時に移動先へ現れる
+	// （ダメージ後の無敵時間のほうが長ければ縮めない）
+	sm.OnEnter(PlayerStateTeleport, func(from, to PlayerState) {
+		mk.InvincibleTime = math.Max(mk.InvincibleTime, DimensionalCapeDuration)
+		mk.CapeCooldown = DimensionalCapeCooldown
+		mk.Velocity = pixel.ZV
+	})
+	sm.OnExit(PlayerStateTeleport, func(from, to PlayerState) {
+		mk.Position = mk.CapeTarget
+	})
+	
+	// のけぞり開始で後ろへ弾かれ、コンボが途切れる
+	sm.OnEnter(PlayerStateHurt, func(from, to PlayerState) {
+		mk.Velocity.X = -mk.facingDir() * HurtKnockback
+		mk.Velocity.Y = HurtKnockback
+		mk.ComboCount = 0
+		mk.ComboTimer = 0
+	})
+	
+	// 戦闘不能
+	sm.OnEnter(PlayerStateDead, func(from, to PlayerState) {
+		mk.IsAlive = false
+		mk.Velocity = pixel.ZV
+	})
+}
+
+// State は現在の行動状態を返します
+func (mk *MetaKnightPlayer) State() PlayerState {
+	return mk.StateMachine.Current
+}
+
+// Celebrate は勝利ポーズに移行します
+func (mk *MetaKnightPlayer) Celebrate() {
+	mk.StateMachine.Transition(PlayerStateVictory)
+}
+
 // Update はメタナイトの状態を更新します
-func (mk *MetaKnightPlayer) Update(dt float64, win *pixelgl.Window) {
+func (mk *MetaKnightPlayer) Update(dt float64, input PlayerInput, stageWidth, stageHeight float64) {
 	if !mk.IsAlive {
 		return
 	}
 	
 	mk.AnimationTime += dt
+	mk.StateMachine.Update(dt)
+	
+	// 無敵時間の更新
+	if mk.InvincibleTime > 0 {
+		mk.InvincibleTime -= dt
+	}
+	mk.updateCandy(dt)
 	
-	// 攻撃クールダウン
+	// 攻撃クールダウンとコンボの受付時間
 	if mk.AttackCooldown > 0 {
 		mk.AttackCooldown -= dt
-		if mk.AttackCooldown <= 0 {
-			mk.IsAttacking = false
+	}
+	if mk.ComboTimer > 0 {
+		mk.ComboTimer -= dt
+		if mk.ComboTimer <= 0 {
 			mk.ComboCount = 0
 		}
 	}
+	if mk.CapeCooldown > 0 {
+		mk.CapeCooldown -= dt
+	}
 	
-	// 移動入力
-	if win.Pressed(pixelgl.KeyLeft) || win.Pressed(pixelgl.KeyA) {
-		mk.Velocity.X = -PlayerSpeed
-	} else if win.Pressed(pixelgl.KeyRight) || win.Pressed(pixelgl.KeyD) {
-		mk.Velocity.X = PlayerSpeed
+	// 全アビリティのクールダウンと効果時間を進める
+	for _, a := range mk.Abilities {
+		a.Update(dt)
+		mk.hasteCooldown(dt, a)
+	}
+	
+	mk.updateState(input, stageWidth, stageHeight)
+	
+	// マントに隠れている間は動かない
+	if mk.State() == PlayerStateTeleport {
+		return
+	}
+	
+	// マッハトルネード中は前方へ突進
+	if tornado, ok := mk.CurrentAbility.(*ability.TornadoAbility); ok && tornado.IsActive {
+		mk.Velocity.X = mk.facingDir() * tornado.Speed
+	}
+	
+	// 重力適用（滑空中・はばたき中は落下速度を抑える）
+	maxFall := MaxFallSpeed
+	gravity := Gravity
+	switch mk.State() {
+	case PlayerStateGlide:
+		maxFall = MetaKnightGlideFallSpeed
+		gravity = Gravity * 0.3
+	case PlayerStateFloat:
+		gravity = Gravity * 0.6
+	}
+	gravity = mk.Env.Gravity(gravity)
+	maxFall = mk.Env.MaxFall(maxFall)
+	mk.Velocity.Y -= gravity * dt
+	if mk.Velocity.Y < -maxFall {
+		mk.Velocity.Y = -maxFall
+	}
+	
+	// 位置更新（水中では遅くなり、風に流される）
+	mk.Position = mk.Position.Add(mk.Env.Move(mk.Velocity, dt))
+	
+	// 画面端の処理
+	if mk.Position.X-mk.Radius < 0 {
+		mk.Position.X = mk.Radius
+		mk.Velocity.X = 0
+	} else if mk.Position.X+mk.Radius > stageWidth {
+		mk.Position.X = stageWidth - mk.Radius
+		mk.Velocity.X = 0
+	}
+	
+	// 地面との衝突
+	if mk.Position.Y-mk.Radius <= 0 {
+		mk.Position.Y = mk.Radius
+		mk.Velocity.Y = 0
+		mk.Land()
 	} else {
+		mk.IsGrounded = false
+	}
+	
+	// 天井との衝突
+	if mk.Position.Y+mk.Radius > stageHeight {
+		mk.Position.Y = stageHeight - mk.Radius
+		mk.Velocity.Y = 0
+	}
+	
+	// 画面外に落ちた場合
+	if mk.Position.Y < -100 {
+		mk.TakeDamage(20)
+		mk.Position = mk.respawnPoint(stageWidth)
+		mk.Velocity = pixel.ZV
+	}
+}
+
+// updateState は入力と物理状態から行動状態を遷移させます
+func (mk *MetaKnightPlayer) updateState(input PlayerInput, stageWidth, stageHeight float64) {
+	sm := mk.StateMachine
+	
+	switch sm.Current {
+	case PlayerStateDead, PlayerStateVictory:
 		mk.Velocity.X = 0
+		return
+	case PlayerStateHurt:
+		if sm.Elapsed < HurtDuration {
+			return
+		}
+	case PlayerStateTeleport:
+		if sm.Elapsed < DimensionalCapeDuration {
+			return
+		}
+		sm.Transition(PlayerStateFall)
+	}
+	
+	// 移動入力
+	moving := true
+	speed := PlayerSpeed
+	if input.Run {
+		speed = RunSpeed
+	}
+	if input.MoveLeft {
+		mk.Velocity.X = -speed * mk.moveScale()
+		mk.IsFacingLeft = true
+	} else if input.MoveRight {
+		mk.Velocity.X = speed * mk.moveScale()
+		mk.IsFacingLeft = false
+	} else {
+		mk.Velocity.X = mk.Env.Stop(mk.Velocity.X)
+		moving = false
+	}
+	
+	// アビリティ切り替え（1: 剣, 2: トルネード, 3: マント防御）
+	if input.SwitchAbility > 0 && input.SwitchAbility <= len(mk.Abilities) {
+		mk.CurrentAbility = mk.Abilities[input.SwitchAbility-1]
+	}
+	
+	// アビリティ発動
+	if input.UseAbility {
+		mk.ActivateAbility()
+	}
+	
+	// ディメンジョンマント：入力方向へ短距離ワープ
+	if input.Evade && mk.CapeCooldown <= 0 {
+		dir := pixel.V(mk.facingDir(), 0)
+		if input.Up {
+			dir = pixel.V(0, 1)
+		} else if input.Down {
+			dir = pixel.V(0, -1)
+		}
+		mk.CapeTarget = mk.clampToStage(mk.Position.Add(dir.Scaled(DimensionalCapeDistance)), stageWidth, stageHeight)
+		sm.Transition(PlayerStateTeleport)
+		return
 	}
 	
-	// ジャンプ入力
-	if win.JustPressed(pixelgl.KeySpace) || win.JustPressed(pixelgl.KeyW) {
-		if !mk.IsJumping {
-			mk.Velocity.Y = JumpForce
+	// ジャンプ入力：地上ではジャンプ、空中では回数制限つきのはばたき（水中では泳ぐ）
+	if input.Jump {
+		if mk.Env.InWater && !mk.IsGrounded {
+			mk.Velocity.Y = SwimStrokeForce
+		} else if !mk.IsJumping && mk.IsGrounded {
+			mk.Velocity.Y = JumpForce * mk.jumpScale()
 			mk.IsJumping = true
-			mk.HasDoubleJumped = false
-		} else if mk.CanDoubleJump && !mk.HasDoubleJumped {
-			mk.Velocity.Y = JumpForce
-			mk.HasDoubleJumped = true
+			mk.IsGrounded = false
+			sm.Transition(PlayerStateJump)
+		} else if mk.FlapCount < MetaKnightMaxFlaps {
+			if !sm.Transition(PlayerStateFloat) {
+				mk.flap()
+			}
 		}
 	}
 	
-	// アビリティ切り替え（1, 2, 3キー）
-	if win.JustPressed(pixelgl.Key1) {
-		mk.CurrentAbility = mk.Abilities[0] // 剣
-	} else if win.JustPressed(pixelgl.Key2) {
-		mk.CurrentAbility = mk.Abilities[1] // トルネード
-	} else if win.JustPressed(pixelgl.Key3) {
-		mk.CurrentAbility = mk.Abilities[2] // マント防御
+	// 攻撃中はモーションが終わるまで維持
+	if sm.Current == PlayerStateAttack && mk.AttackCooldown > 0 {
+		return
 	}
 	
-	// 攻撃入力（Eキー）
-	if win.JustPressed(pixelgl.KeyE) && mk.AttackCooldown <= 0 {
-		mk.IsAttacking = true
-		mk.AttackCooldown = 0.5
-		mk.ComboCount++
-		if mk.ComboCount > 3 {
-			mk.ComboCount = 1
+	// 攻撃入力（攻撃の終わりに押したら入り直して次の段へ）
+	if input.Attack && mk.AttackCooldown <= 0 {
+		if sm.Current == PlayerStateAttack {
+			sm.Restart()
+		} else {
+			sm.Transition(PlayerStateAttack)
 		}
+		return
 	}
 	
-	// アビリティ発動（Qキー）
-	if win.JustPressed(pixelgl.KeyQ) {
-		mk.ActivateAbility()
+	// 空中で下入力を押し続けると滑空
+	airborne := !mk.IsGrounded
+	if airborne && input.Down && mk.Velocity.Y <= 0 {
+		mk.Velocity.X = mk.facingDir() * MetaKnightGlideSpeed
+		sm.Transition(PlayerStateGlide)
+		return
 	}
 	
-	// 重力適用
-	mk.Velocity.Y -= Gravity * dt
-	if mk.Velocity.Y < -MaxFallSpeed {
-		mk.Velocity.Y = -MaxFallSpeed
+	switch {
+	case sm.Current == PlayerStateFloat && airborne && mk.Velocity.Y > -MetaKnightFlapForce:
+		// はばたきの勢いが残っている間は飛行状態を維持
+	case airborne && mk.Velocity.Y > 0:
+		sm.Transition(PlayerStateJump)
+	case airborne:
+		sm.Transition(PlayerStateFall)
+	case moving && speed == RunSpeed:
+		sm.Transition(PlayerStateRun)
+	case moving:
+		sm.Transition(PlayerStateWalk)
+	default:
+		sm.Transition(PlayerStateIdle)
 	}
-	
-	// 位置更新
-	mk.Position = mk.Position.Add(mk.Velocity.Scaled(dt))
+}
+
+// flap は羽ばたいて上昇します
+func (mk *MetaKnightPlayer) flap() {
+	mk.Velocity.Y = MetaKnightFlapForce * mk.jumpScale()
+	mk.FlapCount++
+	mk.IsJumping = true
+	mk.IsGrounded = false
+}
+
+// facingDir は向いている方向（右: 1, 左: -1）を返します
+func (mk *MetaKnightPlayer) facingDir() float64 {
+	if mk.IsFacingLeft {
+		return -1.0
+	}
+	return 1.0
+}
+
+// TeleportTarget はディメンジョンマントで隠れている間、現れる位置と true を返します
+func (mk *MetaKnightPlayer) TeleportTarget() (pixel.Vec, bool) {
+	return mk.CapeTarget, mk.State() == PlayerStateTeleport
+}
+
+// SetTeleportTarget はディメンジョンマントで現れる位置を変えます
+func (mk *MetaKnightPlayer) SetTeleportTarget(pos pixel.Vec) {
+	mk.CapeTarget = pos
+}
+
+// clampToStage は位置をステージ内に収めます
+func (mk *MetaKnightPlayer) clampToStage(pos pixel.Vec, stageWidth, stageHeight float64) pixel.Vec {
+	pos.X = math.Max(mk.Radius, math.Min(stageWidth-mk.Radius, pos.X))
+	pos.Y = math.Max(mk.Radius, math.Min(stageHeight-mk.Radius, pos.Y))
+	return pos
+}
+
+// DeflectsProjectiles はマッハトルネードやマントバリアで飛び道具を跳ね返せるかを返します
+func (mk *MetaKnightPlayer) DeflectsProjectiles() bool {
+	for _, a := range mk.Abilities {
+		switch ab := a.(type) {
+		case *ability.TornadoAbility:
+			if ab.IsActive {
+				return true
+			}
+		case *ability.CapeBarrierAbility:
+			if ab.IsActive {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // ActivateAbility は現在のアビリティを発動します
@@ -147,22 +438,19 @@ func (mk *MetaKnightPlayer) ActivateAbility() {
 		return
 	}
 	
+	mk.CurrentAbility.Use(mk)
+	
 	switch mk.CurrentAbility.GetName() {
 	case "Sword":
 		// 剣の特殊攻撃（突進斬り）
-		mk.Velocity.X = 300
-		if mk.Velocity.X < 0 {
-			mk.Velocity.X = -300
-		}
+		mk.Velocity.X = mk.facingDir() * 300
 		
 	case "Tornado":
-		// トルネードダッシュ
-		mk.Velocity.X = 400
+		// トルネードダッシュ（効果中はUpdateで前進を続ける）
 		mk.Velocity.Y = 100
 		
 	case "Cape Barrier":
-		// マント防御（一時的に無敵、実装はゲームロジック側で処理）
-		// ここでは速度を0にして防御姿勢を取る
+		// マント防御（効果中は飛び道具を跳ね返す）
 		mk.Velocity.X = 0
 	}
 }
@@ -173,6 +461,38 @@ func (mk *MetaKnightPlayer) Draw(imd *imdraw.IMDraw) {
 		return
 	}
 	
+	// ディメンジョンマント中は移動先にマントの残像だけを描く
+	if mk.State() == PlayerStateTeleport {
+		progress := mk.StateMachine.Elapsed / DimensionalCapeDuration
+		imd.Color = color.RGBA{R: 150, G: 100, B: 200, A: uint8(200 * progress)}
+		imd.Push(mk.CapeTarget)
+		imd.Circle(mk.Radius*(1.5-progress*0.5), 3)
+		return
+	}
+	
+	// 無敵時間中は点滅
+	if mk.InvincibleTime > 0 && int(mk.InvincibleTime*10)%2 == 0 {
+		return
+	}
+	mk.drawCandy(imd, mk.Position, mk.Radius)
+	
+	// 飛行・滑空中はコウモリのような翼を広げる
+	if mk.StateMachine.Is(PlayerStateFloat, PlayerStateGlide) {
+		wingColor := color.RGBA{R: 80, G: 40, B: 120, A: 255}
+		imd.Color = wingColor
+		beat := math.Sin(mk.AnimationTime*20) * mk.Radius * 0.5
+		if mk.State() == PlayerStateGlide {
+			beat = 0
+		}
+		for _, side := range []float64{-1, 1} {
+			root := mk.Position.Add(pixel.V(side*mk.Radius*0.6, mk.Radius*0.3))
+			imd.Push(root)
+			imd.Push(root.Add(pixel.V(side*mk.Radius*1.4, mk.Radius*0.6+beat)))
+			imd.Push(root.Add(pixel.V(side*mk.Radius*1.1, -mk.Radius*0.2+beat*0.5)))
+			imd.Polygon(0)
+		}
+	}
+	
 	// 本体（紫の球体）
 	imd.Color = mk.Color
 	imd.Push(mk.Position)
@@ -194,12 +514,12 @@ func (mk *MetaKnightPlayer) Draw(imd *imdraw.IMDraw) {
 	imd.Circle(mk.Radius*0.25, 0)
 	
 	// 剣（現在のアビリティが剣の場合、または攻撃中）
-	if mk.CurrentAbility.GetName() == "Sword" || mk.IsAttacking {
+	if mk.CurrentAbility.GetName() == "Sword" || mk.IsAttacking() {
 		swordColor := color.RGBA{R: 180, G: 180, B: 200, A: 255}
 		imd.Color = swordColor
 		
 		swordAngle := 0.0
-		if mk.IsAttacking {
+		if mk.IsAttacking() {
 			// 攻撃アニメーション
 			swordAngle = math.Sin(mk.AnimationTime*20) * math.Pi / 4
 		}
@@ -262,15 +582,33 @@ func (mk *MetaKnightPlayer) Draw(imd *imdraw.IMDraw) {
 
 // TakeDamage はダメージを受けます
 func (mk *MetaKnightPlayer) TakeDamage(damage int) {
+	if mk.InvincibleTime > 0 || mk.HasCandy() || !mk.IsAlive {
+		return
+	}
+	damage = mk.takeDamage(damage)
+	
 	// マント防御中はダメージ軽減
 	if mk.CurrentAbility != nil && mk.CurrentAbility.GetName() == "Cape Barrier" {
 		damage = damage / 3
 	}
 	
+	// 装備の効果で自動ガードした時はダメージを軽減し、のけぞらない
+	guarded := mk.autoGuard()
+	if guarded {
+		damage = damage / GuardDamageDivide
+	}
+	
 	mk.Health -= damage
 	if mk.Health <= 0 {
 		mk.Health = 0
-		mk.IsAlive = false
+		mk.StateMachine.Transition(PlayerStateDead)
+		return
+	}
+	
+	// 無敵時間を設定
+	mk.InvincibleTime = 1.5
+	if !guarded {
+		mk.StateMachine.Transition(PlayerStateHurt)
 	}
 }
 
@@ -282,10 +620,72 @@ func (mk *MetaKnightPlayer) Heal(amount int) {
 	}
 }
 
-// ResetJump はジャンプ状態をリセットします（地面に着地した時）
-func (mk *MetaKnightPlayer) ResetJump() {
+// ApplyStats はレベルから決まる能力値を設定します
+func (mk *MetaKnightPlayer) ApplyStats(s Stats) {
+	mk.applyStats(s, &mk.Health, &mk.MaxHealth)
+}
+
+// Revive は戦闘不能から指定した体力で復帰します
+func (mk *MetaKnightPlayer) Revive(health int) {
+	mk.IsAlive = true
+	mk.Health = int(math.Min(float64(health), float64(mk.MaxHealth)))
+	mk.InvincibleTime = ReviveInvincibleTime
+	mk.StateMachine.Reset(PlayerStateFall)
+}
+
+// Land はジャンプ状態をリセットします（地面に着地した時）
+func (mk *MetaKnightPlayer) Land() {
 	mk.IsJumping = false
-	mk.HasDoubleJumped = false
+	mk.IsGrounded = true
+	mk.FlapCount = 0
+	if mk.StateMachine.Is(PlayerStateJump, PlayerStateFall, PlayerStateFloat, PlayerStateGlide) {
+		mk.StateMachine.Transition(PlayerStateIdle)
+	}
+}
+
+// IsAttacking は攻撃中かどうかを返します
+func (mk *MetaKnightPlayer) IsAttacking() bool {
+	return mk.State().IsAttacking()
+}
+
+// IsDefeated は戦闘不能かどうかを返します
+func (mk *MetaKnightPlayer) IsDefeated() bool {
+	return !mk.IsAlive
+}
+
+// GetRadius は当たり判定の半径を返します
+func (mk *MetaKnightPlayer) GetRadius() float64 {
+	return mk.Radius
+}
+
+// CanCopyAbility は倒した敵の能力をコピーできるかを返します
+func (mk *MetaKnightPlayer) CanCopyAbility() bool {
+	return false
+}
+
+// TakeProjectiles は発射済みの飛び道具を取り出します（メタナイトは撃たない）
+func (mk *MetaKnightPlayer) TakeProjectiles() []*Projectile {
+	return nil
+}
+
+// Name はキャラクター名を返します
+func (mk *MetaKnightPlayer) Name() string {
+	return "Meta Knight"
+}
+
+// AbilityLabel はHUDに表示する能力欄の見出しを返します
+func (mk *MetaKnightPlayer) AbilityLabel() string {
+	return "Weapon"
+}
+
+// ControlsHelp は操作説明を返します
+func (mk *MetaKnightPlayer) ControlsHelp() string {
+	return "Arrow/WASD: Move  Space: Jump/Flap  Down: Glide  E: Attack  Q: Special  F: Cape  1/2/3: Switch"
+}
+
+// SetEnvironment はいる場所の地形の効果を設定します
+func (mk *MetaKnightPlayer) SetEnvironment(env Environment) {
+	mk.Env = env
 }
 
 // GetBounds は当たり判定用の矩形を返します
@@ -323,6 +723,11 @@ func (mk *MetaKnightPlayer) GetAbility() ability.Ability {
 	return mk.CurrentAbility
 }
 
+// AbilitySlots は切り替えて使う専用技の一覧を返します
+func (mk *MetaKnightPlayer) AbilitySlots() []ability.Ability {
+	return mk.Abilities
+}
+
 // SetAbility はアビリティを設定します（インターフェース実装）
 func (mk *MetaKnightPlayer) SetAbility(a ability.Ability) {
 	mk.CurrentAbility = a
@@ -353,8 +758,13 @@ func (mk *MetaKnightPlayer) GetAttackRange() float64 {
 	return 40.0
 }
 
-// GetAttackDamage は攻撃ダメージを返します
+// GetAttackDamage は攻撃力を反映した攻撃ダメージを返します
 func (mk *MetaKnightPlayer) GetAttackDamage() int {
+	return mk.dealDamage(mk.baseAttackDamage())
+}
+
+// baseAttackDamage は技の本来の攻撃ダメージを返します
+func (mk *MetaKnightPlayer) baseAttackDamage() int {
 	if mk.CurrentAbility != nil {
 		switch mk.CurrentAbility.GetName() {
 		case "Sword":
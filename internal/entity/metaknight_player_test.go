@@ -0,0 +1,110 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/faiface/pixel"
+)
+
+// groundedMetaKnight は地面に立っている剣のメタナイトを作ります
+func groundedMetaKnight(t *testing.T) *MetaKnightPlayer {
+	t.Helper()
+	mk := NewMetaKnightPlayer(pixel.V(200, 100))
+	for i := 0; i < 120 && !mk.IsGrounded; i++ {
+		mk.Update(testDT, PlayerInput{}, testStageWidth, testStageHeight)
+	}
+	if !mk.IsGrounded {
+		t.Fatalf("Meta Knight did not land: %v", mk.Position)
+	}
+	if mk.CurrentAbility == nil || mk.CurrentAbility.GetName() != "Sword" {
+		t.Fatalf("ability = %v, want Sword", mk.CurrentAbility)
+	}
+	return mk
+}
+
+// slash は攻撃を押し、その攻撃のダメージを返します。then フレームのあいだ何も押さずに進めます
+func slash(t *testing.T, mk *MetaKnightPlayer, then int) int {
+	t.Helper()
+	mk.Update(testDT, PlayerInput{Attack: true}, testStageWidth, testStageHeight)
+	if !mk.IsAttacking() {
+		t.Fatalf("state = %v after pressing attack, want Attack", mk.State())
+	}
+	damage := mk.GetAttackDamage()
+	for i := 0; i < then; i++ {
+		mk.Update(testDT, PlayerInput{}, testStageWidth, testStageHeight)
+	}
+	return damage
+}
+
+// 攻撃のモーションが終わるまでのフレーム数
+var slashFrames = int(MetaKnightAttackDuration/testDT) + 1
+
+func TestMetaKnightComboRaisesDamage(t *testing.T) {
+	tests := []struct {
+		name string
+		wait int // 攻撃のモーションが終わってから次に押すまでのフレーム数
+	}{
+		{name: "pressed as the slash ends", wait: 0},
+		{name: "pressed within the combo window", wait: int(MetaKnightComboWindow/testDT) / 2},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mk := groundedMetaKnight(t)
+			var damages []int
+			for i := 0; i < MetaKnightMaxCombo; i++ {
+				damages = append(damages, slash(t, mk, slashFrames+tt.wait))
+			}
+			
+			for i := 1; i < len(damages); i++ {
+				if damages[i] <= damages[i-1] {
+					t.Fatalf("damages = %v, want each slash of the combo to hit harder", damages)
+				}
+			}
+			
+			// 最後の段のあとは1段目に戻る
+			if d := slash(t, mk, 0); d != damages[0] {
+				t.Errorf("slash after the finisher = %d, want %d", d, damages[0])
+			}
+		})
+	}
+}
+
+func TestMetaKnightComboBreaks(t *testing.T) {
+	tests := []struct {
+		name  string
+		after func(t *testing.T, mk *MetaKnightPlayer)
+	}{
+		{name: "timeout", after: func(t *testing.T, mk *MetaKnightPlayer) {
+			for i := 0; i < int(MetaKnightComboWindow/testDT)+2; i++ {
+				mk.Update(testDT, PlayerInput{}, testStageWidth, testStageHeight)
+			}
+		}},
+		{name: "hurt", after: func(t *testing.T, mk *MetaKnightPlayer) {
+			// のけぞりはコンボの受付時間より短いこともあるので、その場で途切れたかを見る
+			mk.TakeDamage(1)
+			if mk.ComboCount != 0 {
+				t.Errorf("ComboCount = %d after being hurt, want 0", mk.ComboCount)
+			}
+			for i := 0; i < int(HurtDuration/testDT)+30; i++ {
+				mk.Update(testDT, PlayerInput{}, testStageWidth, testStageHeight)
+			}
+		}},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mk := groundedMetaKnight(t)
+			first := slash(t, mk, slashFrames)
+			if second := slash(t, mk, slashFrames); second <= first {
+				t.Fatalf("second slash = %d, want more than %d", second, first)
+			}
+			
+			tt.after(t, mk)
+			
+			if d := slash(t, mk, 0); d != first {
+				t.Errorf("slash after the combo broke = %d, want %d", d, first)
+			}
+		})
+	}
+}
@@ -17,7 +17,6 @@ const (
 	MaxFallSpeed      = 500.0
 	GroundFriction    = 0.85
 	AirFriction       = 0.95
-	MaxJumps          = 2 // ダブルジャンプ可能
 )
 
 // Player はプレイヤーキャラクターを表します
@@ -28,36 +27,94 @@ type Player struct {
 	Health       int
 	MaxHealth    int
 	IsGrounded   bool
-	JumpCount    int
 	IsFacingLeft bool
 	
+	// 行動状態
+	StateMachine *StateMachine
+	
 	// コピー能力関連
-	CurrentAbility ability.Ability
+	CurrentAbility ability.Ability `json:"-"`
 	
 	// アニメーション関連
-	AnimationState string
-	AnimationTime  float64
+	AnimationTime float64
 	
 	// 無敵時間（ダメージ後）
 	InvincibleTime float64
+	
+	// 無敵キャンディ
+	CandyEffect
+	
+	// 穴に落ちた時に戻る位置
+	FallRecovery
+	
+	// レベルから決まる能力値
+	StatBlock
+	
+	// 装備した帽子
+	Hat Hat
+	
+	// 発射した飛び道具（ゲーム側が回収する）
+	Projectiles []*Projectile
+	
+	// いる場所の地形の効果（水中・氷・風）
+	Env Environment
 }
 
 // NewPlayer は新しいプレイヤーを作成します
 func NewPlayer(startPos pixel.Vec) *Player {
-	return &Player{
+	p := &Player{
 		Position:       startPos,
 		Velocity:       pixel.ZV,
 		Radius:         PlayerRadius,
 		Health:         100,
 		MaxHealth:      100,
 		IsGrounded:     false,
-		JumpCount:      0,
 		IsFacingLeft:   false,
+		StateMachine:   NewStateMachine(PlayerStateFall),
 		CurrentAbility: nil,
-		AnimationState: "idle",
 		AnimationTime:  0,
 		InvincibleTime: 0,
 	}
+	p.registerStateHooks()
+	
+	return p
+}
+
+// registerStateHooks は状態の開始・終了時の処理を登録します
+func (p *Player) registerStateHooks() {
+	sm := p.StateMachine
+	
+	// ほおばり開始ではばたく（空気弾は updateState で自分から吐き出した時だけ出る）
+	sm.OnEnter(PlayerStateFloat, func(from, to PlayerState) {
+		p.Velocity.Y = FloatFlapForce * p.jumpScale()
+	})
+	
+	// スライディング開始で前方へ加速
+	sm.OnEnter(PlayerStateSlide, func(from, to PlayerState) {
+		p.Velocity.X = p.facingDir() * SlideSpeed
+	})
+	
+	// 攻撃開始で能力を使用（能力がなければ水中では水鉄砲を吐く）
+	sm.OnEnter(PlayerStateAttack, func(from, to PlayerState) {
+		if p.CurrentAbility != nil {
+			p.CurrentAbility.Use(p)
+		} else if p.Env.InWater {
+			p.spit()
+		}
+	})
+	
+	// のけぞり開始で後ろへ弾かれる
+	sm.OnEnter(PlayerStateHurt, func(from, to PlayerState) {
+		p.Velocity.X = -p.facingDir() * HurtKnockback
+		p.Velocity.Y = HurtKnockback
+		p.IsGrounded = false
+	})
+	
+	// 戦闘不能で停止
+	sm.OnEnter(PlayerStateDead, func(from, to PlayerState) {
+		p.Velocity = pixel.V(0, JumpForce*0.5)
+		p.InvincibleTime = 0
+	})
 }
 
 // Update はプレイヤーの状態を更新します
@@ -66,62 +123,61 @@ func (p *Player) Update(dt float64, input PlayerInput, stageWidth, stageHeight f
 	if p.InvincibleTime > 0 {
 		p.InvincibleTime -= dt
 	}
+	p.updateCandy(dt)
 	
 	// アニメーション時間の更新
 	p.AnimationTime += dt
 	
-	// 左右移動
-	if input.MoveLeft {
-		p.Velocity.X -= PlayerSpeed * dt * 10
-		p.IsFacingLeft = true
-		p.AnimationState = "walk"
-	} else if input.MoveRight {
-		p.Velocity.X += PlayerSpeed * dt * 10
-		p.IsFacingLeft = false
-		p.AnimationState = "walk"
-	} else if p.IsGrounded {
-		p.AnimationState = "idle"
-	}
-	
-	// ジャンプ
-	if input.Jump && p.JumpCount < MaxJumps {
-		p.Velocity.Y = JumpForce
-		p.JumpCount++
-		p.IsGrounded = false
-		p.AnimationState = "jump"
+	// コピー能力のクールダウンと効果時間を進める
+	if p.CurrentAbility != nil {
+		p.CurrentAbility.Update(dt)
+		p.hasteCooldown(dt, p.CurrentAbility)
 	}
 	
-	// 攻撃（能力使用）
-	if input.Attack && p.CurrentAbility != nil {
-		p.CurrentAbility.Use(p)
+	// 入力と物理状態から行動状態を決定
+	p.StateMachine.Update(dt)
+	p.updateState(input)
+	params := p.Env.Apply(ParamsForState(p.State()))
+	params.MaxSpeed *= p.moveScale()
+	params.Acceleration *= p.moveScale()
+	
+	// 左右移動（しゃがみ・スライディング・ガード中は入力を受け付けない）
+	if params.Acceleration > 0 {
+		if input.MoveLeft {
+			p.Velocity.X -= params.Acceleration * dt
+			p.IsFacingLeft = true
+		} else if input.MoveRight {
+			p.Velocity.X += params.Acceleration * dt
+			p.IsFacingLeft = false
+		}
 	}
 	
 	// 重力適用
 	if !p.IsGrounded {
-		p.Velocity.Y -= Gravity * dt
-		if p.Velocity.Y < -MaxFallSpeed {
-			p.Velocity.Y = -MaxFallSpeed
+		p.Velocity.Y -= params.Gravity * dt
+		if p.Velocity.Y < -params.MaxFallSpeed {
+			p.Velocity.Y = -params.MaxFallSpeed
 		}
 	}
 	
 	// 摩擦適用
-	if p.IsGrounded {
-		p.Velocity.X *= GroundFriction
+	if p.IsGrounded || !p.State().IsAirborne() {
+		p.Velocity.X *= params.Friction
 	} else {
 		p.Velocity.X *= AirFriction
 	}
 	
 	// 速度制限
-	if math.Abs(p.Velocity.X) > PlayerSpeed {
+	if math.Abs(p.Velocity.X) > params.MaxSpeed {
 		if p.Velocity.X > 0 {
-			p.Velocity.X = PlayerSpeed
+			p.Velocity.X = params.MaxSpeed
 		} else {
-			p.Velocity.X = -PlayerSpeed
+			p.Velocity.X = -params.MaxSpeed
 		}
 	}
 	
-	// 位置更新
-	p.Position = p.Position.Add(p.Velocity.Scaled(dt))
+	// 位置更新（風に流される）
+	p.Position = p.Position.Add(p.Velocity.Scaled(dt)).Add(p.Env.Wind.Scaled(dt))
 	
 	// 画面端の処理
 	if p.Position.X-p.Radius < 0 {
@@ -136,8 +192,7 @@ func (p *Player) Update(dt float64, input PlayerInput, stageWidth, stageHeight f
 	if p.Position.Y-p.Radius <= 0 {
 		p.Position.Y = p.Radius
 		p.Velocity.Y = 0
-		p.IsGrounded = true
-		p.JumpCount = 0
+		p.Land()
 	} else {
 		p.IsGrounded = false
 	}
@@ -151,17 +206,182 @@ func (p *Player) Update(dt float64, input PlayerInput, stageWidth, stageHeight f
 	// 画面外に落ちた場合
 	if p.Position.Y < -100 {
 		p.TakeDamage(20)
-		p.Position = pixel.V(stageWidth/2, 200)
+		p.Position = p.respawnPoint(stageWidth)
 		p.Velocity = pixel.ZV
+		p.StateMachine.Transition(PlayerStateFall)
+	}
+}
+
+// updateState は入力と物理状態から行動状態を遷移させます
+func (p *Player) updateState(input PlayerInput) {
+	sm := p.StateMachine
+	
+	switch sm.Current {
+	case PlayerStateDead, PlayerStateVictory:
+		return
+		
+	case PlayerStateHurt:
+		if sm.Elapsed < HurtDuration {
+			return
+		}
+		
+	case PlayerStateAttack:
+		if sm.Elapsed < AttackDuration {
+			return
+		}
+		
+	case PlayerStateFloat:
+		// ほおばり中はジャンプではばたき、攻撃か下入力か着地で空気弾を吐き出す
+		// 水に入ると空気が抜けるだけでほおばりをやめる
+		if p.Env.InWater {
+			sm.Transition(PlayerStateFall)
+			return
+		}
+		if input.Jump {
+			p.Velocity.Y = FloatFlapForce * p.jumpScale()
+		}
+		if !input.Attack && !input.Down && !p.IsGrounded {
+			return
+		}
+		p.spit()
+		if p.IsGrounded {
+			sm.Transition(PlayerStateIdle)
+		} else {
+			sm.Transition(PlayerStateFall)
+		}
+		return
+		
+	case PlayerStateSlide:
+		if sm.Elapsed < SlideDuration && p.IsGrounded {
+			return
+		}
+		
+	case PlayerStateGuard:
+		if input.Guard && p.IsGrounded {
+			return
+		}
+		
+	case PlayerStateCrouch:
+		// しゃがみ中のジャンプでスライディング
+		if input.Jump && p.IsGrounded {
+			sm.Transition(PlayerStateSlide)
+			return
+		}
+		if input.Down && p.IsGrounded {
+			return
+		}
+	}
+	
+	if p.IsGrounded {
+		moving := input.MoveLeft || input.MoveRight
+		switch {
+		case input.Guard:
+			sm.Transition(PlayerStateGuard)
+		case input.Down:
+			sm.Transition(PlayerStateCrouch)
+		case input.Jump:
+			p.Velocity.Y = JumpForce * p.jumpScale()
+			p.IsGrounded = false
+			sm.Transition(PlayerStateJump)
+		case input.Attack:
+			sm.Transition(PlayerStateAttack)
+		case moving && input.Run:
+			sm.Transition(PlayerStateRun)
+		case moving:
+			sm.Transition(PlayerStateWalk)
+		default:
+			sm.Transition(PlayerStateIdle)
+		}
+		return
+	}
+	
+	// 水中ではジャンプで泳ぐ（ほおばれない）
+	if p.Env.InWater {
+		if input.Jump {
+			p.Velocity.Y = SwimStrokeForce
+		}
+		if input.Attack {
+			sm.Transition(PlayerStateAttack)
+			return
+		}
+	}
+	
+	// 空中でのジャンプはほおばり飛行
+	if input.Jump && sm.Current != PlayerStateFloat {
+		sm.Transition(PlayerStateFloat)
+		return
+	}
+	if input.Attack && sm.Current != PlayerStateFloat {
+		sm.Transition(PlayerStateAttack)
+		return
+	}
+	
+	if p.Velocity.Y > 0 {
+		sm.Transition(PlayerStateJump)
+	} else {
+		sm.Transition(PlayerStateFall)
+	}
+}
+
+// spit はほおばった空気を吐き出します（水中では水鉄砲になる）
+func (p *Player) spit() {
+	if p.Env.InWater {
+		p.Projectiles = append(p.Projectiles, NewWaterSpit(p.Position, p.IsFacingLeft))
+		return
+	}
+	p.Projectiles = append(p.Projectiles, NewAirPuff(p.Position, p.IsFacingLeft))
+}
+
+// SetEnvironment はいる場所の地形の効果を設定します
+func (p *Player) SetEnvironment(env Environment) {
+	p.Env = env
+}
+
+// facingDir は向いている方向（右: 1, 左: -1）を返します
+func (p *Player) facingDir() float64 {
+	if p.IsFacingLeft {
+		return -1.0
+	}
+	return 1.0
+}
+
+// State は現在の行動状態を返します
+func (p *Player) State() PlayerState {
+	return p.StateMachine.Current
+}
+
+// Celebrate は勝利ポーズに移行します
+func (p *Player) Celebrate() {
+	p.StateMachine.Transition(PlayerStateVictory)
+}
+
+// Land は地面やプラットフォームに着地した時に呼ばれます
+func (p *Player) Land() {
+	p.IsGrounded = true
+	if p.StateMachine.Is(PlayerStateJump, PlayerStateFall) {
+		p.StateMachine.Transition(PlayerStateIdle)
 	}
 }
 
+// TakeProjectiles は発射済みの飛び道具を取り出します
+func (p *Player) TakeProjectiles() []*Projectile {
+	projectiles := p.Projectiles
+	p.Projectiles = nil
+	return projectiles
+}
+
+// IsSliding はスライディング中かどうかを返します
+func (p *Player) IsSliding() bool {
+	return p.State() == PlayerStateSlide
+}
+
 // Draw はプレイヤーを描画します（カービィ風のピンクキャラクター）
 func (p *Player) Draw(imd *imdraw.IMDraw) {
 	// 無敵時間中は点滅
 	if p.InvincibleTime > 0 && int(p.InvincibleTime*10)%2 == 0 {
 		return
 	}
+	p.drawCandy(imd, p.Position, p.Radius)
 	
 	// 体の色（ピンク）
 	bodyColor := color.RGBA{R: 255, G: 182, B: 193, A: 255}
@@ -169,64 +389,126 @@ func (p *Player) Draw(imd *imdraw.IMDraw) {
 		bodyColor = p.CurrentAbility.GetColor()
 	}
 	
+	// 状態に応じた体の大きさと潰れ具合
+	r := p.Radius
+	squash := 1.0
+	stretch := 1.0
+	center := p.Position
+	switch p.State() {
+	case PlayerStateFloat:
+		// ほおばって膨らむ
+		r = p.Radius * 1.3
+	case PlayerStateCrouch, PlayerStateSlide:
+		// しゃがんで潰れる
+		squash = 0.6
+		stretch = 1.25
+		center = p.Position.Add(pixel.V(0, -p.Radius*0.4))
+	case PlayerStateHurt, PlayerStateDead:
+		// のけぞって少し潰れる
+		squash = 0.85
+	case PlayerStateVictory:
+		// 勝利ポーズで跳ねる
+		center = p.Position.Add(pixel.V(0, math.Abs(math.Sin(p.AnimationTime*6))*p.Radius*0.5))
+	}
+	
 	// 本体（丸い体）
 	imd.Color = bodyColor
-	imd.Push(p.Position)
-	imd.Circle(p.Radius, 0)
+	imd.Push(center)
+	imd.Ellipse(pixel.V(r*stretch, r*squash), 0)
 	
 	// 足（小さな楕円）
 	footColor := color.RGBA{R: 180, G: 60, B: 80, A: 255}
 	imd.Color = footColor
 	
-	leftFootPos := p.Position.Add(pixel.V(-p.Radius*0.4, -p.Radius*0.8))
-	rightFootPos := p.Position.Add(pixel.V(p.Radius*0.4, -p.Radius*0.8))
+	leftFootPos := center.Add(pixel.V(-r*0.4, -r*0.8*squash))
+	rightFootPos := center.Add(pixel.V(r*0.4, -r*0.8*squash))
+	if p.State() == PlayerStateSlide {
+		// スライディング中は前に足を突き出す
+		kick := r * 1.1
+		if p.IsFacingLeft {
+			kick = -kick
+		}
+		rightFootPos = center.Add(pixel.V(kick, -r*0.5*squash))
+	}
 	
 	imd.Push(leftFootPos)
-	imd.Circle(p.Radius*0.3, 0)
+	imd.Circle(r*0.3, 0)
 	imd.Push(rightFootPos)
-	imd.Circle(p.Radius*0.3, 0)
+	imd.Circle(r*0.3, 0)
 	
 	// 目
 	eyeColor := color.RGBA{R: 20, G: 20, B: 80, A: 255}
 	imd.Color = eyeColor
 	
-	eyeOffsetX := p.Radius * 0.3
+	eyeOffsetX := r * 0.3
 	if p.IsFacingLeft {
 		eyeOffsetX = -eyeOffsetX
 	}
 	
-	leftEyePos := p.Position.Add(pixel.V(-eyeOffsetX, p.Radius*0.2))
-	rightEyePos := p.Position.Add(pixel.V(eyeOffsetX, p.Radius*0.2))
+	leftEyePos := center.Add(pixel.V(-eyeOffsetX, r*0.2*squash))
+	rightEyePos := center.Add(pixel.V(eyeOffsetX, r*0.2*squash))
 	
 	// 目の白目
 	imd.Color = color.White
 	imd.Push(leftEyePos)
-	imd.Circle(p.Radius*0.25, 0)
+	imd.Circle(r*0.25*squash, 0)
 	imd.Push(rightEyePos)
-	imd.Circle(p.Radius*0.25, 0)
+	imd.Circle(r*0.25*squash, 0)
 	
 	// 瞳
 	imd.Color = eyeColor
-	pupilOffset := pixel.V(0, -p.Radius*0.05)
+	pupilOffset := pixel.V(0, -r*0.05)
 	imd.Push(leftEyePos.Add(pupilOffset))
-	imd.Circle(p.Radius*0.15, 0)
+	imd.Circle(r*0.15*squash, 0)
 	imd.Push(rightEyePos.Add(pupilOffset))
-	imd.Circle(p.Radius*0.15, 0)
+	imd.Circle(r*0.15*squash, 0)
+	
+	// 口（ほおばり中は丸い口、それ以外は笑顔）
+	if p.State() == PlayerStateFloat {
+		imd.Color = footColor
+		imd.Push(center.Add(pixel.V(0, -r*0.3)))
+		imd.Circle(r*0.15, 0)
+	} else {
+		drawSmile(imd, center.Add(pixel.V(0, -r*0.2*squash)), r*0.5, footColor)
+	}
 	
-	// 口（笑顔）
-	drawSmile(imd, p.Position.Add(pixel.V(0, -p.Radius*0.2)), p.Radius*0.5, footColor)
+	// ほおばりをやめた直後は口から空気のもやが出る（吐き出さずにやめた時も）
+	if sm := p.StateMachine; sm.Previous == PlayerStateFloat && sm.Elapsed < DeflateDuration {
+		p.drawDeflate(imd, center, r, sm.Elapsed/DeflateDuration)
+	}
 	
 	// 頬の赤み
 	cheekColor := color.RGBA{R: 255, G: 150, B: 170, A: 200}
 	imd.Color = cheekColor
 	
-	leftCheekPos := p.Position.Add(pixel.V(-p.Radius*0.7, 0))
-	rightCheekPos := p.Position.Add(pixel.V(p.Radius*0.7, 0))
+	leftCheekPos := center.Add(pixel.V(-r*0.7, 0))
+	rightCheekPos := center.Add(pixel.V(r*0.7, 0))
 	
 	imd.Push(leftCheekPos)
-	imd.Circle(p.Radius*0.2, 0)
+	imd.Circle(r*0.2*squash, 0)
 	imd.Push(rightCheekPos)
-	imd.Circle(p.Radius*0.2, 0)
+	imd.Circle(r*0.2*squash, 0)
+	
+	// 装備した帽子
+	drawHat(imd, p.Hat, center, r, squash, p.IsFacingLeft)
+	
+	// ガード中は体の周りにバリアを描く
+	if p.State() == PlayerStateGuard {
+		imd.Color = color.RGBA{R: 150, G: 200, B: 255, A: 160}
+		imd.Push(center)
+		imd.Circle(r*1.3, 3)
+	}
+}
+
+// drawDeflate は口から抜けていく空気のもやを描画します（t は 0 から 1 へ進む）
+func (p *Player) drawDeflate(imd *imdraw.IMDraw, center pixel.Vec, r, t float64) {
+	mouth := center.Add(pixel.V(p.facingDir()*r*0.9, -r*0.3))
+	alpha := uint8(200 * (1 - t))
+	imd.Color = color.RGBA{R: 240, G: 250, B: 255, A: alpha}
+	imd.Push(mouth.Add(pixel.V(p.facingDir()*r*0.5*t, 0)))
+	imd.Circle(r*(0.25+0.35*t), 0)
+	imd.Push(mouth.Add(pixel.V(p.facingDir()*r*0.3*t, r*0.3*t)))
+	imd.Circle(r*(0.15+0.2*t), 0)
 }
 
 // drawSmile は笑顔の口を描画します
@@ -245,9 +527,16 @@ func drawSmile(imd *imdraw.IMDraw, center pixel.Vec, width float64, col color.Co
 
 // TakeDamage はダメージを受けます
 func (p *Player) TakeDamage(damage int) {
-	if p.InvincibleTime > 0 {
+	if p.InvincibleTime > 0 || p.HasCandy() {
 		return
 	}
+	damage = p.takeDamage(damage)
+	
+	// ガード中（装備の効果で自動ガードした時も）はダメージを軽減し、のけぞらない
+	guarding := p.State() == PlayerStateGuard || p.autoGuard()
+	if guarding {
+		damage = damage / GuardDamageDivide
+	}
 	
 	p.Health -= damage
 	if p.Health < 0 {
@@ -256,6 +545,12 @@ func (p *Player) TakeDamage(damage int) {
 	
 	// 無敵時間を設定
 	p.InvincibleTime = 1.5
+	
+	if p.Health == 0 {
+		p.StateMachine.Transition(PlayerStateDead)
+	} else if !guarding {
+		p.StateMachine.Transition(PlayerStateHurt)
+	}
 }
 
 // Heal は体力を回復します
@@ -266,6 +561,23 @@ func (p *Player) Heal(amount int) {
 	}
 }
 
+// ApplyStats はレベルから決まる能力値を設定します
+func (p *Player) ApplyStats(s Stats) {
+	p.applyStats(s, &p.Health, &p.MaxHealth)
+}
+
+// SetHat は頭に描く帽子を設定します
+func (p *Player) SetHat(h Hat) {
+	p.Hat = h
+}
+
+// Revive は戦闘不能から指定した体力で復帰します
+func (p *Player) Revive(health int) {
+	p.Health = int(math.Min(float64(health), float64(p.MaxHealth)))
+	p.InvincibleTime = ReviveInvincibleTime
+	p.StateMachine.Reset(PlayerStateFall)
+}
+
 // SetAbility はコピー能力を設定します
 func (p *Player) SetAbility(ab ability.Ability) {
 	p.CurrentAbility = ab
@@ -301,6 +613,104 @@ func (p *Player) SetVelocity(v pixel.Vec) {
 	p.Velocity = v
 }
 
+// SetPosition はプレイヤーの位置を設定します
+func (p *Player) SetPosition(pos pixel.Vec) {
+	p.Position = pos
+}
+
+// GetRadius は当たり判定の半径を返します
+func (p *Player) GetRadius() float64 {
+	return p.Radius
+}
+
+// GetHealth は現在の体力を返します
+func (p *Player) GetHealth() int {
+	return p.Health
+}
+
+// GetMaxHealth は最大体力を返します
+func (p *Player) GetMaxHealth() int {
+	return p.MaxHealth
+}
+
+// IsDefeated は戦闘不能かどうかを返します
+func (p *Player) IsDefeated() bool {
+	return p.Health <= 0
+}
+
+// GetAbility は現在のコピー能力を返します
+func (p *Player) GetAbility() ability.Ability {
+	return p.CurrentAbility
+}
+
+// CanCopyAbility は倒した敵の能力をコピーできるかを返します
+func (p *Player) CanCopyAbility() bool {
+	return true
+}
+
+// IsAttacking は攻撃判定を持つ状態かどうかを返します
+func (p *Player) IsAttacking() bool {
+	return p.State().IsAttacking()
+}
+
+// GetAttackRange は攻撃範囲を返します
+func (p *Player) GetAttackRange() float64 {
+	switch ab := p.CurrentAbility.(type) {
+	case *ability.HammerAbility:
+		return ab.AttackRange
+	case *ability.SwordAbility:
+		return ab.AttackRange
+	case *ability.FireAbility:
+		return ab.AttackRange
+	case *ability.StoneAbility:
+		return ab.AttackRange
+	}
+	return 50.0
+}
+
+// GetAttackDamage は攻撃力を反映した攻撃ダメージを返します
+func (p *Player) GetAttackDamage() int {
+	return p.dealDamage(p.baseAttackDamage())
+}
+
+// baseAttackDamage は技の本来の攻撃ダメージを返します
+func (p *Player) baseAttackDamage() int {
+	if p.IsSliding() {
+		return SlideDamage
+	}
+	switch ab := p.CurrentAbility.(type) {
+	case *ability.HammerAbility:
+		return ab.AttackDamage
+	case *ability.SwordAbility:
+		return ab.AttackDamage
+	case *ability.FireAbility:
+		return ab.AttackDamage
+	case *ability.StoneAbility:
+		return ab.AttackDamage
+	}
+	return 10
+}
+
+// DeflectsProjectiles は飛び道具を跳ね返せるかを返します
+func (p *Player) DeflectsProjectiles() bool {
+	return false
+}
+
+// Name はキャラクター名を返します
+func (p *Player) Name() string {
+	return "Kirby"
+}
+
+// AbilityLabel はHUDに表示する能力欄の見出しを返します
+func (p *Player) AbilityLabel() string {
+	return "Ability"
+}
+
+// ControlsHelp は操作説明を返します
+func (p *Player) ControlsHelp() string {
+	return "Arrow/WASD: Move  Space: Jump/Float  X: Attack/Exhale  Down: Crouch  C: Guard  Shift: Run  H: Helper"
+}
+
 // PlayerInput はプレイヤーの入力を表します
 type PlayerInput struct {
 	MoveLeft  bool
@@ -308,4 +718,15 @@ type PlayerInput struct {
 	Jump      bool
 	Attack    bool
 	UseAbility bool
+	Up        bool
+	Down      bool
+	Guard     bool
+	Run       bool
+	Evade     bool
+	
+	// コピー能力をヘルパーに変える
+	CreateHelper bool
+	
+	// 能力の切り替え（0: なし、1以上: スロット番号）
+	SwitchAbility int
 }
@@ -0,0 +1,133 @@
+package entity
+
+// PlayerState はプレイヤーの行動状態を表します
+type PlayerState int
+
+const (
+	PlayerStateIdle    PlayerState = iota // 待機
+	PlayerStateWalk                       // 歩き
+	PlayerStateRun                        // ダッシュ
+	PlayerStateJump                       // ジャンプ（上昇中）
+	PlayerStateFall                       // 落下
+	PlayerStateFloat                      // ほおばり飛行（ホバリング）
+	PlayerStateCrouch                     // しゃがみ
+	PlayerStateSlide                      // スライディング
+	PlayerStateGuard                      // ガード
+	PlayerStateAttack                     // 攻撃
+	PlayerStateHurt                       // 被ダメージ（のけぞり）
+	PlayerStateDead                       // 戦闘不能
+	PlayerStateVictory                    // 勝利ポーズ
+	PlayerStateGlide                      // 滑空（メタナイト）
+	PlayerStateTeleport                   // ディメンジョンマント（メタナイト）
+)
+
+// String は状態名を返します（アニメーション用）
+func (s PlayerState) String() string {
+	switch s {
+	case PlayerStateIdle:
+		return "idle"
+	case PlayerStateWalk:
+		return "walk"
+	case PlayerStateRun:
+		return "run"
+	case PlayerStateJump:
+		return "jump"
+	case PlayerStateFall:
+		return "fall"
+	case PlayerStateFloat:
+		return "float"
+	case PlayerStateCrouch:
+		return "crouch"
+	case PlayerStateSlide:
+		return "slide"
+	case PlayerStateGuard:
+		return "guard"
+	case PlayerStateAttack:
+		return "attack"
+	case PlayerStateHurt:
+		return "hurt"
+	case PlayerStateDead:
+		return "dead"
+	case PlayerStateVictory:
+		return "victory"
+	case PlayerStateGlide:
+		return "glide"
+	case PlayerStateTeleport:
+		return "teleport"
+	default:
+		return "unknown"
+	}
+}
+
+// IsAirborne は空中にいる状態かどうかを返します
+func (s PlayerState) IsAirborne() bool {
+	return s == PlayerStateJump || s == PlayerStateFall || s == PlayerStateFloat || s == PlayerStateGlide
+}
+
+// IsHitstun はダメージによる硬直中かどうかを返します
+func (s PlayerState) IsHitstun() bool {
+	return s == PlayerStateHurt
+}
+
+// IsAttacking は攻撃判定を持つ状態かどうかを返します
+func (s PlayerState) IsAttacking() bool {
+	return s == PlayerStateAttack || s == PlayerStateSlide
+}
+
+// IsActionable は入力を受け付ける状態かどうかを返します
+func (s PlayerState) IsActionable() bool {
+	switch s {
+	case PlayerStateHurt, PlayerStateDead, PlayerStateVictory:
+		return false
+	default:
+		return true
+	}
+}
+
+// MovementParams は状態ごとの物理パラメータです
+type MovementParams struct {
+	MaxSpeed     float64 // 横方向の最高速度
+	Acceleration float64 // 横方向の加速度
+	Gravity      float64 // 重力加速度
+	MaxFallSpeed float64 // 最大落下速度
+	Friction     float64 // 毎フレームの速度減衰率
+}
+
+const (
+	FloatFlapForce       = 220.0 // ほおばり中のはばたき上昇力
+	RunSpeed             = 320.0 // ダッシュ時の最高速度
+	SlideSpeed           = 380.0 // スライディングの初速
+	SlideDuration        = 0.45  // スライディングの持続時間
+	SlideDamage          = 25    // スライディングキックのダメージ
+	GuardDamageDivide    = 4     // ガード中のダメージ除数
+	AttackDuration       = 0.25  // 攻撃モーションの長さ
+	HurtDuration         = 0.4   // のけぞり時間
+	HurtKnockback        = 180.0 // のけぞり時のノックバック速度
+	DeflateDuration      = 0.25  // ほおばりをやめた時に口から空気のもやが出ている時間
+	ReviveInvincibleTime = 2.0   // 復帰直後の無敵時間
+)
+
+// playerStateParams は状態ごとの物理パラメータ表です
+var playerStateParams = map[PlayerState]MovementParams{
+	PlayerStateIdle:   {MaxSpeed: PlayerSpeed, Acceleration: PlayerSpeed * 10, Gravity: Gravity, MaxFallSpeed: MaxFallSpeed, Friction: GroundFriction},
+	PlayerStateWalk:   {MaxSpeed: PlayerSpeed, Acceleration: PlayerSpeed * 10, Gravity: Gravity, MaxFallSpeed: MaxFallSpeed, Friction: GroundFriction},
+	PlayerStateRun:    {MaxSpeed: RunSpeed, Acceleration: RunSpeed * 10, Gravity: Gravity, MaxFallSpeed: MaxFallSpeed, Friction: GroundFriction},
+	PlayerStateJump:   {MaxSpeed: PlayerSpeed, Acceleration: PlayerSpeed * 10, Gravity: Gravity, MaxFallSpeed: MaxFallSpeed, Friction: AirFriction},
+	PlayerStateFall:   {MaxSpeed: PlayerSpeed, Acceleration: PlayerSpeed * 10, Gravity: Gravity, MaxFallSpeed: MaxFallSpeed, Friction: AirFriction},
+	PlayerStateFloat:  {MaxSpeed: PlayerSpeed * 0.6, Acceleration: PlayerSpeed * 5, Gravity: Gravity * 0.35, MaxFallSpeed: 120, Friction: AirFriction},
+	PlayerStateCrouch: {MaxSpeed: 0, Acceleration: 0, Gravity: Gravity, MaxFallSpeed: MaxFallSpeed, Friction: 0.7},
+	PlayerStateSlide:  {MaxSpeed: SlideSpeed, Acceleration: 0, Gravity: Gravity, MaxFallSpeed: MaxFallSpeed, Friction: 0.97},
+	PlayerStateGuard:  {MaxSpeed: 0, Acceleration: 0, Gravity: Gravity, MaxFallSpeed: MaxFallSpeed, Friction: 0.6},
+	PlayerStateAttack: {MaxSpeed: PlayerSpeed, Acceleration: 0, Gravity: Gravity, MaxFallSpeed: MaxFallSpeed, Friction: GroundFriction},
+	PlayerStateHurt:   {MaxSpeed: HurtKnockback, Acceleration: 0, Gravity: Gravity, MaxFallSpeed: MaxFallSpeed, Friction: 0.9},
+	PlayerStateDead:   {MaxSpeed: 0, Acceleration: 0, Gravity: Gravity, MaxFallSpeed: MaxFallSpeed, Friction: 0.5},
+	PlayerStateVictory: {MaxSpeed: 0, Acceleration: 0, Gravity: Gravity, MaxFallSpeed: MaxFallSpeed, Friction: 0.5},
+}
+
+// ParamsForState は指定した状態の物理パラメータを返します
+func ParamsForState(s PlayerState) MovementParams {
+	if params, ok := playerStateParams[s]; ok {
+		return params
+	}
+	return playerStateParams[PlayerStateIdle]
+}
@@ -0,0 +1,109 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/faiface/pixel"
+)
+
+const (
+	testDT          = 1.0 / 60
+	testStageWidth  = 800.0
+	testStageHeight = 600.0
+)
+
+// floatingPlayer は空中でほおばり飛行を始めたカービィを作ります
+func floatingPlayer(t *testing.T) *Player {
+	t.Helper()
+	p := NewPlayer(pixel.V(200, 300))
+	p.Update(testDT, PlayerInput{}, testStageWidth, testStageHeight)
+	p.Update(testDT, PlayerInput{Jump: true}, testStageWidth, testStageHeight)
+	if p.State() != PlayerStateFloat {
+		t.Fatalf("state = %v, want Float", p.State())
+	}
+	return p
+}
+
+// countAirPuffs は吐き出した空気弾と水鉄砲を数えます
+func countAirPuffs(p *Player) int {
+	n := 0
+	for _, pr := range p.TakeProjectiles() {
+		if pr.Kind == ProjectileAirPuff || pr.Kind == ProjectileWaterSpit {
+			n++
+		}
+	}
+	return n
+}
+
+func TestFloatExhalesOnRelease(t *testing.T) {
+	tests := []struct {
+		name  string
+		input PlayerInput
+	}{
+		{name: "attack", input: PlayerInput{Attack: true}},
+		{name: "down", input: PlayerInput{Down: true}},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := floatingPlayer(t)
+			p.Update(testDT, tt.input, testStageWidth, testStageHeight)
+			
+			if p.State() == PlayerStateFloat {
+				t.Fatal("still floating after releasing")
+			}
+			if n := countAirPuffs(p); n != 1 {
+				t.Errorf("released %d air puffs, want 1", n)
+			}
+		})
+	}
+}
+
+func TestFloatExhalesOnLanding(t *testing.T) {
+	p := floatingPlayer(t)
+	for i := 0; i < 600 && p.State() == PlayerStateFloat; i++ {
+		p.Update(testDT, PlayerInput{}, testStageWidth, testStageHeight)
+	}
+	
+	if !p.IsGrounded || p.State() == PlayerStateFloat {
+		t.Fatalf("did not land: state %v at %v", p.State(), p.Position)
+	}
+	if n := countAirPuffs(p); n != 1 {
+		t.Errorf("landing released %d air puffs, want 1", n)
+	}
+}
+
+func TestFloatInterruptedWithoutAirPuff(t *testing.T) {
+	tests := []struct {
+		name      string
+		interrupt func(p *Player)
+		want      PlayerState
+	}{
+		{name: "hurt", interrupt: func(p *Player) { p.TakeDamage(10) }, want: PlayerStateHurt},
+		{name: "dead", interrupt: func(p *Player) { p.TakeDamage(p.Health) }, want: PlayerStateDead},
+		{name: "victory", interrupt: func(p *Player) { p.Celebrate() }, want: PlayerStateVictory},
+		{name: "water", interrupt: func(p *Player) {
+			p.SetEnvironment(Environment{InWater: true})
+			p.Update(testDT, PlayerInput{}, testStageWidth, testStageHeight)
+		}, want: PlayerStateFall},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := floatingPlayer(t)
+			tt.interrupt(p)
+			
+			if p.State() != tt.want {
+				t.Fatalf("state = %v, want %v", p.State(), tt.want)
+			}
+			if n := countAirPuffs(p); n != 0 {
+				t.Errorf("released %d air puffs, want none", n)
+			}
+			
+			// 空気が抜けるもやは表示する
+			if sm := p.StateMachine; sm.Previous != PlayerStateFloat || sm.Elapsed >= DeflateDuration {
+				t.Errorf("no deflate puff: previous %v, elapsed %.2f", sm.Previous, sm.Elapsed)
+			}
+		})
+	}
+}
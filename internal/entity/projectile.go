@@ -0,0 +1,235 @@
+package entity
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+)
+
+// Team は攻撃の所属陣営を表します
+type Team int
+
+const (
+	TeamPlayer Team = iota // プレイヤー側
+	TeamEnemy              // 敵側
+)
+
+// ProjectileKind は飛び道具の種類を表します
+type ProjectileKind int
+
+const (
+	ProjectileAirPuff ProjectileKind = iota // 空気弾
+	ProjectileBeam                          // ワドルドゥのビーム
+	ProjectileSpear                         // バンダナワドルディの投げ槍
+	ProjectileWaterSpit                     // 水中で吐き出す水鉄砲
+)
+
+// NoOwner は持ち主のいない（敵が撃った）飛び道具を表します
+const NoOwner = -1
+
+const (
+	AirPuffSpeed    = 350.0
+	AirPuffDamage   = 15
+	AirPuffLifetime = 0.5
+	
+	BeamSpeed    = 260.0
+	BeamDamage   = 12
+	BeamLifetime = 1.2
+	
+	SpearSpeed    = 420.0
+	SpearDamage   = 18
+	SpearLifetime = 0.8
+	
+	WaterSpitSpeed    = 300.0
+	WaterSpitDamage   = 12
+	WaterSpitLifetime = 0.6
+)
+
+// Projectile は飛び道具を表します
+type Projectile struct {
+	Position pixel.Vec
+	Velocity pixel.Vec
+	Radius   float64
+	Damage   int
+	Kind     ProjectileKind
+	Team     Team
+	Owner    int // 撃ったプレイヤーの番号（敵の場合は NoOwner）
+	Lifetime float64
+	IsAlive  bool
+	
+	// アニメーション
+	AnimationTime float64
+}
+
+// NewAirPuff はほおばり状態から吐き出す空気弾を作成します
+func NewAirPuff(pos pixel.Vec, facingLeft bool) *Projectile {
+	dir := 1.0
+	if facingLeft {
+		dir = -1.0
+	}
+	
+	return &Projectile{
+		Position: pos.Add(pixel.V(dir*PlayerRadius, 0)),
+		Velocity: pixel.V(dir*AirPuffSpeed, 0),
+		Radius:   10.0,
+		Damage:   AirPuffDamage,
+		Kind:     ProjectileAirPuff,
+		Team:     TeamPlayer,
+		Owner:    NoOwner,
+		Lifetime: AirPuffLifetime,
+		IsAlive:  true,
+	}
+}
+
+// NewBeam はワドルドゥが撃つビームを作成します
+func NewBeam(pos pixel.Vec, dir float64) *Projectile {
+	return &Projectile{
+		Position: pos.Add(pixel.V(dir*16, 0)),
+		Velocity: pixel.V(dir*BeamSpeed, 0),
+		Radius:   6.0,
+		Damage:   BeamDamage,
+		Kind:     ProjectileBeam,
+		Team:     TeamEnemy,
+		Owner:    NoOwner,
+		Lifetime: BeamLifetime,
+		IsAlive:  true,
+	}
+}
+
+// NewSpear はバンダナワドルディが投げる槍を作成します
+func NewSpear(pos pixel.Vec, dir float64) *Projectile {
+	return &Projectile{
+		Position: pos.Add(pixel.V(dir*20, 4)),
+		Velocity: pixel.V(dir*SpearSpeed, 40),
+		Radius:   7.0,
+		Damage:   SpearDamage,
+		Kind:     ProjectileSpear,
+		Team:     TeamPlayer,
+		Owner:    NoOwner,
+		Lifetime: SpearLifetime,
+		IsAlive:  true,
+	}
+}
+
+// NewWaterSpit は水中で吸い込みの代わりに吐き出す水鉄砲を作成します
+func NewWaterSpit(pos pixel.Vec, facingLeft bool) *Projectile {
+	dir := 1.0
+	if facingLeft {
+		dir = -1.0
+	}
+	
+	return &Projectile{
+		Position: pos.Add(pixel.V(dir*PlayerRadius, 0)),
+		Velocity: pixel.V(dir*WaterSpitSpeed, 0),
+		Radius:   8.0,
+		Damage:   WaterSpitDamage,
+		Kind:     ProjectileWaterSpit,
+		Team:     TeamPlayer,
+		Owner:    NoOwner,
+		Lifetime: WaterSpitLifetime,
+		IsAlive:  true,
+	}
+}
+
+// Deflect は飛び道具を跳ね返し、所属陣営を入れ替えます
+func (pr *Projectile) Deflect(team Team) {
+	pr.Velocity = pr.Velocity.Scaled(-1.2)
+	pr.Team = team
+	pr.Lifetime = math.Max(pr.Lifetime, 0.6)
+}
+
+// Update は飛び道具の状態を更新します
+func (pr *Projectile) Update(dt float64, stageWidth, stageHeight float64) {
+	if !pr.IsAlive {
+		return
+	}
+	
+	pr.AnimationTime += dt
+	pr.Lifetime -= dt
+	if pr.Lifetime <= 0 {
+		pr.IsAlive = false
+		return
+	}
+	
+	// 空気弾は徐々に減速し、投げ槍は放物線を描く
+	switch pr.Kind {
+	case ProjectileAirPuff:
+		pr.Velocity = pr.Velocity.Scaled(0.97)
+	case ProjectileSpear:
+		pr.Velocity.Y -= Gravity * 0.25 * dt
+	}
+	
+	pr.Position = pr.Position.Add(pr.Velocity.Scaled(dt))
+	
+	// 画面外に出たら消滅
+	if pr.Position.X < -pr.Radius || pr.Position.X > stageWidth+pr.Radius ||
+		pr.Position.Y < -pr.Radius || pr.Position.Y > stageHeight+pr.Radius {
+		pr.IsAlive = false
+	}
+}
+
+// Draw は飛び道具を描画します
+func (pr *Projectile) Draw(imd *imdraw.IMDraw) {
+	if !pr.IsAlive {
+		return
+	}
+	
+	switch pr.Kind {
+	case ProjectileAirPuff:
+		// 残り時間に応じて薄くなる白い渦
+		alpha := uint8(255 * math.Min(1, pr.Lifetime/AirPuffLifetime+0.3))
+		imd.Color = color.RGBA{R: 240, G: 250, B: 255, A: alpha}
+		imd.Push(pr.Position)
+		imd.Circle(pr.Radius, 0)
+		
+		imd.Color = color.RGBA{R: 180, G: 220, B: 255, A: alpha}
+		swirl := pixel.V(math.Cos(pr.AnimationTime*20), math.Sin(pr.AnimationTime*20)).Scaled(pr.Radius * 0.5)
+		imd.Push(pr.Position.Add(swirl))
+		imd.Circle(pr.Radius*0.4, 0)
+		
+	case ProjectileBeam:
+		// 黄色い光の粒（跳ね返されたものは水色）
+		beamColor := color.RGBA{R: 255, G: 240, B: 80, A: 255}
+		if pr.Team == TeamPlayer {
+			beamColor = color.RGBA{R: 120, G: 220, B: 255, A: 255}
+		}
+		imd.Color = beamColor
+		for i := 0; i < 3; i++ {
+			offset := pixel.V(-pr.Velocity.X*0.02*float64(i), math.Sin(pr.AnimationTime*30+float64(i))*3)
+			imd.Push(pr.Position.Add(offset))
+			imd.Circle(pr.Radius*(1-float64(i)*0.25), 0)
+		}
+		
+	case ProjectileSpear:
+		// 進行方向を向いた柄と穂先
+		dir := pr.Velocity.Unit()
+		tail := pr.Position.Sub(dir.Scaled(pr.Radius * 3))
+		imd.Color = color.RGBA{R: 160, G: 110, B: 60, A: 255}
+		imd.Push(tail, pr.Position)
+		imd.Line(3)
+		imd.Color = color.RGBA{R: 210, G: 210, B: 230, A: 255}
+		imd.Push(pr.Position, pr.Position.Add(dir.Scaled(pr.Radius*1.2)))
+		imd.Line(5)
+		
+	case ProjectileWaterSpit:
+		// 水しぶきの粒
+		imd.Color = color.RGBA{R: 90, G: 170, B: 255, A: 220}
+		for i := 0; i < 3; i++ {
+			offset := pixel.V(-pr.Velocity.X*0.03*float64(i), math.Sin(pr.AnimationTime*25+float64(i)*2)*4)
+			imd.Push(pr.Position.Add(offset))
+			imd.Circle(pr.Radius*(1-float64(i)*0.3), 0)
+		}
+	}
+}
+
+// GetBounds は当たり判定用の矩形を返します
+func (pr *Projectile) GetBounds() pixel.Rect {
+	return pixel.R(
+		pr.Position.X-pr.Radius,
+		pr.Position.Y-pr.Radius,
+		pr.Position.X+pr.Radius,
+		pr.Position.Y+pr.Radius,
+	)
+}
@@ -0,0 +1,24 @@
+package entity
+
+import "github.com/faiface/pixel"
+
+// FallRecovery は穴に落ちた時に戻る位置です。キャラクターに埋め込んで使います。
+// ゲーム側が部屋に入った時や中間ポイントに触れた時に設定します
+type FallRecovery struct {
+	RespawnPoint    pixel.Vec
+	HasRespawnPoint bool
+}
+
+// SetRespawnPoint は穴に落ちた時に戻る位置を設定します
+func (fr *FallRecovery) SetRespawnPoint(pos pixel.Vec) {
+	fr.RespawnPoint = pos
+	fr.HasRespawnPoint = true
+}
+
+// respawnPoint は穴に落ちた時に戻る位置を返します（設定されていなければステージの中央上）
+func (fr *FallRecovery) respawnPoint(stageWidth float64) pixel.Vec {
+	if fr.HasRespawnPoint {
+		return fr.RespawnPoint
+	}
+	return pixel.V(stageWidth/2, 200)
+}
@@ -0,0 +1,100 @@
+package entity
+
+// StateHook は状態の開始・終了時に呼ばれる処理です
+type StateHook func(from, to PlayerState)
+
+// StateMachine はキャラクターの行動状態と遷移を管理します
+type StateMachine struct {
+	Current  PlayerState
+	Previous PlayerState
+	Elapsed  float64 // 現在の状態に入ってからの経過時間
+	
+	onEnter map[PlayerState]StateHook
+	onExit  map[PlayerState]StateHook
+}
+
+// NewStateMachine は新しいステートマシンを作成します
+func NewStateMachine(initial PlayerState) *StateMachine {
+	return &StateMachine{
+		Current:  initial,
+		Previous: initial,
+		Elapsed:  0,
+		onEnter:  make(map[PlayerState]StateHook),
+		onExit:   make(map[PlayerState]StateHook),
+	}
+}
+
+// OnEnter は状態に入った時の処理を登録します
+func (m *StateMachine) OnEnter(s PlayerState, hook StateHook) {
+	m.onEnter[s] = hook
+}
+
+// OnExit は状態から出る時の処理を登録します
+func (m *StateMachine) OnExit(s PlayerState, hook StateHook) {
+	m.onExit[s] = hook
+}
+
+// Update は状態の経過時間を進めます
+func (m *StateMachine) Update(dt float64) {
+	m.Elapsed += dt
+}
+
+// Transition は状態を遷移させます。同じ状態への遷移や、
+// 戦闘不能からの遷移（Reset以外）は行わず false を返します
+func (m *StateMachine) Transition(to PlayerState) bool {
+	if to == m.Current || m.Current == PlayerStateDead {
+		return false
+	}
+	
+	from := m.Current
+	if hook, ok := m.onExit[from]; ok {
+		hook(from, to)
+	}
+	
+	m.Previous = from
+	m.Current = to
+	m.Elapsed = 0
+	
+	if hook, ok := m.onEnter[to]; ok {
+		hook(from, to)
+	}
+	return true
+}
+
+// Restart は現在の状態をフックを呼んでやり直します（連続攻撃のように同じ状態へ入り直す時用）。
+// 戦闘不能からはやり直さず false を返します
+func (m *StateMachine) Restart() bool {
+	if m.Current == PlayerStateDead {
+		return false
+	}
+	
+	s := m.Current
+	if hook, ok := m.onExit[s]; ok {
+		hook(s, s)
+	}
+	
+	m.Previous = s
+	m.Elapsed = 0
+	
+	if hook, ok := m.onEnter[s]; ok {
+		hook(s, s)
+	}
+	return true
+}
+
+// Reset はフックを呼ばずに状態を強制的に設定します（復活・リスポーン用）
+func (m *StateMachine) Reset(s PlayerState) {
+	m.Previous = m.Current
+	m.Current = s
+	m.Elapsed = 0
+}
+
+// Is は現在の状態がいずれかに一致するかを返します
+func (m *StateMachine) Is(states ...PlayerState) bool {
+	for _, s := range states {
+		if m.Current == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package entity
+
+import (
+	"math"
+
+	"github.com/remmakoshino/kirby-inspired-go/internal/ability"
+	"github.com/remmakoshino/kirby-inspired-go/internal/rng"
+)
+
+const (
+	MaxLevel   = 50 // キャラクターのレベルの上限
+	BaseAttack = 10 // 攻撃力がこの値の時に技の本来のダメージを与える
+)
+
+// Stats はレベルと装備から決まるキャラクターの能力値です。
+// ゼロ値は「能力値なし」で、ダメージも速さも本来のままになります（対戦やタイムアタック、ヘルパー）
+type Stats struct {
+	Level     int
+	MaxHealth int
+	Attack    int     // 与えるダメージは Attack / BaseAttack 倍
+	Defense   int     // 受けるダメージは 100 / (100 + Defense) 倍
+	Speed     float64 // 移動の速さの倍率
+	
+	// 装備による効果（ゼロ値なら効果なし）
+	Cooldown    float64 // 能力のクールダウンが早く戻る割合
+	JumpHeight  float64 // ジャンプの高さの増加の割合
+	GuardChance float64 // 攻撃を受けた時に自動でガードする確率
+}
+
+// StatGrowth はキャラクターごとの能力値の伸び方です（レベル1の値と、レベルが1上がるごとの伸び）
+type StatGrowth struct {
+	Health, HealthPerLevel   float64
+	Attack, AttackPerLevel   float64
+	Defense, DefensePerLevel float64
+	Speed, SpeedPerLevel     float64
+}
+
+// At はレベル level の能力値を返します
+func (sg StatGrowth) At(level int) Stats {
+	level = clampLevel(level)
+	n := float64(level - 1)
+	return Stats{
+		Level:     level,
+		MaxHealth: int(math.Round(sg.Health + sg.HealthPerLevel*n)),
+		Attack:    int(math.Round(sg.Attack + sg.AttackPerLevel*n)),
+		Defense:   int(math.Round(sg.Defense + sg.DefensePerLevel*n)),
+		Speed:     sg.Speed + sg.SpeedPerLevel*n,
+	}
+}
+
+// StatsFor は登録済みキャラクターのレベル level の能力値を返します（未登録ならゼロ値）
+func StatsFor(id string, level int) Stats {
+	info, ok := LookupCharacter(id)
+	if !ok {
+		return Stats{}
+	}
+	return info.Growth.At(level)
+}
+
+// ExpToNext は level から次のレベルに上がるのに必要な経験値を返します
+func ExpToNext(level int) int {
+	return 20 + 15*(clampLevel(level)-1)
+}
+
+// clampLevel はレベルを 1〜MaxLevel に収めます
+func clampLevel(level int) int {
+	if level < 1 {
+		return 1
+	}
+	if level > MaxLevel {
+		return MaxLevel
+	}
+	return level
+}
+
+// Damage は技の本来のダメージ base に、攻撃側の攻撃力と受ける側の防御力を反映したダメージを返します。
+// 攻撃力が0以下なら BaseAttack として扱い、1以上のダメージは1未満になりません
+func Damage(base, attack, defense int) int {
+	if base <= 0 {
+		return base
+	}
+	if attack <= 0 {
+		attack = BaseAttack
+	}
+	if defense < 0 {
+		defense = 0
+	}
+	d := float64(base) * float64(attack) / BaseAttack * 100 / float64(100+defense)
+	return int(math.Max(1, math.Round(d)))
+}
+
+// StatBlock はキャラクターの能力値です。キャラクターに埋め込んで使います
+type StatBlock struct {
+	Stats Stats
+}
+
+// GetStats は能力値を返します
+func (sb *StatBlock) GetStats() Stats {
+	return sb.Stats
+}
+
+// applyStats は能力値を設定し、最大体力が増えた（減った）分だけ体力も増やします（戦闘不能のままなら0のまま）
+func (sb *StatBlock) applyStats(s Stats, health, maxHealth *int) {
+	sb.Stats = s
+	if s.MaxHealth <= 0 {
+		return
+	}
+	if *health > 0 {
+		*health = int(math.Max(1, float64(*health+s.MaxHealth-*maxHealth)))
+	}
+	*maxHealth = s.MaxHealth
+	if *health > *maxHealth {
+		*health = *maxHealth
+	}
+}
+
+// dealDamage は技の本来のダメージに攻撃力を反映します
+func (sb *StatBlock) dealDamage(base int) int {
+	return Damage(base, sb.Stats.Attack, 0)
+}
+
+// takeDamage は受けるダメージに防御力を反映します
+func (sb *StatBlock) takeDamage(base int) int {
+	return Damage(base, 0, sb.Stats.Defense)
+}
+
+// moveScale は移動の速さの倍率を返します（能力値がなければ1）
+func (sb *StatBlock) moveScale() float64 {
+	if sb.Stats.Speed <= 0 {
+		return 1
+	}
+	return sb.Stats.Speed
+}
+
+// jumpScale はジャンプの初速の倍率を返します（高さは初速の2乗に比例する）
+func (sb *StatBlock) jumpScale() float64 {
+	if sb.Stats.JumpHeight <= 0 {
+		return 1
+	}
+	return math.Sqrt(1 + sb.Stats.JumpHeight)
+}
+
+// cooldownAdvancer はクールダウンだけを進められる能力です
+type cooldownAdvancer interface {
+	AdvanceCooldown(dt float64)
+}
+
+// hasteCooldown は装備の効果の分だけ能力のクールダウンを余分に進めます
+func (sb *StatBlock) hasteCooldown(dt float64, a ability.Ability) {
+	if sb.Stats.Cooldown <= 0 {
+		return
+	}
+	if ca, ok := a.(cooldownAdvancer); ok {
+		ca.AdvanceCooldown(dt * sb.Stats.Cooldown)
+	}
+}
+
+// autoGuard は装備の効果で攻撃を自動でガードするかを抽選します（効果がなければ乱数を使わない）
+func (sb *StatBlock) autoGuard() bool {
+	return sb.Stats.GuardChance > 0 && rng.Float64() < sb.Stats.GuardChance
+}
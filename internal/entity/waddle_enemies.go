@@ -39,6 +39,9 @@ type WaddleDoo struct {
 	*Enemy
 	ShootTimer    float64
 	ShootCooldown float64
+	
+	// 発射したビーム（ゲーム側が回収する）
+	Projectiles []*Projectile
 }
 
 // NewWaddleDoo は新しいワドルドゥを作成します
@@ -66,6 +69,37 @@ func NewWaddleDoo(pos pixel.Vec) *WaddleDoo {
 	}
 }
 
+const (
+	WaddleDooBeamRange = 260.0 // ビームを撃ち始める距離
+)
+
+// Update はワドルドゥの状態を更新します（プレイヤーが近いとビームを撃つ）
+func (wd *WaddleDoo) Update(dt float64, playerPos pixel.Vec, stageWidth, stageHeight float64) {
+	wd.Enemy.Update(dt, playerPos, stageWidth, stageHeight)
+	if !wd.IsAlive {
+		return
+	}
+	
+	wd.ShootTimer += dt
+	if wd.ShootTimer < wd.ShootCooldown || math.Abs(playerPos.X-wd.Position.X) > WaddleDooBeamRange {
+		return
+	}
+	
+	wd.ShootTimer = 0
+	dir := 1.0
+	if playerPos.X < wd.Position.X {
+		dir = -1.0
+	}
+	wd.Projectiles = append(wd.Projectiles, NewBeam(wd.Position, dir))
+}
+
+// TakeProjectiles は発射済みのビームを取り出します
+func (wd *WaddleDoo) TakeProjectiles() []*Projectile {
+	projectiles := wd.Projectiles
+	wd.Projectiles = nil
+	return projectiles
+}
+
 // Draw はワドルディを描画します
 func (wd *WaddleDee) Draw(imd *imdraw.IMDraw) {
 	if !wd.IsAlive {
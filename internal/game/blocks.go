@@ -0,0 +1,107 @@
+package game
+
+import (
+	"github.com/faiface/pixel"
+
+	"github.com/remmakoshino/kirby-inspired-go/internal/ability"
+	"github.com/remmakoshino/kirby-inspired-go/internal/entity"
+	"github.com/remmakoshino/kirby-inspired-go/internal/stage"
+)
+
+// BlockScore は攻撃でブロックを1つ壊した時のスコアです
+const BlockScore = 10
+
+// blockHits はコピー能力ごとのブロックへの攻撃の種類です（ない能力はふつうの攻撃）
+var blockHits = map[string]stage.BlockHit{
+	"hammer": stage.HitHeavy,
+	"stone":  stage.HitHeavy,
+	"fire":   stage.HitFire,
+}
+
+// blockHitOf はキャラクターの今の能力でのブロックへの攻撃の種類を返します
+func blockHitOf(c entity.PlayableCharacter) stage.BlockHit {
+	if hit, ok := blockHits[ability.TypeOf(c.GetAbility())]; ok {
+		return hit
+	}
+	return stage.HitNormal
+}
+
+// updateBlocks は爆弾の連鎖と導火線を進め、攻撃中のキャラクターがブロックを壊し、スイッチを押します
+func (g *Game) updateBlocks(dt float64) {
+	g.Stage.UpdateBlocks(dt)
+	
+	for _, c := range g.Characters {
+		if c.IsDefeated() {
+			continue
+		}
+		if c.IsAttacking() {
+			g.Score += BlockScore * g.Stage.HitBlocks(c.GetPosition(), c.GetAttackRange(), blockHitOf(c))
+		}
+		
+		// スイッチは乗るか攻撃すると押せる
+		for _, sw := range g.Stage.Switches {
+			bounds := sw.GetBounds()
+			attacked := c.IsAttacking() && c.GetPosition().Sub(bounds.Center()).Len() < c.GetAttackRange()
+			if attacked || c.GetBounds().Intersects(bounds) {
+				g.Stage.PressSwitch(sw)
+			}
+		}
+	}
+}
+
+// hitBlocksWithProjectile は飛び道具がブロックに当たったかを調べます。
+// プレイヤーの飛び道具はふつうの攻撃としてブロックを壊し、スイッチを押します。
+// 当たり判定のあるブロックに当たった飛び道具は消えます
+func (g *Game) hitBlocksWithProjectile(pr *entity.Projectile) {
+	if pr.Team == entity.TeamPlayer {
+		g.Score += BlockScore * g.Stage.HitBlocks(pr.Position, pr.Radius, stage.HitNormal)
+		for _, sw := range g.Stage.Switches {
+			if pr.GetBounds().Intersects(sw.GetBounds()) {
+				g.Stage.PressSwitch(sw)
+			}
+		}
+	}
+	if g.Stage.SolidBlockAt(pr.Position, pr.Radius) {
+		pr.IsAlive = false
+	}
+}
+
+// checkBlockCollision はキャラクターを壊れていないブロックから押し出します。
+// ブロックは足場と違い、横からも通り抜けられない壁になります
+func (g *Game) checkBlockCollision(c entity.PlayableCharacter) {
+	for _, block := range g.Stage.Blocks {
+		bounds := c.GetBounds()
+		if !block.Solid() || !bounds.Intersects(block.Rect) {
+			continue
+		}
+		
+		pos := c.GetPosition()
+		vel := c.GetVelocity()
+		radius := c.GetRadius()
+		r := block.Rect
+		
+		switch {
+		case vel.Y <= 0 && bounds.Min.Y > r.Max.Y-8:
+			// 上から着地
+			c.SetPosition(pixel.V(pos.X, r.Max.Y+radius))
+			c.SetVelocity(pixel.V(vel.X, 0))
+			c.Land()
+		case vel.Y > 0 && bounds.Max.Y < r.Min.Y+8:
+			// 下から頭をぶつける
+			c.SetPosition(pixel.V(pos.X, r.Min.Y-radius))
+			c.SetVelocity(pixel.V(vel.X, 0))
+		case pos.X < r.Center().X:
+			// 左の壁
+			c.SetPosition(pixel.V(r.Min.X-radius, pos.Y))
+			if vel.X > 0 {
+				c.SetVelocity(pixel.V(0, vel.Y))
+			}
+		default:
+			// 右の壁
+			c.SetPosition(pixel.V(r.Max.X+radius, pos.Y))
+			if vel.X < 0 {
+				c.SetVelocity(pixel.V(0, vel.Y))
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/faiface/pixel"
+
+	"github.com/remmakoshino/kirby-inspired-go/internal/entity"
+	"github.com/remmakoshino/kirby-inspired-go/internal/stage"
+)
+
+// capeStage はメタナイトの右にゲート、左にメタルブロック、頭上に足場がある小さなステージです
+type capeStage struct {
+	*stage.Stage
+	Gate     *stage.Block
+	Metal    *stage.Block
+	Platform *stage.Platform
+}
+
+// capeStart はメタナイトを置く位置です（マントの移動距離より近くにそれぞれの地形がある）
+var capeStart = pixel.V(300, 25)
+
+func newCapeStage() *capeStage {
+	s := &capeStage{Stage: stage.NewStage(800, 600)}
+	s.Gate = stage.NewBlock(stage.BlockGate, pixel.R(360, 0, 360+stage.BlockSize, 4*stage.BlockSize), 1)
+	s.Metal = stage.NewBlock(stage.BlockMetal, pixel.R(200, 0, 200+stage.BlockSize, 2*stage.BlockSize), 0)
+	s.Platform = stage.NewPlatform(250, 120, 100, 20)
+	s.AddBlock(s.Gate)
+	s.AddBlock(s.Metal)
+	s.AddPlatform(s.Platform)
+	return s
+}
+
+// capeTestGame は capeStage にメタナイトだけがいるゲームを作り、着地させます
+func capeTestGame(t *testing.T) (*Game, *capeStage, *entity.MetaKnightPlayer) {
+	t.Helper()
+	g := NewHeadlessGame()
+	g.InitializeStage(1, []string{"MetaKnight"})
+	g.Enemies = nil
+	g.WaddleDees = nil
+	g.WaddleDoos = nil
+	
+	s := newCapeStage()
+	g.Stage = s.Stage
+	
+	mk, ok := g.Characters[0].(*entity.MetaKnightPlayer)
+	if !ok {
+		t.Fatalf("character is %T, want *entity.MetaKnightPlayer", g.Characters[0])
+	}
+	mk.SetPosition(capeStart)
+	for i := 0; i < 60; i++ {
+		g.Step(1.0/60, []entity.PlayerInput{{}})
+	}
+	return g, s, mk
+}
+
+// useCape はディメンジョンマントを使い、現れるまで進めます
+func useCape(g *Game, input entity.PlayerInput) {
+	input.Evade = true
+	g.Step(1.0/60, []entity.PlayerInput{input})
+	for i := 0; i < 60; i++ {
+		g.Step(1.0/60, []entity.PlayerInput{{}})
+	}
+}
+
+func TestCapeStopsAtClosedGate(t *testing.T) {
+	g, s, mk := capeTestGame(t)
+	
+	useCape(g, entity.PlayerInput{})
+	
+	if !s.Gate.Solid() {
+		t.Fatal("gate opened without the switch")
+	}
+	if right := mk.GetBounds().Max.X; right > s.Gate.Rect.Min.X {
+		t.Errorf("Meta Knight crossed the closed gate: right edge %.1f, gate at %.1f", right, s.Gate.Rect.Min.X)
+	}
+	if mk.GetPosition().X <= capeStart.X {
+		t.Errorf("Meta Knight did not move toward the gate: %v", mk.GetPosition())
+	}
+}
+
+func TestCapeStopsAtMetalBlock(t *testing.T) {
+	g, s, mk := capeTestGame(t)
+	mk.IsFacingLeft = true
+	
+	useCape(g, entity.PlayerInput{})
+	
+	if left := mk.GetBounds().Min.X; left < s.Metal.Rect.Max.X {
+		t.Errorf("Meta Knight crossed the metal block: left edge %.1f, block ends at %.1f", left, s.Metal.Rect.Max.X)
+	}
+}
+
+func TestCapeStopsUnderPlatform(t *testing.T) {
+	g, s, mk := capeTestGame(t)
+	
+	useCape(g, entity.PlayerInput{Up: true})
+	
+	if mk.GetBounds().Intersects(s.Platform.Rect) {
+		t.Errorf("Meta Knight appeared inside the platform at %v", mk.GetPosition())
+	}
+	if mk.GetPosition().Y > s.Platform.Rect.Min.Y {
+		t.Errorf("Meta Knight went through the platform to %v", mk.GetPosition())
+	}
+}
@@ -0,0 +1,267 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+
+	"github.com/remmakoshino/kirby-inspired-go/internal/ability"
+	"github.com/remmakoshino/kirby-inspired-go/internal/entity"
+	"github.com/remmakoshino/kirby-inspired-go/internal/menu"
+	"github.com/remmakoshino/kirby-inspired-go/internal/rng"
+	"github.com/remmakoshino/kirby-inspired-go/internal/stage"
+)
+
+const (
+	ArenaTomatoes  = 3   // ボスラッシュ全体で休憩部屋から取れるマキシムトマトの数
+	ArenaNextDelay = 2.0 // ボスを倒してから休憩部屋へ移るまでの時間
+	PedestalWidth  = 40.0
+	PedestalHeight = 30.0
+)
+
+// arenaStart はボスの部屋でプレイヤーが出てくる位置です
+var arenaStart = pixel.V(150, 40)
+
+// restBackground は休憩部屋の背景色です
+var restBackground = color.RGBA{R: 40, G: 60, B: 70, A: 255}
+
+// pedestalAbilities は休憩部屋の台に置かれるコピー能力の候補です
+var pedestalAbilities = []string{"speed", "fly", "jump", "fire", "stone", "hammer", "sword"}
+
+// AbilityPedestal は休憩部屋のコピー能力の台です。コピー能力を持てるキャラクターが触れると能力をもらえます（1回だけ）
+type AbilityPedestal struct {
+	Pos     pixel.Vec // 台の下端の中央
+	Ability string
+	Taken   bool
+}
+
+// GetBounds は台の当たり判定を返します
+func (p *AbilityPedestal) GetBounds() pixel.Rect {
+	return pixel.R(p.Pos.X-PedestalWidth/2, p.Pos.Y, p.Pos.X+PedestalWidth/2, p.Pos.Y+PedestalHeight+30)
+}
+
+// BossRush はボスラッシュ（THE ARENA）の進行状況を表します。
+// 部屋はボスの部屋と休憩部屋が交互に並び（偶数がボス、奇数が休憩）、体力はボスの間で引き継がれます
+type BossRush struct {
+	Bosses    int              // 戦うボスの数
+	Room      int              // 入った時の準備を済ませた部屋
+	Tomatoes  int              // 残りのマキシムトマト
+	Pedestal  *AbilityPedestal // 今いる休憩部屋の台（ボスの部屋では nil）
+	Timer     float64          // ボスを倒してからの時間
+	Best      float64          // 始めた時点の1Pのキャラクターの最速記録（なければ0）
+	NewRecord bool             // 最速記録を更新した（クリア時に決まる）
+}
+
+// InitializeBossRush は登録済みのボスと休憩部屋を並べ、最初のボスの部屋から始めます
+func (g *Game) InitializeBossRush(characters []string) {
+	g.Mode = menu.ModeBossRush
+	g.Versus = nil
+	g.Survival = nil
+	g.TimeAttack = nil
+	g.CurrentStage = 0
+	g.PlayerCharacters = characters
+	g.GameOver = false
+	g.Victory = false
+	g.StageTime = 0
+	g.Pickups = nil
+	g.ProgressRecorded = false
+	g.Score = 0
+	
+	bosses := entity.BossInfos()
+	g.Rooms = nil
+	for i, info := range bosses {
+		fight := &Room{Stage: stage.CreateArenaStage(WindowWidth, WindowHeight)}
+		fight.Boss = info.New(pixel.V(fight.Stage.Width-200, 200))
+		g.Rooms = append(g.Rooms, fight)
+		if i < len(bosses)-1 {
+			g.Rooms = append(g.Rooms, newRestRoom(len(g.Rooms)+1))
+		}
+	}
+	g.loadRoom(0)
+	g.Transition = nil
+	g.DoorHeld = nil
+	g.DeathTimer = 0
+	g.Projectiles = nil
+	
+	g.Characters = []entity.PlayableCharacter{}
+	for i, id := range characters {
+		offset := float64(i) * 60
+		if c := entity.NewPlayableCharacter(id, arenaStart.Add(pixel.V(offset, 160))); c != nil {
+			g.Characters = append(g.Characters, c)
+		}
+	}
+	g.Brains = make([]*entity.HelperBrain, len(g.Characters))
+	g.Camera = NewCamera(pixel.V(g.Stage.Width/2, g.Stage.Height/2))
+	g.setFallPoints(arenaStart.Add(pixel.V(0, 160)))
+	g.LevelUps = nil
+	g.applyProgression()
+	
+	g.BossRush = &BossRush{Bosses: len(bosses), Tomatoes: ArenaTomatoes}
+	if g.SaveData != nil && len(characters) > 0 {
+		g.BossRush.Best = g.SaveData.ArenaBestTimes[characters[0]]
+	}
+}
+
+// newRestRoom は次のボスの部屋（next）へ続く扉と店の屋台のある休憩部屋を作成します
+func newRestRoom(next int) *Room {
+	s := stage.NewStage(WindowWidth, WindowHeight)
+	s.Background = restBackground
+	shelf := stage.NewPlatform(WindowWidth/2-150, 140, 300, 20)
+	shelf.Color = color.RGBA{R: 200, G: 170, B: 120, A: 255}
+	s.AddPlatform(shelf)
+	s.AddDoor(stage.NewDoor(pixel.V(WindowWidth-80, 0), next, arenaStart))
+	s.AddVendor(stage.NewVendor(pixel.V(130, 0)))
+	return &Room{Stage: s}
+}
+
+// updateBossRush は部屋に入った時の準備、ボスを倒した後の移動、台の能力、全滅・クリアを処理します
+func (g *Game) updateBossRush(dt float64) {
+	run := g.BossRush
+	if run == nil {
+		return
+	}
+	
+	if g.allCharactersDefeated() {
+		g.GameOver = true
+		return
+	}
+	
+	if g.CurrentRoom != run.Room {
+		run.Room = g.CurrentRoom
+		run.Timer = 0
+		run.Pedestal = nil
+		if g.CurrentRoom%2 == 1 {
+			g.setupRestRoom()
+		}
+	}
+	
+	if run.Pedestal != nil && !run.Pedestal.Taken {
+		g.checkPedestal(run.Pedestal)
+	}
+	
+	// ボスを倒したら少し待って休憩部屋へ（最後のボスならクリア）
+	if g.Boss == nil || g.Boss.IsAlive {
+		return
+	}
+	run.Timer += dt
+	if run.Timer < ArenaNextDelay || g.Transition != nil {
+		return
+	}
+	if g.CurrentRoom+1 >= len(g.Rooms) {
+		g.Victory = true
+		for _, c := range g.Characters {
+			c.Celebrate()
+		}
+		return
+	}
+	g.Transition = &RoomTransition{Target: g.CurrentRoom + 1, Exit: arenaStart, Star: -1}
+}
+
+// setupRestRoom は休憩部屋に残りのマキシムトマトを並べ、ランダムなコピー能力の台を置きます
+func (g *Game) setupRestRoom() {
+	run := g.BossRush
+	g.Items = nil
+	for i := 0; i < run.Tomatoes; i++ {
+		x := WindowWidth/2 + (float64(i)-float64(run.Tomatoes-1)/2)*60
+		g.Items = append(g.Items, entity.NewPlacedItem(pixel.V(x, 200), entity.ItemMaxTomato))
+	}
+	run.Pedestal = &AbilityPedestal{
+		Pos:     pixel.V(300, 0),
+		Ability: pedestalAbilities[rng.Intn(len(pedestalAbilities))],
+	}
+}
+
+// checkPedestal はコピー能力を持てるキャラクターが台に触れていれば、台の能力を渡します
+func (g *Game) checkPedestal(p *AbilityPedestal) {
+	for _, c := range g.Characters {
+		if c.IsDefeated() || !c.CanCopyAbility() || !c.GetBounds().Intersects(p.GetBounds()) {
+			continue
+		}
+		if ab := ability.CreateAbilityFromType(p.Ability); ab != nil {
+			c.SetAbility(ab)
+			p.Taken = true
+			return
+		}
+	}
+}
+
+// recordBossRush はクリアしたボスラッシュの時間を1Pのキャラクターの記録としてセーブデータに書き込みます。
+// 巻き戻しで何度も呼ばれる Step ではなく、updateProgress から1回だけ呼びます
+func (g *Game) recordBossRush() {
+	run := g.BossRush
+	if run == nil || g.SaveData == nil || len(g.PlayerCharacters) == 0 {
+		return
+	}
+	run.NewRecord = g.SaveData.RecordArena(g.PlayerCharacters[0], g.StageTime)
+	g.writeSave()
+}
+
+// drawPedestal は休憩部屋のコピー能力の台を描画します（能力を取った後は星が消える）
+func (g *Game) drawPedestal() {
+	p := g.BossRush.Pedestal
+	imd := g.IMDraw
+	
+	imd.Color = color.RGBA{R: 170, G: 150, B: 190, A: 255}
+	imd.Push(p.Pos.Add(pixel.V(-PedestalWidth/2, 0)), p.Pos.Add(pixel.V(PedestalWidth/2, PedestalHeight)))
+	imd.Rectangle(0)
+	
+	if p.Taken {
+		return
+	}
+	bob := math.Sin(g.StageTime*3) * 4
+	star := p.Pos.Add(pixel.V(0, PedestalHeight+18+bob))
+	imd.Color = color.RGBA{R: 255, G: 230, B: 60, A: 255}
+	for i := 0; i < 10; i++ {
+		r := 14.0
+		if i%2 == 1 {
+			r = 6.3
+		}
+		angle := math.Pi/2 + float64(i)*math.Pi/5
+		imd.Push(star.Add(pixel.V(math.Cos(angle)*r, math.Sin(angle)*r)))
+	}
+	imd.Polygon(0)
+}
+
+// drawBossRushHUD は何体目のボスか、経過時間、残りのマキシムトマト、台の能力の名前を表示します
+func (g *Game) drawBossRushHUD() {
+	run := g.BossRush
+	
+	fight := g.CurrentRoom/2 + 1
+	bossText := text.New(pixel.V(WindowWidth-170, WindowHeight-30), g.Atlas)
+	bossText.Color = colornames.White
+	fmt.Fprintf(bossText, "BOSS %d/%d", fight, run.Bosses)
+	bossText.Draw(g.Window, pixel.IM.Scaled(bossText.Orig, 2))
+	
+	timeText := text.New(pixel.V(10, WindowHeight-125), g.Atlas)
+	timeText.Color = colornames.White
+	fmt.Fprintf(timeText, "Time: %s", formatClearTime(g.StageTime))
+	if run.Best > 0 {
+		fmt.Fprintf(timeText, "  Best: %s", formatClearTime(run.Best))
+	}
+	timeText.Draw(g.Window, pixel.IM.Scaled(timeText.Orig, 1.5))
+	
+	tomatoText := text.New(pixel.V(10, WindowHeight-145), g.Atlas)
+	tomatoText.Color = colornames.Lightgreen
+	fmt.Fprintf(tomatoText, "Maxim Tomatoes: %d", run.Tomatoes)
+	tomatoText.Draw(g.Window, pixel.IM.Scaled(tomatoText.Orig, 1.5))
+	
+	if p := run.Pedestal; p != nil && !p.Taken {
+		pos := g.Camera.Matrix().Project(p.Pos.Add(pixel.V(-30, PedestalHeight+45)))
+		abilityText := text.New(pos, g.Atlas)
+		abilityText.Color = colornames.Yellow
+		if ab := ability.CreateAbilityFromType(p.Ability); ab != nil {
+			fmt.Fprintf(abilityText, "%s", ab.GetName())
+		}
+		abilityText.Draw(g.Window, pixel.IM.Scaled(abilityText.Orig, 1.5))
+	}
+}
+
+// formatClearTime はクリア時間を「分:秒.1/10秒」の文字列にします
+func formatClearTime(seconds float64) string {
+	tenths := int(seconds * 10)
+	return fmt.Sprintf("%d:%02d.%d", tenths/600, tenths/10%60, tenths%10)
+}
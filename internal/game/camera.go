@@ -0,0 +1,77 @@
+package game
+
+import (
+	"math"
+
+	"github.com/faiface/pixel"
+)
+
+// Camera は全プレイヤーが収まるように画面を追従する共有カメラです
+type Camera struct {
+	Position pixel.Vec // 画面中央が映すワールド座標
+	Zoom     float64
+	
+	// 追従の滑らかさ（大きいほど速く追いつく）
+	FollowSpeed float64
+}
+
+// NewCamera は新しいカメラを作成します
+func NewCamera(center pixel.Vec) *Camera {
+	return &Camera{
+		Position:    center,
+		Zoom:        1.0,
+		FollowSpeed: 6.0,
+	}
+}
+
+// Follow は指定した位置の中点へカメラを寄せ、ステージの外が映らないように制限します
+func (c *Camera) Follow(dt float64, targets []pixel.Vec, stageWidth, stageHeight float64) {
+	if len(targets) == 0 {
+		return
+	}
+	
+	center := pixel.ZV
+	for _, t := range targets {
+		center = center.Add(t)
+	}
+	center = center.Scaled(1 / float64(len(targets)))
+	
+	t := math.Min(1, c.FollowSpeed*dt)
+	c.Position = pixel.Lerp(c.Position, center, t)
+	c.clamp(stageWidth, stageHeight)
+}
+
+// Reset は追従せずにカメラを位置へ移します（部屋を移動した時など）
+func (c *Camera) Reset(center pixel.Vec, stageWidth, stageHeight float64) {
+	c.Position = center
+	c.clamp(stageWidth, stageHeight)
+}
+
+// clamp はステージ端でカメラを止めます
+func (c *Camera) clamp(stageWidth, stageHeight float64) {
+	halfW := WindowWidth / 2 / c.Zoom
+	halfH := WindowHeight / 2 / c.Zoom
+	c.Position.X = clampRange(c.Position.X, halfW, stageWidth-halfW)
+	c.Position.Y = clampRange(c.Position.Y, halfH, stageHeight-halfH)
+}
+
+// Matrix はワールド座標から画面座標への変換行列を返します
+func (c *Camera) Matrix() pixel.Matrix {
+	screenCenter := pixel.V(WindowWidth/2, WindowHeight/2)
+	return pixel.IM.Moved(screenCenter.Sub(c.Position)).Scaled(screenCenter, c.Zoom)
+}
+
+// Contains は位置が画面内（上下左右に margin の余裕を含む）にあるかを返します
+func (c *Camera) Contains(pos pixel.Vec, margin float64) bool {
+	halfW := WindowWidth/2/c.Zoom + margin
+	halfH := WindowHeight/2/c.Zoom + margin
+	return math.Abs(pos.X-c.Position.X) <= halfW && math.Abs(pos.Y-c.Position.Y) <= halfH
+}
+
+// clampRange は値を範囲内に収めます（範囲が逆転している場合は中央を返します）
+func clampRange(v, min, max float64) float64 {
+	if min > max {
+		return (min + max) / 2
+	}
+	return math.Max(min, math.Min(max, v))
+}
@@ -0,0 +1,440 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"github.com/faiface/pixel/pixelgl"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+
+	"github.com/remmakoshino/kirby-inspired-go/internal/ability"
+	"github.com/remmakoshino/kirby-inspired-go/internal/cutscene"
+	"github.com/remmakoshino/kirby-inspired-go/internal/entity"
+	"github.com/remmakoshino/kirby-inspired-go/internal/menu"
+)
+
+// 会話の画面の大きさ
+const (
+	LetterboxHeight = 50.0 // カットシーン中の上下の黒帯の高さ
+	DialogueWrap    = 58   // 台詞を折り返す文字数
+)
+
+// CutsceneAdvanceButtons は台詞を送る・選択肢を決めるボタンです
+var CutsceneAdvanceButtons = []pixelgl.Button{pixelgl.KeyEnter, pixelgl.KeySpace}
+
+// CutsceneSkipButtons は残りのカットシーンを飛ばすボタンです
+var CutsceneSkipButtons = []pixelgl.Button{pixelgl.KeyEscape}
+
+// playerActor は台本で1Pのキャラクターを表す話し手と顔の絵の名前です
+const playerActor = "player"
+
+// Cutscene は再生中のカットシーンです。
+// カメラと役を動かすステップは、始まった時の位置から動かします
+type Cutscene struct {
+	Runner     *cutscene.Runner
+	CameraFrom pixel.Vec   // camera のステップを始めた時のカメラの位置
+	ZoomFrom   float64     // camera のステップを始めた時のカメラの拡大率
+	ActorFrom  []pixel.Vec // move のステップを始めた時の役の位置
+}
+
+// cutsceneDirector は台本のステップをゲームの世界で演じます
+type cutsceneDirector struct {
+	g *Game
+}
+
+// cutscenesEnabled はカットシーンを流すかを返します（ストーリーのローカルプレイだけ）
+func (g *Game) cutscenesEnabled() bool {
+	return g.Mode == menu.ModeStory && g.Net == nil && g.Library != nil
+}
+
+// queueCutscene は台本があり、このステージでまだ見ていなければカットシーンを順番待ちに加えます
+func (g *Game) queueCutscene(id string) {
+	if !g.cutscenesEnabled() {
+		return
+	}
+	if _, ok := g.Library.Lookup(id); !ok {
+		return
+	}
+	for _, seen := range g.CutscenesSeen {
+		if seen == id {
+			return
+		}
+	}
+	g.CutscenesSeen = append(g.CutscenesSeen, id)
+	g.CutsceneQueue = append(g.CutsceneQueue, id)
+}
+
+// queueBossCutscene は今いる部屋のボスが生きていれば、戦う前のカットシーンを順番待ちに加えます
+func (g *Game) queueBossCutscene() {
+	if g.Boss != nil && g.Boss.IsAlive {
+		g.queueCutscene("boss-" + entity.BossID(g.Boss.Type))
+	}
+}
+
+// startCutscene は部屋の移動ややられた演出が終わっていれば、順番待ちのカットシーンを始めます
+func (g *Game) startCutscene() {
+	if g.Cutscene != nil || len(g.CutsceneQueue) == 0 || !g.cutscenesEnabled() {
+		return
+	}
+	if g.Transition != nil || g.DeathTimer > 0 || g.GameOver || g.Pause != nil || g.ShopMenu != nil {
+		return
+	}
+	id := g.CutsceneQueue[0]
+	g.CutsceneQueue = g.CutsceneQueue[1:]
+	if s, ok := g.Library.Lookup(id); ok {
+		g.Cutscene = &Cutscene{Runner: cutscene.NewRunner(s)}
+	}
+}
+
+// updateCutscene はカットシーンを進めます。終わったらカメラを1倍に戻してプレイヤーに合わせます
+func (g *Game) updateCutscene(dt float64) {
+	win := g.Window
+	d := cutsceneDirector{g}
+	r := g.Cutscene.Runner
+	
+	if g.anyJustPressed(CutsceneSkipButtons) {
+		r.Skip(d)
+	} else {
+		r.Update(dt, cutscene.Input{
+			Advance: g.anyJustPressed(CutsceneAdvanceButtons),
+			Up:      win.JustPressed(pixelgl.KeyUp) || win.JustPressed(pixelgl.KeyW),
+			Down:    win.JustPressed(pixelgl.KeyDown) || win.JustPressed(pixelgl.KeyS),
+		}, d)
+	}
+	if !r.Done {
+		return
+	}
+	
+	g.Cutscene = nil
+	g.Camera.Zoom = 1
+	if center, ok := g.actorCenter("players"); ok {
+		g.Camera.Reset(center, g.Stage.Width, g.Stage.Height)
+	}
+}
+
+// actorPositions は役の位置を返します（"boss" はボス、"players" は倒れていないプレイヤー全員）
+func (g *Game) actorPositions(actor string) []pixel.Vec {
+	switch actor {
+	case "boss":
+		if g.Boss != nil && g.Boss.IsAlive {
+			return []pixel.Vec{g.Boss.Position}
+		}
+	case "players":
+		return g.livingPlayerPositions()
+	}
+	return nil
+}
+
+// actorCenter は役の位置の中点を返します（役がいなければ false）
+func (g *Game) actorCenter(actor string) (pixel.Vec, bool) {
+	positions := g.actorPositions(actor)
+	if len(positions) == 0 {
+		return pixel.ZV, false
+	}
+	center := pixel.ZV
+	for _, p := range positions {
+		center = center.Add(p)
+	}
+	return center.Scaled(1 / float64(len(positions))), true
+}
+
+// moveActor は役を動かします（positions は actorPositions と同じ順）
+func (g *Game) moveActor(actor string, positions []pixel.Vec) {
+	switch actor {
+	case "boss":
+		if g.Boss != nil && len(positions) > 0 {
+			g.Boss.Position = positions[0]
+			g.Boss.Velocity = pixel.ZV
+		}
+	case "players":
+		i := 0
+		for _, c := range g.Characters {
+			if c.IsDefeated() || i >= len(positions) {
+				continue
+			}
+			c.SetPosition(positions[i])
+			c.SetVelocity(pixel.ZV)
+			i++
+		}
+	}
+}
+
+// cutsceneEvent は台本の出来事を起こします。"ability:<種類>" はコピーできるプレイヤー全員にコピー能力を渡します
+func (g *Game) cutsceneEvent(event string) {
+	if !strings.HasPrefix(event, "ability:") {
+		return
+	}
+	kind := strings.TrimPrefix(event, "ability:")
+	given := false
+	for i, c := range g.Characters {
+		if g.isCPU(i) || c.IsDefeated() || !c.CanCopyAbility() {
+			continue
+		}
+		if ab := ability.CreateAbilityFromType(kind); ab != nil {
+			c.SetAbility(ab)
+			given = true
+		}
+	}
+	if given {
+		g.showItemNotice(fmt.Sprintf("Got %s!", abilityName(kind)))
+	}
+}
+
+// Begin はステップの始まりに、動かす前のカメラと役の位置を覚え、出来事を起こします
+func (d cutsceneDirector) Begin(s cutscene.Step) {
+	g := d.g
+	cs := g.Cutscene
+	switch s.Kind {
+	case cutscene.KindCamera:
+		cs.CameraFrom = g.Camera.Position
+		cs.ZoomFrom = g.Camera.Zoom
+	case cutscene.KindMove:
+		cs.ActorFrom = g.actorPositions(s.Actor)
+	case cutscene.KindEvent:
+		g.cutsceneEvent(s.Event)
+	}
+}
+
+// Animate はカメラを役か位置へ寄せるか、役を横に歩かせます（始めと終わりはゆっくり動かす）
+func (d cutsceneDirector) Animate(s cutscene.Step, t float64) {
+	g := d.g
+	cs := g.Cutscene
+	ease := t * t * (3 - 2*t)
+	
+	switch s.Kind {
+	case cutscene.KindCamera:
+		target, ok := g.actorCenter(s.Actor)
+		if !ok {
+			target = pixel.V(s.X, s.Y)
+		}
+		zoom := s.Zoom
+		if zoom <= 0 {
+			zoom = 1
+		}
+		g.Camera.Zoom = cs.ZoomFrom + (zoom-cs.ZoomFrom)*ease
+		g.Camera.Reset(pixel.Lerp(cs.CameraFrom, target, ease), g.Stage.Width, g.Stage.Height)
+		
+	case cutscene.KindMove:
+		positions := make([]pixel.Vec, len(cs.ActorFrom))
+		for i, from := range cs.ActorFrom {
+			positions[i] = from.Add(pixel.V(s.DX*ease, 0))
+		}
+		g.moveActor(s.Actor, positions)
+	}
+}
+
+// speakerName は台詞の話し手の表示名を返します
+func (g *Game) speakerName(speaker string) string {
+	if speaker == playerActor && len(g.Characters) > 0 {
+		return g.Characters[0].Name()
+	}
+	return speaker
+}
+
+// portraitID は台詞の顔の絵のIDを返します
+func (g *Game) portraitID(portrait string) string {
+	if portrait == playerActor && len(g.PlayerCharacters) > 0 {
+		return g.PlayerCharacters[0]
+	}
+	return portrait
+}
+
+// wrapText は文章を単語の切れ目で width 文字ごとに折り返します
+func wrapText(s string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		if line != "" && len(line)+1+len(word) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// drawCutscene は上下の黒帯と、台詞の枠（顔の絵・話し手の名前・台詞・選択肢）を描画します
+func (g *Game) drawCutscene() {
+	r := g.Cutscene.Runner
+	
+	g.IMDraw.Color = colornames.Black
+	g.IMDraw.Push(pixel.V(0, 0), pixel.V(WindowWidth, LetterboxHeight))
+	g.IMDraw.Rectangle(0)
+	g.IMDraw.Push(pixel.V(0, WindowHeight-LetterboxHeight), pixel.V(WindowWidth, WindowHeight))
+	g.IMDraw.Rectangle(0)
+	g.IMDraw.Draw(g.Window)
+	g.IMDraw.Clear()
+	
+	s, ok := r.Current()
+	if !ok || s.Kind != cutscene.KindSay {
+		return
+	}
+	
+	// 台詞の枠と顔の絵
+	box := pixel.R(40, 30, WindowWidth-40, 220)
+	frame := pixel.R(60, 50, 210, 200)
+	g.IMDraw.Color = color.RGBA{R: 20, G: 20, B: 50, A: 235}
+	g.IMDraw.Push(box.Min, box.Max)
+	g.IMDraw.Rectangle(0)
+	g.IMDraw.Color = colornames.Gold
+	g.IMDraw.Push(box.Min, box.Max)
+	g.IMDraw.Rectangle(3)
+	g.IMDraw.Color = color.RGBA{R: 90, G: 140, B: 200, A: 255}
+	g.IMDraw.Push(frame.Min, frame.Max)
+	g.IMDraw.Rectangle(0)
+	g.IMDraw.Color = colornames.White
+	g.IMDraw.Push(frame.Min, frame.Max)
+	g.IMDraw.Rectangle(2)
+	drawPortrait(g.IMDraw, g.portraitID(s.Portrait), frame.Center())
+	g.IMDraw.Draw(g.Window)
+	g.IMDraw.Clear()
+	
+	nameText := text.New(pixel.V(frame.Max.X+20, box.Max.Y-35), g.Atlas)
+	nameText.Color = colornames.Gold
+	fmt.Fprintf(nameText, "%s", g.speakerName(s.Speaker))
+	nameText.Draw(g.Window, pixel.IM.Scaled(nameText.Orig, 2))
+	
+	lineText := text.New(pixel.V(frame.Max.X+20, box.Max.Y-70), g.Atlas)
+	lineText.Color = colornames.White
+	// 全文で折り返してから表示した文字数だけ出す（出している途中の単語で行が変わらないように）
+	visible := len([]rune(r.Visible()))
+	for _, line := range wrapText(s.Text, DialogueWrap) {
+		runes := []rune(line)
+		if visible <= 0 {
+			break
+		}
+		if visible < len(runes) {
+			fmt.Fprintln(lineText, string(runes[:visible]))
+			break
+		}
+		fmt.Fprintln(lineText, line)
+		visible -= len(runes) + 1
+	}
+	lineText.Draw(g.Window, pixel.IM.Scaled(lineText.Orig, 1.8))
+	
+	if r.TextDone() && len(s.Choices) > 0 {
+		g.drawCutsceneChoices(s.Choices, r.Choice, box)
+	}
+	
+	helpText := text.New(pixel.V(box.Max.X-230, box.Min.Y+12), g.Atlas)
+	helpText.Color = colornames.Lightgray
+	fmt.Fprintf(helpText, "ENTER: Next  ESC: Skip")
+	helpText.Draw(g.Window, pixel.IM.Scaled(helpText.Orig, 1.3))
+}
+
+// drawCutsceneChoices は台詞の枠の右上に選択肢を描画します
+func (g *Game) drawCutsceneChoices(choices []cutscene.Choice, selected int, box pixel.Rect) {
+	height := float64(len(choices))*30 + 20
+	panel := pixel.R(box.Max.X-320, box.Max.Y+10, box.Max.X, box.Max.Y+10+height)
+	
+	g.IMDraw.Color = color.RGBA{R: 20, G: 20, B: 50, A: 235}
+	g.IMDraw.Push(panel.Min, panel.Max)
+	g.IMDraw.Rectangle(0)
+	g.IMDraw.Color = colornames.Gold
+	g.IMDraw.Push(panel.Min, panel.Max)
+	g.IMDraw.Rectangle(3)
+	g.IMDraw.Draw(g.Window)
+	g.IMDraw.Clear()
+	
+	y := panel.Max.Y - 35
+	for i, c := range choices {
+		choiceText := text.New(pixel.V(panel.Min.X+20, y), g.Atlas)
+		choiceText.Color = colornames.White
+		if i == selected {
+			choiceText.Color = colornames.Yellow
+			fmt.Fprintf(choiceText, "> ")
+		} else {
+			fmt.Fprintf(choiceText, "  ")
+		}
+		fmt.Fprintf(choiceText, "%s", c.Text)
+		choiceText.Draw(g.Window, pixel.IM.Scaled(choiceText.Orig, 1.8))
+		y -= 30
+	}
+}
+
+// drawPortrait は話し手の顔の絵を描画します（知らないIDは灰色の顔）
+func drawPortrait(imd *imdraw.IMDraw, id string, c pixel.Vec) {
+	eyes := func(col color.Color, dx, dy float64, r pixel.Vec) {
+		imd.Color = col
+		imd.Push(c.Add(pixel.V(-dx, dy)), c.Add(pixel.V(dx, dy)))
+		imd.Ellipse(r, 0)
+	}
+	
+	switch id {
+	case "Kirby":
+		imd.Color = color.RGBA{R: 255, G: 170, B: 200, A: 255}
+		imd.Push(c)
+		imd.Circle(55, 0)
+		imd.Color = color.RGBA{R: 255, G: 110, B: 150, A: 255}
+		imd.Push(c.Add(pixel.V(-30, -14)), c.Add(pixel.V(30, -14)))
+		imd.Ellipse(pixel.V(10, 5), 0)
+		eyes(color.RGBA{R: 30, G: 30, B: 90, A: 255}, 13, 10, pixel.V(6, 14))
+		
+	case "MetaKnight":
+		imd.Color = color.RGBA{R: 40, G: 40, B: 110, A: 255}
+		imd.Push(c)
+		imd.Circle(55, 0)
+		imd.Color = color.RGBA{R: 200, G: 200, B: 215, A: 255}
+		imd.Push(c)
+		imd.Circle(42, 0)
+		imd.Color = color.RGBA{R: 30, G: 30, B: 40, A: 255}
+		imd.Push(c.Add(pixel.V(-32, -2)), c.Add(pixel.V(32, 16)))
+		imd.Rectangle(0)
+		eyes(colornames.Yellow, 13, 7, pixel.V(6, 6))
+		
+	case "Dedede":
+		imd.Color = color.RGBA{R: 250, G: 200, B: 160, A: 255}
+		imd.Push(c.Add(pixel.V(0, -8)))
+		imd.Circle(48, 0)
+		imd.Color = color.RGBA{R: 200, G: 40, B: 50, A: 255}
+		imd.Push(c.Add(pixel.V(-44, 24)), c.Add(pixel.V(44, 24)), c.Add(pixel.V(30, 62)), c.Add(pixel.V(-30, 62)))
+		imd.Polygon(0)
+		imd.Color = colornames.Gold
+		imd.Push(c.Add(pixel.V(-44, 20)), c.Add(pixel.V(44, 30)))
+		imd.Rectangle(0)
+		imd.Color = colornames.White
+		imd.Push(c.Add(pixel.V(0, 66)))
+		imd.Circle(8, 0)
+		eyes(color.RGBA{R: 30, G: 30, B: 60, A: 255}, 14, 4, pixel.V(5, 10))
+		imd.Color = color.RGBA{R: 250, G: 190, B: 50, A: 255}
+		imd.Push(c.Add(pixel.V(0, -24)))
+		imd.Ellipse(pixel.V(18, 9), 0)
+		
+	case "BandanaDee", "WaddleDee":
+		imd.Color = color.RGBA{R: 235, G: 140, B: 60, A: 255}
+		imd.Push(c)
+		imd.Circle(52, 0)
+		imd.Color = color.RGBA{R: 250, G: 215, B: 170, A: 255}
+		imd.Push(c.Add(pixel.V(0, -8)))
+		imd.Ellipse(pixel.V(40, 32), 0)
+		eyes(color.RGBA{R: 40, G: 30, B: 30, A: 255}, 12, -4, pixel.V(5, 11))
+		if id == "BandanaDee" {
+			imd.Color = color.RGBA{R: 40, G: 80, B: 200, A: 255}
+			imd.Push(c)
+			imd.CircleArc(46, 0.35, math.Pi-0.35, 12)
+		}
+		
+	default:
+		imd.Color = colornames.Gray
+		imd.Push(c)
+		imd.Circle(50, 0)
+		eyes(colornames.Black, 12, 6, pixel.V(5, 10))
+	}
+}
+
+// cutsceneID は台本のIDを組み立てます（"intro-4" など）
+func cutsceneID(kind string, stageNum int) string {
+	return kind + "-" + strconv.Itoa(stageNum)
+}
@@ -3,7 +3,7 @@ package game
 import (
 	"fmt"
 	"image/color"
-	"math/rand"
+	"math"
 	"time"
 
 	"github.com/faiface/pixel"
@@ -14,8 +14,12 @@ import (
 	"golang.org/x/image/font/basicfont"
 
 	"github.com/remmakoshino/kirby-inspired-go/internal/ability"
+	"github.com/remmakoshino/kirby-inspired-go/internal/cutscene"
 	"github.com/remmakoshino/kirby-inspired-go/internal/entity"
+	"github.com/remmakoshino/kirby-inspired-go/internal/inventory"
 	"github.com/remmakoshino/kirby-inspired-go/internal/menu"
+	"github.com/remmakoshino/kirby-inspired-go/internal/rng"
+	"github.com/remmakoshino/kirby-inspired-go/internal/save"
 	"github.com/remmakoshino/kirby-inspired-go/internal/stage"
 )
 
@@ -27,15 +31,19 @@ const (
 // Game はゲーム全体を管理します
 type Game struct {
 	Window   *pixelgl.Window
-	Player   *entity.Player
-	MetaKnight *entity.MetaKnightPlayer
+	Characters []entity.PlayableCharacter
+	Brains   []*entity.HelperBrain // キャラクターごとのCPU操作（nil は人間が操作）
 	Enemies  []*entity.Enemy
 	WaddleDees []*entity.WaddleDee
 	WaddleDoos []*entity.WaddleDoo
 	Boss     *entity.Boss
+	Projectiles []*entity.Projectile
+	Items       []*entity.Item
+	Spawners    []*Spawner // 今いる部屋の敵の出現地点
 	Stage    *stage.Stage
 	IMDraw   *imdraw.IMDraw
 	Score    int
+	Lives    int // 残り人数（1UP で増える）
 	GameOver bool
 	Victory  bool
 	
@@ -44,7 +52,57 @@ type Game struct {
 	
 	// ステージ情報
 	CurrentStage int
-	PlayerCharacter string // "Kirby" or "MetaKnight"
+	Rooms        []*Room         // ステージの部屋（今いる部屋は nil で、Stage や Enemies が持つ）
+	CurrentRoom  int
+	Transition   *RoomTransition // 部屋の移動中（移動していなければ nil）
+	DoorHeld     []bool          // プレイヤーごとに前のフレームで上を押していたか（扉に続けて入らないため）
+	Respawn      RespawnPoint    // やられた時に復帰する中間ポイント
+	DeathTimer   float64         // 全員がやられてからの経過時間（やられていなければ 0）
+	StageTime    float64           // ステージ開始からの経過時間（クリア時間の記録に使う）
+	Pickups      []entity.ItemKind // このステージで拾ったアイテム（セーブデータに加える前）
+	PlayerCharacters []string // プレイヤーごとのキャラクターID（"Kirby", "MetaKnight" など）
+	Progress         map[string]*save.CharacterProgress // キャラクターごとのレベルと経験値（レベルを使わない時は nil）
+	LevelUps         []LevelUp                          // 表示中のレベルアップ
+	
+	// 持ち物と装備（レベルを使わない時は使えない）
+	Catalog        *inventory.Catalog // アイテムの定義
+	Bag            *inventory.Bag     // 持ち物とキャラクターごとの装備（スロットを選ぶ前は nil）
+	Pause          *PauseMenu         // ポーズ中の持ち物画面（ポーズしていなければ nil）
+	Shop           *inventory.Shop    // 屋台の品揃えと値段
+	ShopMenu       *ShopMenu          // 屋台で開いた店の画面（開いていなければ nil）
+	ItemNotice     string             // 手に入れたアイテムの通知
+	ItemNoticeTime float64            // 通知を出す残り時間
+	
+	// 会話とカットシーン（ストーリーのローカルプレイだけ）
+	Library       *cutscene.Library // 台本
+	Cutscene      *Cutscene         // 再生中のカットシーン（再生していなければ nil）
+	CutsceneQueue []string          // 順番待ちの台本のID
+	CutscenesSeen []string          // このステージで流した台本のID（同じ会話を繰り返さない）
+	
+	// 全プレイヤーを映す共有カメラ
+	Camera *Camera
+	
+	// ゲームモードと対戦の進行状況
+	Mode     menu.GameMode
+	Versus   *VersusMatch
+	Survival   *SurvivalRun
+	BossRush   *BossRush
+	TimeAttack *TimeAttackRun
+	Ghost      *save.Ghost // タイムアタックで一緒に走る自己ベストのゴースト（なければ nil）
+	
+	// ネット対戦（ローカルプレイ中は nil）
+	Net *Netplay
+	
+	// セーブデータ（保存先が使えない場合は Saves が nil）
+	Saves            *save.Store
+	SaveSlot         int
+	SaveData         *save.Data // 選んだスロットのデータ（スロットを選ぶ前は nil）
+	ProgressRecorded bool       // このステージのクリアを記録済み
+	
+	// デバッグ用のセーブステート（F5で保存、F9で復元）
+	QuickSave            []byte
+	SaveStateMessage     string
+	SaveStateMessageTime float64
 	
 	// UI関連
 	Atlas *text.Atlas
@@ -52,7 +110,7 @@ type Game struct {
 
 // NewGame は新しいゲームを作成します
 func NewGame(win *pixelgl.Window) *Game {
-	rand.Seed(time.Now().UnixNano())
+	rng.Seed(time.Now().UnixNano())
 	
 	// テキスト描画用のアトラス
 	atlas := text.NewAtlas(basicfont.Face7x13, text.ASCII)
@@ -60,85 +118,85 @@ func NewGame(win *pixelgl.Window) *Game {
 	// メニューマネージャーの作成
 	menuMgr := menu.NewMenuManager(win)
 	
-	return &Game{
+	// セーブデータの保存先（使えなければ保存せずに遊ぶ）
+	saves, err := save.DefaultStore()
+	if err != nil {
+		saves = nil
+	}
+	
+	// アイテムの定義と屋台の品揃え
+	catalog := inventory.Default()
+	
+	g := &Game{
 		Window:      win,
 		IMDraw:      imdraw.New(nil),
 		Score:       0,
+		Lives:       StartingLives,
 		GameOver:    false,
 		Victory:     false,
 		Atlas:       atlas,
 		MenuManager: menuMgr,
 		CurrentStage: 0,
-		PlayerCharacter: "",
+		PlayerCharacters: nil,
+		Camera:      NewCamera(pixel.V(WindowWidth/2, WindowHeight/2)),
+		Saves:       saves,
+		Catalog:     catalog,
+		Shop:        inventory.DefaultShop(catalog),
+		Library:     cutscene.Default(),
 	}
+	g.refreshSaveSlots()
+	return g
 }
 
-// InitializeStage はステージを初期化します
-func (g *Game) InitializeStage(stageNum int, character string) {
+// InitializeStage はステージを初期化します。characters はプレイヤー順のキャラクターIDです
+func (g *Game) InitializeStage(stageNum int, characters []string) {
+	g.Mode = menu.ModeStory
+	g.Versus = nil
+	g.Survival = nil
+	g.BossRush = nil
+	g.TimeAttack = nil
+	g.Ghost = nil
 	g.CurrentStage = stageNum
-	g.PlayerCharacter = character
+	g.PlayerCharacters = characters
 	g.GameOver = false
 	g.Victory = false
-	
-	// ステージ作成
-	g.Stage = stage.CreateDefaultStage(WindowWidth, WindowHeight)
-	
-	// キャラクター作成
-	startPos := pixel.V(WindowWidth/2, 200)
-	if character == "Kirby" {
-		g.Player = entity.NewPlayer(startPos)
-		g.MetaKnight = nil
-	} else {
-		g.MetaKnight = entity.NewMetaKnightPlayer(startPos)
-		g.Player = nil
-	}
-	
-	// ステージに応じた敵とボスを配置
-	g.setupStageEnemies(stageNum)
-}
-
-// setupStageEnemies はステージごとの敵を配置
-func (g *Game) setupStageEnemies(stageNum int) {
-	g.Enemies = []*entity.Enemy{}
-	g.WaddleDees = []*entity.WaddleDee{}
-	g.WaddleDoos = []*entity.WaddleDoo{}
-	
-	if stageNum == 1 {
-		// ステージ1: ワドルディ中心
-		g.WaddleDees = append(g.WaddleDees,
-			entity.NewWaddleDee(pixel.V(200, 150)),
-			entity.NewWaddleDee(pixel.V(400, 200)),
-			entity.NewWaddleDee(pixel.V(700, 150)),
-		)
-		
-		g.WaddleDoos = append(g.WaddleDoos,
-			entity.NewWaddleDoo(pixel.V(500, 250)),
-		)
-		
-		// ボス: デデデ大王
-		g.Boss = entity.NewDededeBoss(pixel.V(WindowWidth-200, 200))
-		
-	} else if stageNum == 2 {
-		// ステージ2: ワドルドゥ中心
-		g.WaddleDees = append(g.WaddleDees,
-			entity.NewWaddleDee(pixel.V(300, 150)),
-		)
-		
-		g.WaddleDoos = append(g.WaddleDoos,
-			entity.NewWaddleDoo(pixel.V(250, 200)),
-			entity.NewWaddleDoo(pixel.V(600, 250)),
-			entity.NewWaddleDoo(pixel.V(800, 150)),
-		)
-		
-		// ボス: メタナイト
-		g.Boss = entity.NewMetaKnightBoss(pixel.V(WindowWidth-200, 200))
+	g.StageTime = 0
+	g.Pickups = nil
+	g.ProgressRecorded = false
+	
+	// ステージの部屋と敵・ボスを作成し、最初の部屋に入る
+	def := stageDefinitionFor(stageNum)
+	g.setupStageRooms(def)
+	g.Transition = nil
+	g.DoorHeld = nil
+	g.DeathTimer = 0
+	
+	// キャラクター作成（複数人の場合は横に並べる）
+	g.Characters = []entity.PlayableCharacter{}
+	for i, id := range characters {
+		offset := (float64(i) - float64(len(characters)-1)/2) * 60
+		startPos := pixel.V(WindowWidth/2+offset, 200)
+		if c := entity.NewPlayableCharacter(id, startPos); c != nil {
+			g.Characters = append(g.Characters, c)
+		}
 	}
-	
-	// 基本の敵も配置
-	g.Enemies = append(g.Enemies,
-		entity.NewEnemy(pixel.V(350, 200), entity.EnemyTypeFlyer),
-		entity.NewEnemy(pixel.V(650, 180), entity.EnemyTypeJumper),
-	)
+	g.Brains = make([]*entity.HelperBrain, len(g.Characters))
+	g.Camera = NewCamera(pixel.V(g.Stage.Width/2, g.Stage.Height/2))
+	
+	// 中間ポイントに触れるまではステージの最初から復帰する
+	g.Respawn = RespawnPoint{Room: 0, Pos: pixel.V(WindowWidth/2, 200)}
+	g.setFallPoints(g.Respawn.Pos)
+	
+	// レベルの能力値
+	g.LevelUps = nil
+	g.applyProgression()
+	
+	// ステージの始まりの会話（最初の部屋にボスがいれば続けて戦う前の会話）
+	g.Cutscene = nil
+	g.CutsceneQueue = nil
+	g.CutscenesSeen = nil
+	g.queueCutscene(cutsceneID("intro", stageNum))
+	g.queueBossCutscene()
 }
 
 // Update はゲームの状態を更新します
@@ -147,313 +205,559 @@ func (g *Game) Update(dt float64) {
 	if g.MenuManager.State != menu.StatePlaying {
 		g.MenuManager.Update(dt)
 		
+		// タイトル画面で選んだセーブスロットの操作
+		if g.MenuManager.SlotConfirmed {
+			g.applySlotAction()
+		}
+		
 		// ゲーム開始時の初期化
 		if g.MenuManager.State == menu.StatePlaying && g.Stage == nil {
-			character := "Kirby"
-			if g.MenuManager.SelectedCharacter == menu.CharacterMetaKnight {
-				character = "MetaKnight"
+			mm := g.MenuManager
+			switch mm.SelectedMode {
+			case menu.ModeVersus:
+				g.InitializeVersus(mm.SelectedCharacterIDs(), mm.VersusRule, mm.VersusStocks, mm.VersusMinutes)
+			case menu.ModeSurvival:
+				g.InitializeSurvival(mm.SelectedCharacterIDs())
+			case menu.ModeBossRush:
+				g.InitializeBossRush(mm.SelectedCharacterIDs())
+			case menu.ModeTimeAttack:
+				g.InitializeTimeAttack(mm.SelectedStage, mm.SelectedCharacterIDs())
+			default:
+				g.InitializeStage(mm.SelectedStage, mm.SelectedCharacterIDs())
 			}
-			g.InitializeStage(g.MenuManager.SelectedStage, character)
 		}
 		return
 	}
 	
-	if g.GameOver || g.Victory {
-		// ゲームオーバー/クリア時はRキーでメニューに戻る
+	// ネット対戦は固定フレームでセッションが進める（決着後も巻き戻しに備えて通信を続ける）
+	// （タイムアタック中はセーブステートを使えない）
+	if g.Net != nil {
+		g.updateNetplay(dt)
+	} else if g.TimeAttack == nil {
+		g.updateSaveStates(dt)
+	}
+	g.updateProgress(dt)
+	
+	// カットシーン中は会話だけを進める（クリア後の会話も結果画面より先に流す）
+	g.startCutscene()
+	if g.Cutscene != nil {
+		g.updateCutscene(dt)
+		return
+	}
+	
+	if g.matchEnded() || g.netplayFailed() {
+		g.Pause = nil
+		g.ShopMenu = nil
+		
+		// ストーリーのゲームオーバーはCキーでステージの最初からコンティニュー（タイムアタックはゴール後もやり直せる）
+		if (g.GameOver && g.Mode == menu.ModeStory || g.Mode == menu.ModeTimeAttack) && g.Net == nil && g.Window.JustPressed(pixelgl.KeyC) {
+			g.continueStage()
+			return
+		}
+		
+		// ゲームオーバー/クリア/対戦終了時（ネット対戦の切断時も）はRキーでメニューに戻る
+		// （ストーリーはワールドマップへ）
 		if g.Window.JustPressed(pixelgl.KeyR) {
-			g.MenuManager.State = menu.StateTitleScreen
+			if g.GameOver {
+				g.Lives = StartingLives
+			}
+			g.writeSave()
+			if (g.Mode == menu.ModeStory || g.Mode == menu.ModeTimeAttack) && g.Net == nil {
+				g.MenuManager.OpenWorldMap()
+			} else {
+				g.MenuManager.State = menu.StateTitleScreen
+			}
+			g.stopNetplay()
 			g.Stage = nil
+			g.Rooms = nil
+			g.Transition = nil
+			g.DeathTimer = 0
 			g.Score = 0
+			g.Versus = nil
+			g.Survival = nil
+			g.BossRush = nil
+			g.TimeAttack = nil
+			g.Ghost = nil
+			g.Items = nil
+			g.CutsceneQueue = nil
 		}
 		return
 	}
+	if g.Net != nil {
+		return
+	}
 	
-	// プレイヤー更新
-	if g.Player != nil {
-		input := entity.PlayerInput{
-			MoveLeft:   g.Window.Pressed(pixelgl.KeyLeft) || g.Window.Pressed(pixelgl.KeyA),
-			MoveRight:  g.Window.Pressed(pixelgl.KeyRight) || g.Window.Pressed(pixelgl.KeyD),
-			Jump:       g.Window.JustPressed(pixelgl.KeySpace) || g.Window.JustPressed(pixelgl.KeyW),
-			Attack:     g.Window.JustPressed(pixelgl.KeyX) || g.Window.JustPressed(pixelgl.KeyJ),
-			UseAbility: g.Window.JustPressed(pixelgl.KeyZ) || g.Window.JustPressed(pixelgl.KeyK),
-		}
-		g.Player.Update(dt, input, g.Stage.Width, g.Stage.Height)
-		g.checkPlayerPlatformCollision()
-		
-		if g.Player.CurrentAbility != nil {
-			g.Player.CurrentAbility.Update(dt)
+	// ポーズ中は持ち物画面だけを、屋台の店が開いている間は店の画面だけを動かす
+	if g.updatePause() || g.updateShop() {
+		return
+	}
+	
+	// 2Pによるヘルパーの引き継ぎ
+	g.updateHelperControl()
+	
+	inputs := make([]entity.PlayerInput, len(g.Characters))
+	for i := range g.Characters {
+		inputs[i] = g.readInput(i)
+	}
+	
+	// タイムアタックは記録が公平になるように固定フレームで進める
+	if g.TimeAttack != nil {
+		g.stepTimeAttack(dt, inputs)
+		return
+	}
+	g.Step(dt, inputs)
+}
+
+// Step はプレイヤー順の入力でゲームを dt 秒進めます。
+// ウィンドウを参照しないので、ネット対戦の再シミュレートや検証用のハーネスからも呼べます
+func (g *Game) Step(dt float64, inputs []entity.PlayerInput) {
+	if g.Stage == nil || g.matchEnded() {
+		return
+	}
+	if g.Mode != menu.ModeVersus {
+		g.StageTime += dt
+	}
+	if g.TimeAttack != nil {
+		g.tickTimeAttack(dt)
+	}
+	
+	// 部屋の移動中は演出だけを進める
+	if g.Transition != nil {
+		g.updateTransition(dt)
+		return
+	}
+	
+	// 水・風・氷の効果を全員に設定
+	g.applyEnvironment()
+	
+	// プレイヤー更新（CPUヘルパーは思考ルーチンの入力で動く）
+	for i, c := range g.Characters {
+		input := entity.PlayerInput{}
+		if brain := g.Brains[i]; brain != nil {
+			input = g.helperInput(dt, i, brain)
+		} else if i < len(inputs) {
+			input = inputs[i]
 		}
-		
-		// ゲームオーバー判定
-		if g.Player.Health <= 0 {
-			g.GameOver = true
+		c.Update(dt, input, g.Stage.Width, g.Stage.Height)
+		g.checkPlatformCollision(c)
+		g.checkBlockCollision(c)
+		g.checkTeleportPath(c)
+		for _, pr := range c.TakeProjectiles() {
+			pr.Owner = i
+			pr.Damage = entity.Damage(pr.Damage, c.GetStats().Attack, 0)
+			g.Projectiles = append(g.Projectiles, pr)
 		}
-	} else if g.MetaKnight != nil {
-		g.MetaKnight.Update(dt, g.Window)
-		g.checkMetaKnightPlatformCollision()
-		
-		// ゲームオーバー判定
-		if g.MetaKnight.Health <= 0 {
-			g.GameOver = true
+		if input.CreateHelper {
+			g.createHelper(i)
 		}
 	}
 	
-	// 敵の更新
-	playerPos := pixel.ZV
-	if g.Player != nil {
-		playerPos = g.Player.Position
-	} else if g.MetaKnight != nil {
-		playerPos = g.MetaKnight.Position
+	// 扉とワープスターと中間ポイントと屋台
+	for _, star := range g.Stage.WarpStars {
+		star.Update(dt)
 	}
+	for _, v := range g.Stage.Vendors {
+		v.Update(dt)
+	}
+	g.checkRoomEntrances(inputs)
+	g.checkCheckpoints(dt)
+	
+	// アイテムの更新と取得
+	g.updateItems(dt)
 	
+	if g.Mode == menu.ModeVersus {
+		// 対戦のルール処理（撃墜・復帰・決着）
+		g.updateVersus(dt)
+	} else {
+		// 倒れたヘルパーは退場し、倒れた仲間には触れると復活させる
+		g.removeFallenHelpers()
+		g.checkRevives()
+		
+		switch g.Mode {
+		case menu.ModeSurvival:
+			// 波の全滅と休憩（全員がやられたらすぐにゲームオーバー）
+			g.updateSurvival(dt)
+		case menu.ModeBossRush:
+			// ボスを倒したら休憩部屋へ（全員がやられたらすぐにゲームオーバー）
+			g.updateBossRush(dt)
+		default:
+			// 全員が戦闘不能になったら残り人数を減らして復帰（なくなればゲームオーバー）
+			g.updateDeath(dt)
+		}
+	}
+	
+	// 敵の更新（それぞれ一番近いプレイヤーを狙う）
 	for _, enemy := range g.Enemies {
 		if enemy.IsAlive {
-			enemy.Update(dt, playerPos, g.Stage.Width, g.Stage.Height)
+			enemy.Update(dt, g.nearestPlayerPos(enemy.Position), g.Stage.Width, g.Stage.Height)
 		}
 	}
 	
 	for _, waddleDee := range g.WaddleDees {
 		if waddleDee.IsAlive {
-			waddleDee.Update(dt, playerPos, g.Stage.Width, g.Stage.Height)
+			waddleDee.Update(dt, g.nearestPlayerPos(waddleDee.Position), g.Stage.Width, g.Stage.Height)
 		}
 	}
 	
 	for _, waddleDoo := range g.WaddleDoos {
 		if waddleDoo.IsAlive {
-			waddleDoo.Update(dt, playerPos, g.Stage.Width, g.Stage.Height)
+			waddleDoo.Update(dt, g.nearestPlayerPos(waddleDoo.Position), g.Stage.Width, g.Stage.Height)
+			g.Projectiles = append(g.Projectiles, waddleDoo.TakeProjectiles()...)
 		}
 	}
 	
 	// ボスの更新
 	if g.Boss != nil && g.Boss.IsAlive {
-		g.Boss.Update(dt, playerPos, g.Stage.Width, g.Stage.Height)
+		g.Boss.Update(dt, g.nearestPlayerPos(g.Boss.Position), g.Stage.Width, g.Stage.Height)
 	}
 	
-	// 衝突判定
+	// カメラ追従（置いていかれたヘルパーはワープ）
+	g.Camera.Follow(dt, g.livingPlayerPositions(), g.Stage.Width, g.Stage.Height)
+	g.warpStrayHelpers()
+	
+	// 飛び道具の更新
+	g.updateProjectiles(dt)
+	
+	// 衝突判定とトゲ・溶岩のダメージ
 	g.checkCollisions()
+	g.applyHazards()
+	
+	// 壊せるブロックとスイッチ
+	g.updateBlocks(dt)
 	
-	// 勝利判定（ボスを倒した）
-	if g.Boss != nil && !g.Boss.IsAlive && !g.Victory {
+	// 倒した敵のアイテムのドロップと、スポナーからの敵の出現
+	g.dropEnemyItems()
+	g.updateSpawners(dt)
+	
+	// ボスの経験値とレベルアップ、手に入れたアイテムの表示
+	g.rewardBoss()
+	g.updateLevelUps(dt)
+	g.updateItemNotice(dt)
+	
+	// 勝利判定（ボスを倒すか、ボスのいないステージでは敵を全滅させた）
+	if (g.Mode == menu.ModeStory || g.Mode == menu.ModeTimeAttack) && !g.Victory && g.stageCleared() {
 		g.Victory = true
-		g.Score += 1000
+		if g.Boss != nil {
+			g.Score += 1000
+		} else {
+			g.Score += 500
+		}
+		
+		for _, c := range g.Characters {
+			c.Celebrate()
+		}
+		g.queueCutscene(cutsceneID("victory", g.CurrentStage))
 	}
 }
 
-// checkPlayerPlatformCollision はプレイヤーとプラットフォームの衝突をチェックします
-func (g *Game) checkPlayerPlatformCollision() {
-	if g.Player == nil {
-		return
+// nearestPlayerPos は指定位置から一番近い生存プレイヤー（ヘルパーを含む）の位置を返します。
+// 全員が戦闘不能の場合は1Pの位置を返します
+func (g *Game) nearestPlayerPos(from pixel.Vec) pixel.Vec {
+	nearest := pixel.ZV
+	if len(g.Characters) > 0 {
+		nearest = g.Characters[0].GetPosition()
 	}
 	
-	playerBounds := g.Player.GetBounds()
-	
-	for _, platform := range g.Stage.Platforms {
-		if playerBounds.Intersects(platform.Rect) {
-			// 上から着地
-			if g.Player.Velocity.Y <= 0 && 
-			   g.Player.Position.Y-g.Player.Radius > platform.Rect.Max.Y-5 {
-				g.Player.Position.Y = platform.Rect.Max.Y + g.Player.Radius
-				g.Player.Velocity.Y = 0
-				g.Player.IsGrounded = true
-				g.Player.JumpCount = 0
+	bestDist := math.Inf(1)
+	for _, c := range g.Characters {
+		if c.IsDefeated() {
+			continue
+		}
+		if d := c.GetPosition().Sub(from).Len(); d < bestDist {
+			bestDist = d
+			nearest = c.GetPosition()
+		}
+	}
+	return nearest
+}
+
+// livingPlayerPositions は人間が操作する生存プレイヤーの位置一覧を返します
+func (g *Game) livingPlayerPositions() []pixel.Vec {
+	positions := make([]pixel.Vec, 0, len(g.Characters))
+	for i, c := range g.Characters {
+		if !c.IsDefeated() && !g.isCPU(i) {
+			positions = append(positions, c.GetPosition())
+		}
+	}
+	return positions
+}
+
+// checkRevives は生存プレイヤーが倒れた仲間に触れていれば復活させます
+func (g *Game) checkRevives() {
+	for _, downed := range g.Characters {
+		if !downed.IsDefeated() {
+			continue
+		}
+		for _, rescuer := range g.Characters {
+			if rescuer.IsDefeated() || !rescuer.GetBounds().Intersects(downed.GetBounds()) {
+				continue
 			}
-			// 下から衝突
-			if g.Player.Velocity.Y > 0 && 
-			   g.Player.Position.Y+g.Player.Radius < platform.Rect.Min.Y+5 {
-				g.Player.Position.Y = platform.Rect.Min.Y - g.Player.Radius
-				g.Player.Velocity.Y = 0
+			downed.Revive(downed.GetMaxHealth() / 2)
+			break
+		}
+	}
+}
+
+// matchEnded はゲームオーバー・クリア・対戦の決着のいずれかかを返します
+func (g *Game) matchEnded() bool {
+	return g.GameOver || g.Victory || g.versusFinished()
+}
+
+// versusFinished は対戦が決着したかどうかを返します
+func (g *Game) versusFinished() bool {
+	return g.Versus != nil && g.Versus.Finished
+}
+
+// updateItems はアイテムを動かし、触れたキャラクターに効果を与えます
+func (g *Game) updateItems(dt float64) {
+	solids := g.Stage.SolidRects()
+	alive := g.Items[:0]
+	for _, it := range g.Items {
+		it.Update(dt, solids, g.Stage.Width, g.Stage.Height)
+		for _, c := range g.Characters {
+			if it.IsAlive && !c.IsDefeated() && it.GetBounds().Intersects(c.GetBounds()) {
+				it.Apply(c)
+				g.collectItem(it)
+				if g.Mode == menu.ModeStory {
+					g.Pickups = append(g.Pickups, it.Kind)
+				}
 			}
 		}
+		if it.IsAlive {
+			alive = append(alive, it)
+		}
 	}
+	g.Items = alive
 }
 
-// checkMetaKnightPlatformCollision はメタナイトとプラットフォームの衝突をチェック
-func (g *Game) checkMetaKnightPlatformCollision() {
-	if g.MetaKnight == nil {
-		return
+// allCharactersDefeated は人間が操作する全キャラクターが戦闘不能かどうかを返します
+func (g *Game) allCharactersDefeated() bool {
+	for i, c := range g.Characters {
+		if !c.IsDefeated() && !g.isCPU(i) {
+			return false
+		}
 	}
-	
-	playerBounds := g.MetaKnight.GetBounds()
+	return true
+}
+
+// checkPlatformCollision はキャラクターとプラットフォームの衝突をチェックします
+func (g *Game) checkPlatformCollision(c entity.PlayableCharacter) {
+	bounds := c.GetBounds()
 	
 	for _, platform := range g.Stage.Platforms {
-		if playerBounds.Intersects(platform.Rect) {
-			// 上から着地
-			if g.MetaKnight.Velocity.Y <= 0 && 
-			   g.MetaKnight.Position.Y-g.MetaKnight.Radius > platform.Rect.Max.Y-5 {
-				g.MetaKnight.Position.Y = platform.Rect.Max.Y + g.MetaKnight.Radius
-				g.MetaKnight.Velocity.Y = 0
-				g.MetaKnight.ResetJump()
-			}
-			// 下から衝突
-			if g.MetaKnight.Velocity.Y > 0 && 
-			   g.MetaKnight.Position.Y+g.MetaKnight.Radius < platform.Rect.Min.Y+5 {
-				g.MetaKnight.Position.Y = platform.Rect.Min.Y - g.MetaKnight.Radius
-				g.MetaKnight.Velocity.Y = 0
-			}
+		if !bounds.Intersects(platform.Rect) {
+			continue
 		}
+		
+		pos := c.GetPosition()
+		vel := c.GetVelocity()
+		radius := c.GetRadius()
+		
+		// 上から着地
+		if vel.Y <= 0 && pos.Y-radius > platform.Rect.Max.Y-5 {
+			c.SetPosition(pixel.V(pos.X, platform.Rect.Max.Y+radius))
+			c.SetVelocity(pixel.V(vel.X, 0))
+			c.Land()
+		}
+		// 下から衝突
+		if vel.Y > 0 && pos.Y+radius < platform.Rect.Min.Y+5 {
+			c.SetPosition(pixel.V(pos.X, platform.Rect.Min.Y-radius))
+			c.SetVelocity(pixel.V(vel.X, 0))
+		}
+	}
+}
+
+// checkTeleportPath はディメンジョンマントなどの移動先を、途中の地形やブロックの手前で止めます
+func (g *Game) checkTeleportPath(c entity.PlayableCharacter) {
+	t, ok := c.(entity.Teleporter)
+	if !ok {
+		return
+	}
+	if target, moving := t.TeleportTarget(); moving {
+		t.SetTeleportTarget(g.Stage.SweepTo(c.GetPosition(), target, c.GetRadius()))
 	}
 }
 
 // checkCollisions は衝突判定を行います
 func (g *Game) checkCollisions() {
-	var playerBounds pixel.Rect
-	var playerPos pixel.Vec
-	var isKirby bool
-	
-	if g.Player != nil {
-		playerBounds = g.Player.GetBounds()
-		playerPos = g.Player.Position
-		isKirby = true
-	} else if g.MetaKnight != nil {
-		playerBounds = g.MetaKnight.GetBounds()
-		playerPos = g.MetaKnight.Position
-		isKirby = false
-	} else {
-		return
+	for _, c := range g.Characters {
+		if c.IsDefeated() {
+			continue
+		}
+		g.checkCharacterCollisions(c)
 	}
-	
+}
+
+// checkCharacterCollisions はキャラクター1人と敵・ボスの衝突判定を行います
+func (g *Game) checkCharacterCollisions(c entity.PlayableCharacter) {
 	// 通常の敵との衝突
 	for _, enemy := range g.Enemies {
-		if !enemy.IsAlive {
-			continue
-		}
-		
-		enemyBounds := enemy.GetBounds()
-		
-		if playerBounds.Intersects(enemyBounds) {
-			// プレイヤーが上から踏んだ場合
-			if playerPos.Y > enemy.Position.Y+10 {
-				enemy.TakeDamage(30)
-				if isKirby {
-					g.Player.Velocity.Y = 200
-				} else {
-					g.MetaKnight.Velocity.Y = 200
-				}
-				g.Score += 10
-				
-				if !enemy.IsAlive {
-					abilityType := enemy.GetAbilityType()
-					if isKirby {
-						g.Player.SetAbility(ability.CreateAbility(abilityType))
-					}
-					g.Score += 50
-				}
-			} else {
-				// 横や下から当たった場合はダメージ
-				if isKirby {
-					g.Player.TakeDamage(10)
-				} else {
-					g.MetaKnight.TakeDamage(10)
-				}
+		if g.contactEnemy(c, enemy, 10, 10) {
+			// 倒した敵の能力をコピー（能力を持たない敵からはコピーしない）
+			if ab := ability.CreateAbility(enemy.GetAbilityType()); ab != nil && c.CanCopyAbility() {
+				c.SetAbility(ab)
 			}
+			g.Score += 50
 		}
 	}
 	
 	// ワドルディとの衝突
 	for _, waddleDee := range g.WaddleDees {
-		if !waddleDee.IsAlive {
-			continue
-		}
-		
-		enemyBounds := waddleDee.GetBounds()
-		
-		if playerBounds.Intersects(enemyBounds) {
-			if playerPos.Y > waddleDee.Position.Y+10 {
-				waddleDee.TakeDamage(25)
-				if isKirby {
-					g.Player.Velocity.Y = 200
-				} else {
-					g.MetaKnight.Velocity.Y = 200
-				}
-				g.Score += 15
-			} else {
-				if isKirby {
-					g.Player.TakeDamage(8)
-				} else {
-					g.MetaKnight.TakeDamage(8)
-				}
-			}
-		}
+		g.contactEnemy(c, waddleDee.Enemy, 8, 15)
 	}
 	
 	// ワドルドゥとの衝突
 	for _, waddleDoo := range g.WaddleDoos {
-		if !waddleDoo.IsAlive {
-			continue
+		g.contactEnemy(c, waddleDoo.Enemy, 12, 20)
+	}
+	
+	// ボスとの衝突（無敵キャンディ中は触れるだけでダメージを与える）
+	if g.Boss != nil && g.Boss.IsAlive && c.HasCandy() && c.GetBounds().Intersects(g.Boss.GetBounds()) {
+		if g.Boss.MeleeHit(CandyBossDamage) {
+			g.Score += 5
+		}
+	}
+	if g.Boss != nil && g.Boss.IsAlive {
+		// ボスが攻撃中の場合
+		if c.GetBounds().Intersects(g.Boss.GetBounds()) && g.Boss.IsAttacking() {
+			c.TakeDamage(20)
 		}
 		
-		enemyBounds := waddleDoo.GetBounds()
-		
-		if playerBounds.Intersects(enemyBounds) {
-			if playerPos.Y > waddleDoo.Position.Y+10 {
-				waddleDoo.TakeDamage(30)
-				if isKirby {
-					g.Player.Velocity.Y = 200
-				} else {
-					g.MetaKnight.Velocity.Y = 200
-				}
-				g.Score += 20
-			} else {
-				if isKirby {
-					g.Player.TakeDamage(12)
-				} else {
-					g.MetaKnight.TakeDamage(12)
-				}
+		// プレイヤーの攻撃がボスに当たる
+		distance := c.GetPosition().Sub(g.Boss.Position).Len()
+		if c.IsAttacking() && distance < c.GetAttackRange()+g.Boss.Radius {
+			if g.Boss.MeleeHit(c.GetAttackDamage()) {
+				g.Score += 5
 			}
 		}
 	}
+}
+
+// contactEnemy はキャラクターと敵1体の攻撃・踏みつけ・接触ダメージを処理します。
+// この判定で敵を倒した場合は true を返します
+func (g *Game) contactEnemy(c entity.PlayableCharacter, enemy *entity.Enemy, contactDamage, score int) bool {
+	if !enemy.IsAlive {
+		return false
+	}
 	
-	// ボスとの衝突
-	if g.Boss != nil && g.Boss.IsAlive {
-		bossBounds := g.Boss.GetBounds()
-		
-		if playerBounds.Intersects(bossBounds) {
-			// ボスが攻撃中の場合
-			if g.Boss.IsAttacking() {
-				if isKirby {
-					g.Player.TakeDamage(20)
-				} else {
-					g.MetaKnight.TakeDamage(20)
-				}
+	// 攻撃中は攻撃範囲内の敵にダメージ
+	distance := c.GetPosition().Sub(enemy.Position).Len()
+	if c.IsAttacking() && distance < c.GetAttackRange()+enemy.Radius {
+		if !enemy.MeleeHit(c.GetAttackDamage()) {
+			return false
+		}
+		g.Score += score
+		return !enemy.IsAlive
+	}
+	
+	if !c.GetBounds().Intersects(enemy.GetBounds()) {
+		return false
+	}
+	
+	// 無敵キャンディ中は触れた敵を倒す
+	if c.HasCandy() {
+		enemy.TakeDamage(enemy.Health)
+		g.Score += CandyKillScore
+		return true
+	}
+	
+	// プレイヤーが上から踏んだ場合
+	if c.GetPosition().Y > enemy.Position.Y+10 {
+		enemy.TakeDamage(entity.StompDamage)
+		vel := c.GetVelocity()
+		c.SetVelocity(pixel.V(vel.X, 200))
+		g.Score += score
+		return !enemy.IsAlive
+	}
+	
+	// 横や下から当たった場合はダメージ
+	c.TakeDamage(contactDamage)
+	return false
+}
+
+// updateProjectiles は飛び道具を移動させ、敵やボスとの当たり判定を行います
+func (g *Game) updateProjectiles(dt float64) {
+	alive := g.Projectiles[:0]
+	for _, pr := range g.Projectiles {
+		pr.Update(dt, g.Stage.Width, g.Stage.Height)
+		if pr.IsAlive {
+			g.hitBlocksWithProjectile(pr)
+		}
+		if pr.IsAlive && pr.Team == entity.TeamPlayer {
+			g.hitEnemiesWithProjectile(pr)
+			// 対戦では他のプレイヤーにも当たる
+			if pr.IsAlive && g.friendlyFire() {
+				g.hitPlayerWithProjectile(pr)
 			}
+		} else if pr.IsAlive && pr.Team == entity.TeamEnemy {
+			g.hitPlayerWithProjectile(pr)
 		}
-		
-		// プレイヤーの攻撃がボスに当たる
-		attackRange := 50.0
-		if !isKirby && g.MetaKnight.IsAttacking {
-			attackRange = g.MetaKnight.GetAttackRange()
+		if pr.IsAlive {
+			alive = append(alive, pr)
+		}
+	}
+	g.Projectiles = alive
+}
+
+// hitPlayerWithProjectile は飛び道具とプレイヤーの当たり判定を行います（撃った本人には当たらない）
+func (g *Game) hitPlayerWithProjectile(pr *entity.Projectile) {
+	bounds := pr.GetBounds()
+	
+	for i, c := range g.Characters {
+		if i == pr.Owner || c.IsDefeated() || !bounds.Intersects(c.GetBounds()) {
+			continue
 		}
 		
-		distance := playerPos.Sub(g.Boss.Position).Len()
-		if distance < attackRange+g.Boss.Radius {
-			// 攻撃判定
-			if (isKirby && g.Window.JustPressed(pixelgl.KeyX)) ||
-			   (!isKirby && g.MetaKnight.IsAttacking) {
-				damage := 10
-				if !isKirby {
-					damage = g.MetaKnight.GetAttackDamage()
-				}
-				g.Boss.TakeDamage(damage)
-				g.Score += 5
-			}
+		// マッハトルネードやマントバリアは飛び道具を跳ね返す
+		if c.DeflectsProjectiles() {
+			pr.Deflect(entity.TeamPlayer)
+			pr.Owner = i
+			return
+		}
+		if g.Versus != nil {
+			g.Versus.LastHitBy[i] = pr.Owner
 		}
+		c.TakeDamage(pr.Damage)
+		pr.IsAlive = false
+		return
 	}
 }
 
-// spawnNewWave は新しい敵の波を生成します
-func (g *Game) spawnNewWave() {
-	g.Score += 100
+// hitEnemiesWithProjectile はプレイヤーの飛び道具と敵の当たり判定を行います
+func (g *Game) hitEnemiesWithProjectile(pr *entity.Projectile) {
+	bounds := pr.GetBounds()
 	
-	// ランダムに敵を配置
-	numEnemies := 3 + rand.Intn(3)
-	g.Enemies = make([]*entity.Enemy, 0, numEnemies)
+	for _, enemy := range g.Enemies {
+		if enemy.IsAlive && bounds.Intersects(enemy.GetBounds()) {
+			enemy.TakeDamage(pr.Damage)
+			g.Score += 10
+			pr.IsAlive = false
+			return
+		}
+	}
 	
-	for i := 0; i < numEnemies; i++ {
-		x := 100 + rand.Float64()*(g.Stage.Width-200)
-		y := 150 + rand.Float64()*200
-		enemyType := entity.EnemyType(rand.Intn(3))
-		
-		g.Enemies = append(g.Enemies, entity.NewEnemy(pixel.V(x, y), enemyType))
+	for _, waddleDee := range g.WaddleDees {
+		if waddleDee.IsAlive && bounds.Intersects(waddleDee.GetBounds()) {
+			waddleDee.TakeDamage(pr.Damage)
+			g.Score += 15
+			pr.IsAlive = false
+			return
+		}
+	}
+	
+	for _, waddleDoo := range g.WaddleDoos {
+		if waddleDoo.IsAlive && bounds.Intersects(waddleDoo.GetBounds()) {
+			waddleDoo.TakeDamage(pr.Damage)
+			g.Score += 20
+			pr.IsAlive = false
+			return
+		}
+	}
+	
+	if g.Boss != nil && g.Boss.IsAlive && bounds.Intersects(g.Boss.GetBounds()) {
+		g.Boss.TakeDamage(pr.Damage)
+		g.Score += 5
+		pr.IsAlive = false
 	}
 }
 
@@ -474,8 +778,9 @@ func (g *Game) Draw() {
 	g.Window.Clear(g.Stage.Background)
 	g.IMDraw.Clear()
 	
-	// ステージ描画
+	// ステージ描画（水はキャラクターの手前に描く）
 	g.Stage.Draw(g.IMDraw)
+	g.Stage.DrawHazards(g.IMDraw, g.StageTime, false)
 	
 	// 敵描画
 	for _, enemy := range g.Enemies {
@@ -490,33 +795,102 @@ func (g *Game) Draw() {
 		waddleDoo.Draw(g.IMDraw)
 	}
 	
+	// ボスラッシュの休憩部屋のコピー能力の台
+	if g.BossRush != nil && g.BossRush.Pedestal != nil {
+		g.drawPedestal()
+	}
+	
 	// ボス描画
 	if g.Boss != nil {
 		g.Boss.Draw(g.IMDraw)
 	}
 	
+	// アイテム描画
+	for _, it := range g.Items {
+		it.Draw(g.IMDraw)
+	}
+	
+	// 飛び道具描画
+	for _, pr := range g.Projectiles {
+		pr.Draw(g.IMDraw)
+	}
+	
+	// タイムアタックの自己ベストのゴースト
+	if g.Ghost != nil && g.TimeAttack != nil {
+		g.drawGhost()
+	}
+	
 	// プレイヤー描画
-	if g.Player != nil {
-		g.Player.Draw(g.IMDraw)
-	} else if g.MetaKnight != nil {
-		g.MetaKnight.Draw(g.IMDraw)
+	for _, c := range g.Characters {
+		c.Draw(g.IMDraw)
+	}
+	g.drawLevelUpRings()
+	g.Stage.DrawHazards(g.IMDraw, g.StageTime, true)
+	
+	// 倒れた仲間の目印（協力プレイ時）
+	if len(g.Characters) > 1 {
+		g.drawDownedMarkers()
 	}
 	
-	// IMDrawを画面に反映
+	// IMDrawをカメラ越しに画面へ反映し、UIは画面座標で描く
+	g.Window.SetMatrix(g.Camera.Matrix())
 	g.IMDraw.Draw(g.Window)
+	g.Window.SetMatrix(pixel.IM)
+	g.IMDraw.Clear()
 	
 	// UI描画
 	g.drawUI()
+	g.drawLevelUpText()
+	g.drawItemNotice()
+	g.drawVendorPrompt()
 	
 	// ゲームオーバー画面
 	if g.GameOver {
 		g.drawGameOver()
 	}
 	
-	// 勝利画面
-	if g.Victory {
+	// 勝利画面（クリア後の会話が終わってから）
+	if g.Victory && g.Cutscene == nil {
 		g.drawVictory()
 	}
+	
+	// 対戦の結果画面
+	if g.versusFinished() {
+		g.drawVersusResults()
+	}
+	
+	// 会話とカットシーン
+	if g.Cutscene != nil {
+		g.drawCutscene()
+	}
+	
+	// 部屋の移動の暗転
+	if g.Transition != nil {
+		g.drawTransition()
+	}
+	
+	// やられた演出
+	if g.DeathTimer > 0 {
+		g.drawDeath()
+	}
+	
+	// ネット対戦の通信状況
+	if g.Net != nil {
+		g.drawNetplayStatus()
+	}
+	
+	// ポーズ中の持ち物画面と屋台の店
+	if g.Pause != nil {
+		g.drawPause()
+	}
+	if g.ShopMenu != nil {
+		g.drawShop()
+	}
+	
+	// セーブステートの通知
+	if g.SaveStateMessageTime > 0 {
+		g.drawSaveStateMessage()
+	}
 }
 
 // drawUI はUIを描画します
@@ -525,70 +899,119 @@ func (g *Game) drawUI() {
 	scoreText := text.New(pixel.V(10, WindowHeight-30), g.Atlas)
 	scoreText.Color = colornames.White
 	fmt.Fprintf(scoreText, "Score: %d", g.Score)
+	if g.inventoryEnabled() {
+		fmt.Fprintf(scoreText, "  Coins: %d", g.Bag.Coins)
+	}
 	scoreText.Draw(g.Window, pixel.IM.Scaled(scoreText.Orig, 2))
 	
-	// HP表示
-	hpText := text.New(pixel.V(10, WindowHeight-60), g.Atlas)
-	hpText.Color = colornames.White
-	
-	var currentHP, maxHP int
-	if g.Player != nil {
-		currentHP = g.Player.Health
-		maxHP = g.Player.MaxHealth
-	} else if g.MetaKnight != nil {
-		currentHP = g.MetaKnight.Health
-		maxHP = g.MetaKnight.MaxHealth
+	for i, c := range g.Characters {
+		g.drawCharacterHUD(i, c)
 	}
 	
-	fmt.Fprintf(hpText, "HP: %d/%d", currentHP, maxHP)
-	hpText.Draw(g.Window, pixel.IM.Scaled(hpText.Orig, 2))
-	
-	// HPバー
-	g.drawHealthBar(currentHP, maxHP)
-	
 	// ボスHPバー
 	if g.Boss != nil && g.Boss.IsAlive {
 		g.drawBossHealthBar()
 	}
 	
-	// 能力表示（カービィのみ）
-	if g.Player != nil && g.Player.CurrentAbility != nil {
-		abilityText := text.New(pixel.V(10, WindowHeight-90), g.Atlas)
-		abilityText.Color = colornames.Yellow
-		fmt.Fprintf(abilityText, "Ability: %s", g.Player.CurrentAbility.GetName())
-		abilityText.Draw(g.Window, pixel.IM.Scaled(abilityText.Orig, 2))
+	// ステージ表示（対戦ではストック・撃墜数・残り時間、サバイバルでは波）
+	if g.Versus != nil {
+		g.drawVersusHUD()
+	} else if g.Survival != nil {
+		g.drawSurvivalHUD()
+	} else if g.BossRush != nil {
+		g.drawBossRushHUD()
+	} else {
+		name := stageDefinitionFor(g.CurrentStage).Name
+		stageText := text.New(pixel.V(WindowWidth-20-float64(len(name))*14, WindowHeight-30), g.Atlas)
+		stageText.Color = colornames.White
+		fmt.Fprintf(stageText, "%s", name)
+		stageText.Draw(g.Window, pixel.IM.Scaled(stageText.Orig, 2))
+		
+		// ほかの部屋を含めた残りの敵の数
+		left := 0
+		for _, room := range g.allRooms() {
+			left += room.enemiesLeft()
+		}
+		leftText := text.New(pixel.V(10, WindowHeight-125), g.Atlas)
+		leftText.Color = colornames.White
+		fmt.Fprintf(leftText, "Enemies: %d", left)
+		leftText.Draw(g.Window, pixel.IM.Scaled(leftText.Orig, 1.5))
+		
+		livesText := text.New(pixel.V(10, WindowHeight-145), g.Atlas)
+		livesText.Color = colornames.Lightgreen
+		fmt.Fprintf(livesText, "Lives: %d", g.Lives)
+		livesText.Draw(g.Window, pixel.IM.Scaled(livesText.Orig, 1.5))
+		
+		if g.TimeAttack != nil {
+			g.drawTimeAttackHUD()
+		}
 	}
 	
-	// メタナイトの場合、現在のアビリティ表示
-	if g.MetaKnight != nil && g.MetaKnight.CurrentAbility != nil {
-		abilityText := text.New(pixel.V(10, WindowHeight-90), g.Atlas)
-		abilityText.Color = colornames.Yellow
-		fmt.Fprintf(abilityText, "Weapon: %s", g.MetaKnight.CurrentAbility.GetName())
-		abilityText.Draw(g.Window, pixel.IM.Scaled(abilityText.Orig, 2))
+	// 操作説明
+	if len(g.Characters) > 0 {
+		help := g.Characters[0].ControlsHelp()
+		if g.humanCount() > 1 {
+			help = CoopControlsHelp
+		}
+		controlText := text.New(pixel.V(10, 30), g.Atlas)
+		controlText.Color = colornames.White
+		fmt.Fprintf(controlText, "%s", help)
+		controlText.Draw(g.Window, pixel.IM.Scaled(controlText.Orig, 1.5))
 	}
 	
-	// ステージ表示
-	stageText := text.New(pixel.V(WindowWidth-150, WindowHeight-30), g.Atlas)
-	stageText.Color = colornames.White
-	fmt.Fprintf(stageText, "Stage %d", g.CurrentStage)
-	stageText.Draw(g.Window, pixel.IM.Scaled(stageText.Orig, 2))
+	// CPUヘルパーがいる時は2Pの参加方法
+	if i := g.helperIndex(); i >= 0 && g.isCPU(i) {
+		helperText := text.New(pixel.V(10, 55), g.Atlas)
+		helperText.Color = colornames.Lightpink
+		fmt.Fprintf(helperText, "%s", HelperControlsHelp)
+		helperText.Draw(g.Window, pixel.IM.Scaled(helperText.Orig, 1.5))
+	}
+}
+
+// drawCharacterHUD はキャラクターのHPと能力を表示します（1Pは左端、2Pは右端）
+func (g *Game) drawCharacterHUD(player int, c entity.PlayableCharacter) {
+	hudX := 10.0
+	if player > 0 {
+		hudX = WindowWidth - 220.0
+	}
 	
-	// 操作説明
-	controlText := text.New(pixel.V(10, 30), g.Atlas)
-	controlText.Color = colornames.White
-	if g.Player != nil {
-		fmt.Fprintf(controlText, "Arrow/WASD: Move  Space: Jump  X: Attack  Z: Ability")
+	// HP表示
+	hpText := text.New(pixel.V(hudX, WindowHeight-60), g.Atlas)
+	hpText.Color = colornames.White
+	if g.isCPU(player) {
+		fmt.Fprintf(hpText, "CPU ")
+	} else if len(g.Characters) > 1 {
+		fmt.Fprintf(hpText, "%dP ", player+1)
+	}
+	if c.IsDefeated() {
+		hpText.Color = colornames.Gray
+		fmt.Fprintf(hpText, "DOWN")
 	} else {
-		fmt.Fprintf(controlText, "Arrow/WASD: Move  Space: Jump  E: Attack  Q: Special  1/2/3: Switch")
+		fmt.Fprintf(hpText, "HP: %d/%d", c.GetHealth(), c.GetMaxHealth())
+	}
+	hpText.Draw(g.Window, pixel.IM.Scaled(hpText.Orig, 2))
+	
+	// HPバー
+	g.drawHealthBar(hudX, c.GetHealth(), c.GetMaxHealth())
+	
+	// レベルと経験値（ヘルパーにはない）
+	if g.progressionEnabled() && player < len(g.PlayerCharacters) {
+		g.drawExpBar(hudX, g.progressOf(g.PlayerCharacters[player]))
+	}
+	
+	// 能力表示
+	if ab := c.GetAbility(); ab != nil {
+		abilityText := text.New(pixel.V(hudX, WindowHeight-90), g.Atlas)
+		abilityText.Color = colornames.Yellow
+		fmt.Fprintf(abilityText, "%s: %s", c.AbilityLabel(), ab.GetName())
+		abilityText.Draw(g.Window, pixel.IM.Scaled(abilityText.Orig, 2))
 	}
-	controlText.Draw(g.Window, pixel.IM.Scaled(controlText.Orig, 1.5))
 }
 
 // drawHealthBar はHPバーを描画します
-func (g *Game) drawHealthBar(currentHP, maxHP int) {
+func (g *Game) drawHealthBar(barX float64, currentHP, maxHP int) {
 	barWidth := 200.0
 	barHeight := 20.0
-	barX := 10.0
 	barY := WindowHeight - 100.0
 	
 	// 背景
@@ -607,6 +1030,19 @@ func (g *Game) drawHealthBar(currentHP, maxHP int) {
 	g.IMDraw.Draw(g.Window)
 }
 
+// drawDownedMarkers は倒れた仲間の位置に復活の目印を描画します
+func (g *Game) drawDownedMarkers() {
+	for _, c := range g.Characters {
+		if !c.IsDefeated() {
+			continue
+		}
+		pulse := 0.5 + 0.5*math.Sin(float64(time.Now().UnixNano())/1e8)
+		g.IMDraw.Color = color.RGBA{R: 255, G: 255, B: 255, A: uint8(80 + 120*pulse)}
+		g.IMDraw.Push(c.GetPosition())
+		g.IMDraw.Circle(c.GetRadius()+6+4*pulse, 3)
+	}
+}
+
 // drawBossHealthBar はボスのHPバーを描画します
 func (g *Game) drawBossHealthBar() {
 	barWidth := 400.0
@@ -630,12 +1066,7 @@ func (g *Game) drawBossHealthBar() {
 	g.IMDraw.Draw(g.Window)
 	
 	// ボス名表示
-	bossName := "Boss"
-	if g.Boss.Type == entity.BossDedede {
-		bossName = "King Dedede"
-	} else if g.Boss.Type == entity.BossMetaKnight {
-		bossName = "Meta Knight"
-	}
+	bossName := entity.BossName(g.Boss.Type)
 	
 	bossText := text.New(pixel.V(barX+barWidth/2-50, barY+barHeight+5), g.Atlas)
 	bossText.Color = colornames.Red
@@ -658,15 +1089,30 @@ func (g *Game) drawGameOver() {
 	fmt.Fprintf(gameOverText, "GAME OVER")
 	gameOverText.Draw(g.Window, pixel.IM.Scaled(gameOverText.Orig, 4))
 	
-	// スコア表示
+	// スコア表示（サバイバルはたどり着いた波、ボスラッシュはたどり着いたボスも）
 	finalScoreText := text.New(pixel.V(WindowWidth/2-80, WindowHeight/2-50), g.Atlas)
 	finalScoreText.Color = colornames.White
 	fmt.Fprintf(finalScoreText, "Final Score: %d", g.Score)
+	if g.Survival != nil {
+		fmt.Fprintf(finalScoreText, "\nReached Wave %d", g.Survival.Wave)
+		if g.Survival.NewRecord {
+			finalScoreText.Color = colornames.Gold
+			fmt.Fprintf(finalScoreText, "  NEW RECORD!")
+		}
+	}
+	if g.BossRush != nil {
+		fmt.Fprintf(finalScoreText, "\nReached Boss %d/%d", g.CurrentRoom/2+1, g.BossRush.Bosses)
+	}
 	finalScoreText.Draw(g.Window, pixel.IM.Scaled(finalScoreText.Orig, 2))
 	
 	// リスタート案内
 	restartText := text.New(pixel.V(WindowWidth/2-140, WindowHeight/2-100), g.Atlas)
 	restartText.Color = colornames.Yellow
+	if g.Mode == menu.ModeStory && g.Net == nil {
+		fmt.Fprintf(restartText, "Press C to Continue\n")
+	} else if g.TimeAttack != nil {
+		fmt.Fprintf(restartText, "Press C to Retry\n")
+	}
 	fmt.Fprintf(restartText, "Press R to Return to Menu")
 	restartText.Draw(g.Window, pixel.IM.Scaled(restartText.Orig, 2))
 }
@@ -683,23 +1129,42 @@ func (g *Game) drawVictory() {
 	// 勝利テキスト
 	victoryText := text.New(pixel.V(WindowWidth/2-100, WindowHeight/2), g.Atlas)
 	victoryText.Color = colornames.Gold
-	fmt.Fprintf(victoryText, "STAGE CLEAR!")
+	if g.BossRush != nil {
+		fmt.Fprintf(victoryText, "ARENA CLEAR!")
+	} else {
+		fmt.Fprintf(victoryText, "STAGE CLEAR!")
+	}
 	victoryText.Draw(g.Window, pixel.IM.Scaled(victoryText.Orig, 4))
 	
-	// スコア表示
+	// スコア表示（ボスラッシュはクリア時間も）
 	finalScoreText := text.New(pixel.V(WindowWidth/2-80, WindowHeight/2-50), g.Atlas)
 	finalScoreText.Color = colornames.White
 	fmt.Fprintf(finalScoreText, "Score: %d", g.Score)
+	if g.BossRush != nil {
+		fmt.Fprintf(finalScoreText, "\nTime: %s", formatClearTime(g.StageTime))
+		if g.BossRush.NewRecord {
+			finalScoreText.Color = colornames.Gold
+			fmt.Fprintf(finalScoreText, "  NEW RECORD!")
+		}
+	}
 	finalScoreText.Draw(g.Window, pixel.IM.Scaled(finalScoreText.Orig, 2))
 	
+	// タイムアタックはスプリットの表
+	if g.TimeAttack != nil {
+		g.drawTimeAttackResults()
+	}
+	
 	// リスタート案内
 	restartText := text.New(pixel.V(WindowWidth/2-140, WindowHeight/2-100), g.Atlas)
 	restartText.Color = colornames.White
+	if g.TimeAttack != nil {
+		fmt.Fprintf(restartText, "Press C to Retry\n")
+	}
 	fmt.Fprintf(restartText, "Press R to Return to Menu")
 	restartText.Draw(g.Window, pixel.IM.Scaled(restartText.Orig, 2))
 }
 
-// spawnNewWave は使用されなくなりました（削除）
+// Run はウィンドウが閉じられるまでゲームループを回します
 func (g *Game) Run() {
 	last := time.Now()
 	
@@ -0,0 +1,115 @@
+package game
+
+import (
+	"github.com/faiface/pixel"
+
+	"github.com/remmakoshino/kirby-inspired-go/internal/entity"
+	"github.com/remmakoshino/kirby-inspired-go/internal/stage"
+)
+
+// トゲと溶岩のダメージ
+const (
+	SpikeDamage    = 15
+	SpikeKnockback = 320.0 // トゲに触れた時に弾かれる速さ
+	LavaDamage     = 8     // 無敵時間が切れるたびに受けるダメージ
+	LavaBounce     = 380.0 // 溶岩で跳ね上がる速さ
+)
+
+// environmentAt は当たり判定の位置にある水・風・氷の効果を返します
+func (g *Game) environmentAt(bounds pixel.Rect) entity.Environment {
+	env := entity.Environment{OnIce: g.Stage.IceBelow(bounds)}
+	for _, h := range g.Stage.Hazards {
+		switch h.Kind {
+		case stage.HazardWater:
+			if h.Contains(bounds.Center()) {
+				env.InWater = true
+			}
+		case stage.HazardWind:
+			if h.Rect.Intersects(bounds) {
+				env.Wind = env.Wind.Add(h.Wind)
+			}
+		}
+	}
+	return env
+}
+
+// applyHazards はトゲと溶岩に触れているキャラクター・敵・ボスにダメージを与えます
+func (g *Game) applyHazards() {
+	for _, h := range g.Stage.Hazards {
+		if h.Kind != stage.HazardSpikes && h.Kind != stage.HazardLava {
+			continue
+		}
+		
+		for _, c := range g.Characters {
+			if !c.IsDefeated() && h.Rect.Intersects(c.GetBounds()) {
+				g.hurtCharacterByHazard(c, h)
+			}
+		}
+		for _, e := range g.Enemies {
+			hurtEnemyByHazard(e, h)
+		}
+		for _, w := range g.WaddleDees {
+			hurtEnemyByHazard(w.Enemy, h)
+		}
+		for _, w := range g.WaddleDoos {
+			hurtEnemyByHazard(w.Enemy, h)
+		}
+		if g.Boss != nil && g.Boss.IsAlive && h.Rect.Intersects(g.Boss.GetBounds()) {
+			g.Boss.MeleeHit(hazardDamage(h))
+		}
+	}
+}
+
+// hazardDamage は仕掛けのダメージを返します
+func hazardDamage(h *stage.Hazard) int {
+	if h.Kind == stage.HazardSpikes {
+		return SpikeDamage
+	}
+	return LavaDamage
+}
+
+// hurtCharacterByHazard はキャラクターにダメージを与えます。
+// 無敵時間中でなくダメージが通った場合、トゲからは横へ、溶岩からは上へ弾き出します
+func (g *Game) hurtCharacterByHazard(c entity.PlayableCharacter, h *stage.Hazard) {
+	before := c.GetHealth()
+	c.TakeDamage(hazardDamage(h))
+	if c.GetHealth() == before || c.IsDefeated() {
+		return
+	}
+	
+	if h.Kind == stage.HazardLava {
+		c.SetVelocity(pixel.V(c.GetVelocity().X, LavaBounce))
+		return
+	}
+	dir := 1.0
+	if c.GetPosition().X < h.Rect.Center().X {
+		dir = -1.0
+	}
+	c.SetVelocity(pixel.V(dir*SpikeKnockback, SpikeKnockback))
+}
+
+// hurtEnemyByHazard は敵にダメージを与えます（近接攻撃と同じく連続ヒットは防ぐ）
+func hurtEnemyByHazard(e *entity.Enemy, h *stage.Hazard) {
+	if e.IsAlive && h.Rect.Intersects(e.GetBounds()) {
+		e.MeleeHit(hazardDamage(h))
+	}
+}
+
+// applyEnvironment は全員にいる場所の水・風・氷の効果を設定します（更新の前に呼ぶ）
+func (g *Game) applyEnvironment() {
+	for _, c := range g.Characters {
+		c.SetEnvironment(g.environmentAt(c.GetBounds()))
+	}
+	for _, e := range g.Enemies {
+		e.SetEnvironment(g.environmentAt(e.GetBounds()))
+	}
+	for _, w := range g.WaddleDees {
+		w.SetEnvironment(g.environmentAt(w.GetBounds()))
+	}
+	for _, w := range g.WaddleDoos {
+		w.SetEnvironment(g.environmentAt(w.GetBounds()))
+	}
+	if g.Boss != nil {
+		g.Boss.SetEnvironment(g.environmentAt(g.Boss.GetBounds()))
+	}
+}
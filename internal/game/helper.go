@@ -0,0 +1,174 @@
+package game
+
+import (
+	"math"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelgl"
+
+	"github.com/remmakoshino/kirby-inspired-go/internal/entity"
+	"github.com/remmakoshino/kirby-inspired-go/internal/menu"
+)
+
+const (
+	MaxCharacters       = 2    // 画面に出せるキャラクター（プレイヤー＋ヘルパー）の上限
+	HelperWarpMargin    = 60.0 // 画面外にこれ以上はみ出したヘルパーをリーダーの元へワープさせる
+	HelperSpawnDistance = 40.0 // ヘルパーが生まれる位置（リーダーの後ろ）
+)
+
+// createHelper はプレイヤーのコピー能力をヘルパーに変えます。
+// 能力を持っていない、対戦中、またはキャラクターが満員の場合は何もしません
+func (g *Game) createHelper(owner int) {
+	c := g.Characters[owner]
+	ab := c.GetAbility()
+	if ab == nil || !c.CanCopyAbility() || c.IsDefeated() {
+		return
+	}
+	if g.Mode == menu.ModeVersus || len(g.Characters) >= MaxCharacters {
+		return
+	}
+	
+	// 能力はヘルパーに移り、カービィは素の状態に戻る
+	c.SetAbility(nil)
+	
+	behind := HelperSpawnDistance
+	if c.GetVelocity().X < 0 {
+		behind = -behind
+	}
+	pos := c.GetPosition().Add(pixel.V(-behind, 20))
+	g.Characters = append(g.Characters, entity.NewHelper(pos, ab))
+	g.Brains = append(g.Brains, entity.NewHelperBrain())
+}
+
+// isCPU はキャラクターがCPUに操作されているかを返します
+func (g *Game) isCPU(i int) bool {
+	return i < len(g.Brains) && g.Brains[i] != nil
+}
+
+// humanCount は人間が操作しているキャラクターの数を返します
+func (g *Game) humanCount() int {
+	count := 0
+	for i := range g.Characters {
+		if !g.isCPU(i) {
+			count++
+		}
+	}
+	return count
+}
+
+// helperIndex はヘルパーの番号を返します（いなければ -1）
+func (g *Game) helperIndex() int {
+	for i, c := range g.Characters {
+		if _, ok := c.(*entity.Helper); ok {
+			return i
+		}
+	}
+	return -1
+}
+
+// updateHelperControl は2Pがヘルパーの操作を引き継いだり、CPUに戻したりします
+func (g *Game) updateHelperControl() {
+	i := g.helperIndex()
+	if i < 0 {
+		return
+	}
+	
+	joined := g.anyJustPressed(HelperJoinButtons)
+	js := pixelgl.Joystick1 + pixelgl.Joystick(i)
+	if g.Window.JoystickPresent(js) && g.Window.JoystickJustPressed(js, pixelgl.ButtonStart) {
+		joined = true
+	}
+	if !joined {
+		return
+	}
+	
+	if g.isCPU(i) {
+		g.Brains[i] = nil
+	} else {
+		g.Brains[i] = entity.NewHelperBrain()
+	}
+}
+
+// helperInput はCPUヘルパーの入力を思考ルーチンで決めます
+func (g *Game) helperInput(dt float64, i int, brain *entity.HelperBrain) entity.PlayerInput {
+	self := g.Characters[i]
+	leader := g.leaderPos(self.GetPosition())
+	target, ok := g.nearestEnemyPos(self.GetPosition())
+	return brain.Think(dt, self, leader, target, ok)
+}
+
+// leaderPos はヘルパーがついていく、一番近い人間のプレイヤーの位置を返します
+func (g *Game) leaderPos(from pixel.Vec) pixel.Vec {
+	leader := from
+	bestDist := math.Inf(1)
+	for i, c := range g.Characters {
+		if g.isCPU(i) || c.IsDefeated() {
+			continue
+		}
+		if d := c.GetPosition().Sub(from).Len(); d < bestDist {
+			bestDist = d
+			leader = c.GetPosition()
+		}
+	}
+	return leader
+}
+
+// nearestEnemyPos は指定位置から一番近い生存している敵・ボスの位置を返します
+func (g *Game) nearestEnemyPos(from pixel.Vec) (pixel.Vec, bool) {
+	nearest := pixel.ZV
+	bestDist := math.Inf(1)
+	consider := func(pos pixel.Vec) {
+		if d := pos.Sub(from).Len(); d < bestDist {
+			bestDist = d
+			nearest = pos
+		}
+	}
+	
+	for _, enemy := range g.Enemies {
+		if enemy.IsAlive {
+			consider(enemy.Position)
+		}
+	}
+	for _, waddleDee := range g.WaddleDees {
+		if waddleDee.IsAlive {
+			consider(waddleDee.Position)
+		}
+	}
+	for _, waddleDoo := range g.WaddleDoos {
+		if waddleDoo.IsAlive {
+			consider(waddleDoo.Position)
+		}
+	}
+	if g.Boss != nil && g.Boss.IsAlive {
+		consider(g.Boss.Position)
+	}
+	
+	return nearest, !math.IsInf(bestDist, 1)
+}
+
+// warpStrayHelpers は画面外に取り残されたCPUヘルパーをリーダーの元へワープさせます
+func (g *Game) warpStrayHelpers() {
+	for i, c := range g.Characters {
+		if !g.isCPU(i) || c.IsDefeated() || g.Camera.Contains(c.GetPosition(), HelperWarpMargin) {
+			continue
+		}
+		leader := g.leaderPos(c.GetPosition())
+		c.SetPosition(leader.Add(pixel.V(-HelperSpawnDistance, 60)))
+		c.SetVelocity(pixel.ZV)
+	}
+}
+
+// removeFallenHelpers は倒れたヘルパーを退場させます
+func (g *Game) removeFallenHelpers() {
+	characters := g.Characters[:0]
+	brains := g.Brains[:0]
+	for i, c := range g.Characters {
+		if _, ok := c.(*entity.Helper); ok && c.IsDefeated() {
+			continue
+		}
+		characters = append(characters, c)
+		brains = append(brains, g.Brains[i])
+	}
+	g.Characters = characters
+	g.Brains = brains
+}
@@ -0,0 +1,163 @@
+package game
+
+import (
+	"github.com/faiface/pixel/pixelgl"
+
+	"github.com/remmakoshino/kirby-inspired-go/internal/entity"
+)
+
+// InputLayout はキーボードの割り当てを表します
+type InputLayout struct {
+	Left, Right, Up, Down []pixelgl.Button
+	Jump                  []pixelgl.Button
+	Attack                []pixelgl.Button
+	UseAbility            []pixelgl.Button
+	Guard                 []pixelgl.Button
+	Run                   []pixelgl.Button
+	Evade                 []pixelgl.Button
+	SwitchAbility         []pixelgl.Button // 1番目から順に能力1, 2, 3
+	CreateHelper          []pixelgl.Button
+}
+
+// SoloLayout は1人プレイ用の割り当てです（矢印キーとWASDの両方で操作できる）
+var SoloLayout = InputLayout{
+	Left:          []pixelgl.Button{pixelgl.KeyLeft, pixelgl.KeyA},
+	Right:         []pixelgl.Button{pixelgl.KeyRight, pixelgl.KeyD},
+	Up:            []pixelgl.Button{pixelgl.KeyUp},
+	Down:          []pixelgl.Button{pixelgl.KeyDown, pixelgl.KeyS},
+	Jump:          []pixelgl.Button{pixelgl.KeySpace, pixelgl.KeyW},
+	Attack:        []pixelgl.Button{pixelgl.KeyX, pixelgl.KeyJ, pixelgl.KeyE},
+	UseAbility:    []pixelgl.Button{pixelgl.KeyZ, pixelgl.KeyK, pixelgl.KeyQ},
+	Guard:         []pixelgl.Button{pixelgl.KeyC, pixelgl.KeyL},
+	Run:           []pixelgl.Button{pixelgl.KeyLeftShift},
+	Evade:         []pixelgl.Button{pixelgl.KeyF},
+	SwitchAbility: []pixelgl.Button{pixelgl.Key1, pixelgl.Key2, pixelgl.Key3},
+	CreateHelper:  []pixelgl.Button{pixelgl.KeyH},
+}
+
+// CoopLayouts は2人プレイ用の割り当てです（1P: キーボード左側, 2P: 矢印キーと右側）
+var CoopLayouts = []InputLayout{
+	{
+		Left:          []pixelgl.Button{pixelgl.KeyA},
+		Right:         []pixelgl.Button{pixelgl.KeyD},
+		Up:            []pixelgl.Button{pixelgl.KeyW},
+		Down:          []pixelgl.Button{pixelgl.KeyS},
+		Jump:          []pixelgl.Button{pixelgl.KeySpace},
+		Attack:        []pixelgl.Button{pixelgl.KeyJ},
+		UseAbility:    []pixelgl.Button{pixelgl.KeyK},
+		Guard:         []pixelgl.Button{pixelgl.KeyL},
+		Run:           []pixelgl.Button{pixelgl.KeyLeftShift},
+		Evade:         []pixelgl.Button{pixelgl.KeyF},
+		SwitchAbility: []pixelgl.Button{pixelgl.Key1, pixelgl.Key2, pixelgl.Key3},
+		CreateHelper:  []pixelgl.Button{pixelgl.KeyH},
+	},
+	{
+		Left:          []pixelgl.Button{pixelgl.KeyLeft},
+		Right:         []pixelgl.Button{pixelgl.KeyRight},
+		Up:            []pixelgl.Button{pixelgl.KeyUp},
+		Down:          []pixelgl.Button{pixelgl.KeyDown},
+		Jump:          []pixelgl.Button{pixelgl.KeyPeriod},
+		Attack:        []pixelgl.Button{pixelgl.KeyComma},
+		UseAbility:    []pixelgl.Button{pixelgl.KeyM},
+		Guard:         []pixelgl.Button{pixelgl.KeySlash},
+		Run:           []pixelgl.Button{pixelgl.KeyRightShift},
+		Evade:         []pixelgl.Button{pixelgl.KeySemicolon},
+		SwitchAbility: []pixelgl.Button{pixelgl.Key8, pixelgl.Key9, pixelgl.Key0},
+		CreateHelper:  []pixelgl.Button{pixelgl.KeyN},
+	},
+}
+
+// CoopControlsHelp は2人プレイ時の操作説明です
+const CoopControlsHelp = "1P: WASD Space J K L  2P: Arrows . , M /  Gamepad: Stick A X B RB  Touch a downed partner to revive"
+
+// HelperJoinButtons は2Pがヘルパーの操作を引き継ぐ（手放す）ボタンです
+var HelperJoinButtons = []pixelgl.Button{pixelgl.KeyEnter}
+
+// HelperControlsHelp はCPUヘルパーがいる時の操作説明です
+const HelperControlsHelp = "Enter / 2P Gamepad Start: 2P takes over the helper"
+
+// gamepadDeadZone はアナログスティックの遊びです
+const gamepadDeadZone = 0.4
+
+// readInput は指定したプレイヤーの入力をキーボードとゲームパッドから読み取ります
+func (g *Game) readInput(player int) entity.PlayerInput {
+	layout := SoloLayout
+	if g.humanCount() > 1 && player < len(CoopLayouts) {
+		layout = CoopLayouts[player]
+	}
+	
+	input := g.readLayout(layout)
+	g.mergeGamepad(&input, pixelgl.Joystick1+pixelgl.Joystick(player))
+	return input
+}
+
+// readLayout はキーボードの割り当てから入力を読み取ります
+func (g *Game) readLayout(layout InputLayout) entity.PlayerInput {
+	input := entity.PlayerInput{
+		MoveLeft:     g.anyPressed(layout.Left),
+		MoveRight:    g.anyPressed(layout.Right),
+		Jump:         g.anyJustPressed(layout.Jump),
+		Attack:       g.anyJustPressed(layout.Attack),
+		UseAbility:   g.anyJustPressed(layout.UseAbility),
+		Up:           g.anyPressed(layout.Up),
+		Down:         g.anyPressed(layout.Down),
+		Guard:        g.anyPressed(layout.Guard),
+		Run:          g.anyPressed(layout.Run),
+		Evade:        g.anyJustPressed(layout.Evade),
+		CreateHelper: g.anyJustPressed(layout.CreateHelper),
+	}
+	
+	// 能力の切り替え
+	for i, button := range layout.SwitchAbility {
+		if g.Window.JustPressed(button) {
+			input.SwitchAbility = i + 1
+			break
+		}
+	}
+	
+	return input
+}
+
+// mergeGamepad はゲームパッドが接続されていればその入力を重ねます
+func (g *Game) mergeGamepad(input *entity.PlayerInput, js pixelgl.Joystick) {
+	win := g.Window
+	if !win.JoystickPresent(js) {
+		return
+	}
+	
+	stickX := win.JoystickAxis(js, pixelgl.AxisLeftX)
+	stickY := win.JoystickAxis(js, pixelgl.AxisLeftY)
+	
+	input.MoveLeft = input.MoveLeft || stickX < -gamepadDeadZone || win.JoystickPressed(js, pixelgl.ButtonDpadLeft)
+	input.MoveRight = input.MoveRight || stickX > gamepadDeadZone || win.JoystickPressed(js, pixelgl.ButtonDpadRight)
+	// GLFWのスティックY軸は下が正
+	input.Up = input.Up || stickY < -gamepadDeadZone || win.JoystickPressed(js, pixelgl.ButtonDpadUp)
+	input.Down = input.Down || stickY > gamepadDeadZone || win.JoystickPressed(js, pixelgl.ButtonDpadDown)
+	input.Jump = input.Jump || win.JoystickJustPressed(js, pixelgl.ButtonA)
+	input.Attack = input.Attack || win.JoystickJustPressed(js, pixelgl.ButtonX)
+	input.UseAbility = input.UseAbility || win.JoystickJustPressed(js, pixelgl.ButtonB)
+	input.Guard = input.Guard || win.JoystickPressed(js, pixelgl.ButtonRightBumper)
+	input.Run = input.Run || win.JoystickPressed(js, pixelgl.ButtonLeftBumper)
+	input.Evade = input.Evade || win.JoystickJustPressed(js, pixelgl.ButtonY)
+	input.CreateHelper = input.CreateHelper || win.JoystickJustPressed(js, pixelgl.ButtonBack)
+}
+
+// anyPressed はいずれかのボタンが押されているかを返します
+func (g *Game) anyPressed(buttons []pixelgl.Button) bool {
+	for _, button := range buttons {
+		if g.Window.Pressed(button) {
+			return true
+		}
+	}
+	return false
+}
+
+// anyJustPressed はいずれかのボタンがこのフレームで押されたかを返します
+func (g *Game) anyJustPressed(buttons []pixelgl.Button) bool {
+	for _, button := range buttons {
+		if g.Window.JustPressed(button) {
+			return true
+		}
+	}
+	return false
+}
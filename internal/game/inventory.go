@@ -0,0 +1,397 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelgl"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+
+	"github.com/remmakoshino/kirby-inspired-go/internal/ability"
+	"github.com/remmakoshino/kirby-inspired-go/internal/entity"
+	"github.com/remmakoshino/kirby-inspired-go/internal/inventory"
+	"github.com/remmakoshino/kirby-inspired-go/internal/rng"
+	"github.com/remmakoshino/kirby-inspired-go/internal/save"
+)
+
+// ItemNoticeTime は手に入れたアイテムの通知を出す時間です
+const ItemNoticeTime = 2.5
+
+// PauseButtons はポーズ（持ち物画面）を開く・閉じるボタンです（1Pのゲームパッドは Start）
+var PauseButtons = []pixelgl.Button{pixelgl.KeyEscape, pixelgl.KeyP}
+
+// PauseMenu はポーズ中の持ち物画面の状態です
+type PauseMenu struct {
+	Player    int    // 装備を変える・アイテムを使うプレイヤー
+	Selection int    // 選んでいる行（装備の枠が先、その後に持ち物）
+	Message   string // 最後の操作の結果
+}
+
+// pauseRow は持ち物画面の1行です（装備の枠か、持っているアイテムか、店で覚えたコピー能力）
+type pauseRow struct {
+	Slot    inventory.Slot // 装備の枠の行（ほかの行では空）
+	Item    inventory.Item
+	Ability string // 覚えたコピー能力の行（ほかの行では空）
+}
+
+// loadInventory はスロットの持ち物と装備、コインと覚えたコピー能力をこのプレイ用に写します
+func (g *Game) loadInventory(data *save.Data) {
+	bag := &inventory.Bag{Items: data.Inventory, Equipment: data.Equipment, Coins: data.Coins, Abilities: data.Abilities}
+	g.Bag = bag.Clone()
+}
+
+// storeInventory はこのプレイの持ち物と装備、コインと覚えたコピー能力をセーブデータに書き戻します
+func (g *Game) storeInventory() {
+	if g.Bag == nil {
+		return
+	}
+	bag := g.Bag.Clone()
+	g.SaveData.Inventory = bag.Items
+	g.SaveData.Equipment = bag.Equipment
+	g.SaveData.Coins = bag.Coins
+	g.SaveData.Abilities = bag.Abilities
+}
+
+// inventoryEnabled は持ち物と装備を使えるかを返します（レベルを使うモードだけ）
+func (g *Game) inventoryEnabled() bool {
+	return g.progressionEnabled() && g.Bag != nil && g.Catalog != nil
+}
+
+// characterStats はレベルの能力値に装備の効果を足した能力値を返します
+func (g *Game) characterStats(id string, level int) entity.Stats {
+	s := entity.StatsFor(id, level)
+	if !g.inventoryEnabled() {
+		return s
+	}
+	e := g.Bag.Effects(g.Catalog, id)
+	s.MaxHealth += e.MaxHealth
+	s.Attack += e.Attack
+	s.Defense += e.Defense
+	s.Speed += e.Speed
+	s.Cooldown = e.Cooldown
+	s.JumpHeight = e.JumpHeight
+	s.GuardChance = e.GuardChance
+	return s
+}
+
+// characterHat はキャラクターが装備している帽子の見た目を返します
+func (g *Game) characterHat(id string) entity.Hat {
+	if !g.inventoryEnabled() {
+		return entity.Hat{}
+	}
+	it, ok := g.Catalog.Lookup(g.Bag.Equipped(id, inventory.SlotHat))
+	if !ok {
+		return entity.Hat{}
+	}
+	return entity.Hat{Style: it.Hat, Color: itemColor(it)}
+}
+
+// equipCharacter はプレイヤーのキャラクターにレベルと装備の能力値と帽子を設定します
+func (g *Game) equipCharacter(player int, level int) {
+	c := g.Characters[player]
+	id := g.PlayerCharacters[player]
+	c.ApplyStats(g.characterStats(id, level))
+	if w, ok := c.(entity.HatWearer); ok {
+		w.SetHat(g.characterHat(id))
+	}
+}
+
+// refreshEquipment は装備を変えたキャラクターを選んでいるプレイヤー全員の能力値と帽子を設定し直します
+func (g *Game) refreshEquipment(id string) {
+	for i := range g.Characters {
+		if i >= len(g.PlayerCharacters) || g.PlayerCharacters[i] != id {
+			continue
+		}
+		g.equipCharacter(i, g.progressOf(id).Level)
+	}
+}
+
+// pickTreasure は倒した敵が落とす宝袋の中身を選びます（持ち物を使えない時は落とさない）
+func (g *Game) pickTreasure() (string, bool) {
+	if !g.inventoryEnabled() {
+		return "", false
+	}
+	it, ok := g.Catalog.PickDrop(rng.Float64())
+	return it.ID, ok
+}
+
+// gainItem はアイテムを持ち物に加えて通知します（消費アイテムが持ちきれなければその旨を通知）
+func (g *Game) gainItem(id string) {
+	if !g.inventoryEnabled() {
+		return
+	}
+	it, ok := g.Catalog.Lookup(id)
+	if !ok {
+		return
+	}
+	if g.Bag.Add(it, 1) == 0 {
+		g.showItemNotice(fmt.Sprintf("%s: bag full", it.Name))
+		return
+	}
+	g.showItemNotice(fmt.Sprintf("GOT %s!", it.Name))
+}
+
+// rewardBossItem はボスを初めて倒した時に、そのボスの装備品を持ち物に加えます
+func (g *Game) rewardBossItem(boss entity.BossType) {
+	if !g.inventoryEnabled() {
+		return
+	}
+	it, ok := g.Catalog.BossReward(entity.BossID(boss))
+	if !ok || g.Bag.Owns(it.ID) {
+		return
+	}
+	g.gainItem(it.ID)
+}
+
+// showItemNotice は手に入れたアイテムの通知を出します
+func (g *Game) showItemNotice(message string) {
+	g.ItemNotice = message
+	g.ItemNoticeTime = ItemNoticeTime
+}
+
+// updateItemNotice は通知の残り時間を減らします
+func (g *Game) updateItemNotice(dt float64) {
+	if g.ItemNoticeTime > 0 {
+		g.ItemNoticeTime -= dt
+	}
+}
+
+// pauseRows は持ち物画面の行を返します（装備の枠、持っているアイテム、覚えたコピー能力の順）
+func (g *Game) pauseRows() []pauseRow {
+	if !g.inventoryEnabled() {
+		return nil
+	}
+	var rows []pauseRow
+	for _, slot := range inventory.Slots {
+		rows = append(rows, pauseRow{Slot: slot})
+	}
+	for _, it := range g.Catalog.Items {
+		if g.Bag.Count(it.ID) > 0 {
+			rows = append(rows, pauseRow{Item: it})
+		}
+	}
+	for _, a := range g.Bag.Abilities {
+		rows = append(rows, pauseRow{Ability: a})
+	}
+	return rows
+}
+
+// pausePressed はポーズのボタンが押されたかを返します
+func (g *Game) pausePressed() bool {
+	if g.anyJustPressed(PauseButtons) {
+		return true
+	}
+	return g.Window.JoystickPresent(pixelgl.Joystick1) && g.Window.JoystickJustPressed(pixelgl.Joystick1, pixelgl.ButtonStart)
+}
+
+// updatePause はポーズの開閉と持ち物画面の操作を処理します。ポーズ中なら true を返します
+func (g *Game) updatePause() bool {
+	if g.Pause == nil {
+		if g.pausePressed() && g.ShopMenu == nil && g.Transition == nil && g.DeathTimer == 0 {
+			g.Pause = &PauseMenu{}
+		}
+		return g.Pause != nil
+	}
+	if g.pausePressed() {
+		g.Pause = nil
+		return false
+	}
+	
+	pm := g.Pause
+	win := g.Window
+	rows := g.pauseRows()
+	
+	// 上下で行を選び、左右で操作するプレイヤーを切り替える
+	if win.JustPressed(pixelgl.KeyUp) || win.JustPressed(pixelgl.KeyW) {
+		pm.Selection--
+	}
+	if win.JustPressed(pixelgl.KeyDown) || win.JustPressed(pixelgl.KeyS) {
+		pm.Selection++
+	}
+	if len(rows) > 0 {
+		pm.Selection = (pm.Selection + len(rows)) % len(rows)
+	}
+	players := len(g.PlayerCharacters)
+	if players > 1 {
+		if win.JustPressed(pixelgl.KeyLeft) || win.JustPressed(pixelgl.KeyA) {
+			pm.Player = (pm.Player + players - 1) % players
+		}
+		if win.JustPressed(pixelgl.KeyRight) || win.JustPressed(pixelgl.KeyD) {
+			pm.Player = (pm.Player + 1) % players
+		}
+	}
+	
+	if len(rows) > 0 && (win.JustPressed(pixelgl.KeyEnter) || win.JustPressed(pixelgl.KeySpace)) {
+		g.choosePauseRow(rows[pm.Selection])
+		if rows = g.pauseRows(); pm.Selection >= len(rows) {
+			pm.Selection = len(rows) - 1
+		}
+	}
+	return true
+}
+
+// choosePauseRow は選んだ行を決定します。装備の枠は外し、装備品は装備し、消費アイテムは使い、
+// 覚えたコピー能力はキャラクターにコピーさせます
+func (g *Game) choosePauseRow(row pauseRow) {
+	pm := g.Pause
+	if pm.Player >= len(g.PlayerCharacters) || pm.Player >= len(g.Characters) {
+		return
+	}
+	id := g.PlayerCharacters[pm.Player]
+	c := g.Characters[pm.Player]
+	
+	switch {
+	case row.Slot != "":
+		g.Bag.Unequip(id, row.Slot)
+		g.refreshEquipment(id)
+		pm.Message = ""
+		
+	case row.Item.IsEquipment():
+		if err := g.Bag.Equip(g.Catalog, id, row.Item.ID); err != nil {
+			pm.Message = err.Error()
+			return
+		}
+		g.refreshEquipment(id)
+		pm.Message = fmt.Sprintf("Equipped %s", row.Item.Name)
+		
+	case row.Ability != "":
+		if c.IsDefeated() {
+			pm.Message = "Can't copy abilities while down"
+			return
+		}
+		if !c.CanCopyAbility() {
+			pm.Message = fmt.Sprintf("%s can't copy abilities", id)
+			return
+		}
+		ab := ability.CreateAbilityFromType(row.Ability)
+		if ab == nil {
+			return
+		}
+		c.SetAbility(ab)
+		pm.Message = fmt.Sprintf("Copied %s", ab.GetName())
+		
+	default:
+		if c.IsDefeated() {
+			pm.Message = "Can't use items while down"
+			return
+		}
+		g.Bag.Remove(row.Item.ID)
+		useConsumable(c, row.Item)
+		pm.Message = fmt.Sprintf("Used %s", row.Item.Name)
+	}
+}
+
+// useConsumable は消費アイテムの効果をキャラクターに与えます
+func useConsumable(c entity.PlayableCharacter, it inventory.Item) {
+	if it.FullHeal {
+		c.Heal(c.GetMaxHealth())
+	} else if it.Heal > 0 {
+		c.Heal(it.Heal)
+	}
+	if it.Candy > 0 {
+		c.EatCandy(it.Candy)
+	}
+}
+
+// itemColor はアイテムの定義の色を返します
+func itemColor(it inventory.Item) color.RGBA {
+	return color.RGBA{R: it.Color[0], G: it.Color[1], B: it.Color[2], A: 255}
+}
+
+// drawItemNotice は手に入れたアイテムの通知を画面の上に表示します
+func (g *Game) drawItemNotice() {
+	if g.ItemNoticeTime <= 0 {
+		return
+	}
+	noticeText := text.New(pixel.V(WindowWidth/2-120, WindowHeight-150), g.Atlas)
+	noticeText.Color = colornames.Gold
+	fmt.Fprintf(noticeText, "%s", g.ItemNotice)
+	noticeText.Draw(g.Window, pixel.IM.Scaled(noticeText.Orig, 2))
+}
+
+// drawPause はポーズ画面（持ち物を使えるモードでは装備と持ち物の一覧）を描画します
+func (g *Game) drawPause() {
+	pm := g.Pause
+	
+	// 画面を暗くする
+	g.IMDraw.Color = color.RGBA{R: 0, G: 0, B: 0, A: 170}
+	g.IMDraw.Push(pixel.V(0, 0), pixel.V(WindowWidth, WindowHeight))
+	g.IMDraw.Rectangle(0)
+	g.IMDraw.Draw(g.Window)
+	g.IMDraw.Clear()
+	
+	titleText := text.New(pixel.V(WindowWidth/2-90, WindowHeight-100), g.Atlas)
+	titleText.Color = colornames.Yellow
+	fmt.Fprintf(titleText, "PAUSED")
+	titleText.Draw(g.Window, pixel.IM.Scaled(titleText.Orig, 4))
+	
+	rows := g.pauseRows()
+	if rows == nil || pm.Player >= len(g.PlayerCharacters) {
+		hintText := text.New(pixel.V(WindowWidth/2-90, WindowHeight/2), g.Atlas)
+		hintText.Color = colornames.White
+		fmt.Fprintf(hintText, "ESC / P: Resume")
+		hintText.Draw(g.Window, pixel.IM.Scaled(hintText.Orig, 2))
+		return
+	}
+	id := g.PlayerCharacters[pm.Player]
+	
+	// 操作するプレイヤーとキャラクター
+	headerText := text.New(pixel.V(WindowWidth/2-300, WindowHeight-150), g.Atlas)
+	headerText.Color = colornames.Lightskyblue
+	if len(g.PlayerCharacters) > 1 {
+		fmt.Fprintf(headerText, "< %dP %s >", pm.Player+1, id)
+	} else {
+		fmt.Fprintf(headerText, "%s", id)
+	}
+	if pm.Player < len(g.Characters) {
+		s := g.Characters[pm.Player].GetStats()
+		fmt.Fprintf(headerText, "   Lv%d  HP %d  ATK %d  DEF %d", s.Level, s.MaxHealth, s.Attack, s.Defense)
+	}
+	headerText.Draw(g.Window, pixel.IM.Scaled(headerText.Orig, 1.6))
+	
+	// 装備の枠と持ち物の一覧
+	y := WindowHeight - 200.0
+	for i, row := range rows {
+		if i == len(inventory.Slots) || row.Ability != "" && rows[i-1].Ability == "" {
+			y -= 12
+		}
+		rowText := text.New(pixel.V(WindowWidth/2-300, y), g.Atlas)
+		rowText.Color = colornames.White
+		if i == pm.Selection {
+			rowText.Color = colornames.Yellow
+			fmt.Fprintf(rowText, "> ")
+		} else {
+			fmt.Fprintf(rowText, "  ")
+		}
+		if row.Slot != "" {
+			name := "-"
+			if it, ok := g.Catalog.Lookup(g.Bag.Equipped(id, row.Slot)); ok {
+				name = fmt.Sprintf("%s  (%s)", it.Name, it.Description)
+			}
+			fmt.Fprintf(rowText, "%-10s %s", row.Slot, name)
+		} else if row.Ability != "" {
+			fmt.Fprintf(rowText, "%-18s      Copy ability (learned at the shop)", abilityName(row.Ability))
+		} else {
+			fmt.Fprintf(rowText, "%-18s x%d  %s", row.Item.Name, g.Bag.Count(row.Item.ID), row.Item.Description)
+		}
+		rowText.Draw(g.Window, pixel.IM.Scaled(rowText.Orig, 1.5))
+		y -= 24
+	}
+	
+	if pm.Message != "" {
+		messageText := text.New(pixel.V(WindowWidth/2-300, y-20), g.Atlas)
+		messageText.Color = colornames.Lightgreen
+		fmt.Fprintf(messageText, "%s", pm.Message)
+		messageText.Draw(g.Window, pixel.IM.Scaled(messageText.Orig, 1.5))
+	}
+	
+	helpText := text.New(pixel.V(WindowWidth/2-300, 40), g.Atlas)
+	helpText.Color = colornames.Lightgray
+	fmt.Fprintf(helpText, "Up/Down: Select  Enter: Equip / Use / Unequip / Copy  ESC / P: Resume")
+	if len(g.PlayerCharacters) > 1 {
+		fmt.Fprintf(helpText, "  Left/Right: Player")
+	}
+	helpText.Draw(g.Window, pixel.IM.Scaled(helpText.Orig, 1.3))
+}
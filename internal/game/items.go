@@ -0,0 +1,100 @@
+package game
+
+import (
+	"github.com/remmakoshino/kirby-inspired-go/internal/entity"
+	"github.com/remmakoshino/kirby-inspired-go/internal/rng"
+)
+
+// 残り人数と無敵キャンディ
+const (
+	StartingLives   = 3
+	MaxLives        = 99
+	CandyBossDamage = 20 // 無敵キャンディ中にボスに触れた時のダメージ
+	CandyKillScore  = 30 // 無敵キャンディで敵を倒した時のスコア
+)
+
+// itemDrop は倒した敵が落とすアイテムと確率です
+type itemDrop struct {
+	Kind   entity.ItemKind
+	Chance float64
+}
+
+// enemyDrops は敵を倒した時のドロップ表です。上から順に確率を足していき、1回だけ抽選します
+var enemyDrops = []itemDrop{
+	{Kind: entity.ItemPointStar, Chance: 0.25},
+	{Kind: entity.ItemFood, Chance: 0.12},
+	{Kind: entity.ItemCandy, Chance: 0.03},
+	{Kind: entity.Item1Up, Chance: 0.01},
+	{Kind: entity.ItemTreasure, Chance: 0.04},
+}
+
+// rollDrop はドロップ表を抽選します。何も落とさない場合は false を返します
+func rollDrop() (entity.ItemKind, bool) {
+	r := rng.Float64()
+	for _, drop := range enemyDrops {
+		if r < drop.Chance {
+			return drop.Kind, true
+		}
+		r -= drop.Chance
+	}
+	return 0, false
+}
+
+// dropEnemyItems は倒されたばかりの敵のドロップを抽選し、当たればその場にアイテムを落とします。
+// 画面の下に落ちて消えた敵は何も落としません
+func (g *Game) dropEnemyItems() {
+	var defeated []*entity.Enemy
+	defeated = append(defeated, g.Enemies...)
+	for _, w := range g.WaddleDees {
+		defeated = append(defeated, w.Enemy)
+	}
+	for _, w := range g.WaddleDoos {
+		defeated = append(defeated, w.Enemy)
+	}
+	
+	for _, e := range defeated {
+		if e.IsAlive || e.DropChecked {
+			continue
+		}
+		e.DropChecked = true
+		if e.Position.Y < 0 {
+			continue
+		}
+		g.gainExp(entity.EnemyExp(e.Type))
+		g.dropCoins(e)
+		kind, ok := rollDrop()
+		if !ok {
+			continue
+		}
+		it := entity.NewDroppedItem(e.Position, kind)
+		if kind == entity.ItemTreasure {
+			// 宝袋は中身を決めて落とす（持ち物を使えないモードでは何も落とさない）
+			if it.Treasure, ok = g.pickTreasure(); !ok {
+				continue
+			}
+		}
+		g.Items = append(g.Items, it)
+	}
+}
+
+// collectItem はゲーム側が効果を与えるアイテム（1UP とポイントスター、ボスラッシュのマキシムトマトの数、宝袋、コイン）の効果を与えます
+func (g *Game) collectItem(it *entity.Item) {
+	switch it.Kind {
+	case entity.Item1Up:
+		if g.Lives < MaxLives {
+			g.Lives++
+		}
+	case entity.ItemPointStar:
+		g.Score += entity.PointStarScore
+		g.earnCoins(PointStarCoins)
+	case entity.ItemMaxTomato:
+		// ボスラッシュのマキシムトマトは取った分だけ減る
+		if g.BossRush != nil && g.BossRush.Tomatoes > 0 {
+			g.BossRush.Tomatoes--
+		}
+	case entity.ItemTreasure:
+		g.gainItem(it.Treasure)
+	case entity.ItemCoin:
+		g.earnCoins(it.Coins)
+	}
+}
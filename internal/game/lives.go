@@ -0,0 +1,122 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+
+	"github.com/remmakoshino/kirby-inspired-go/internal/menu"
+)
+
+// DeathAnimTime は全員がやられてから復帰（またはゲームオーバー）するまでの時間です
+const DeathAnimTime = 2.0
+
+// RespawnPoint はやられた時に復帰する部屋と位置です
+type RespawnPoint struct {
+	Room int
+	Pos  pixel.Vec
+}
+
+// setFallPoints は全員の穴に落ちた時に戻る位置を設定します（複数人の場合は横に並べる）
+func (g *Game) setFallPoints(pos pixel.Vec) {
+	for i, c := range g.Characters {
+		offset := (float64(i) - float64(len(g.Characters)-1)/2) * 40
+		c.SetRespawnPoint(pos.Add(pixel.V(offset, 0)))
+	}
+}
+
+// checkCheckpoints は生存しているキャラクターが触れた中間ポイントを、やられた時の復帰位置にします
+func (g *Game) checkCheckpoints(dt float64) {
+	for _, cp := range g.Stage.Checkpoints {
+		cp.Update(dt)
+		if cp.Active {
+			continue
+		}
+		for _, c := range g.Characters {
+			if c.IsDefeated() || !c.GetBounds().Intersects(cp.GetBounds()) {
+				continue
+			}
+			cp.Active = true
+			g.Respawn = RespawnPoint{Room: g.CurrentRoom, Pos: cp.SpawnPos()}
+			g.setFallPoints(cp.SpawnPos())
+			g.recordSplit()
+			break
+		}
+	}
+}
+
+// updateDeath は人間が操作する全員がやられた時の処理です。
+// やられた演出のあと、残り人数があれば1人減らして中間ポイントから復帰し、なければゲームオーバーにします
+func (g *Game) updateDeath(dt float64) {
+	if !g.allCharactersDefeated() {
+		g.DeathTimer = 0
+		return
+	}
+	
+	g.DeathTimer += dt
+	if g.DeathTimer < DeathAnimTime {
+		return
+	}
+	g.DeathTimer = 0
+	
+	if g.Lives <= 0 {
+		g.GameOver = true
+		return
+	}
+	g.Lives--
+	g.respawnAtCheckpoint()
+}
+
+// respawnAtCheckpoint は全員を体力満タンで最後の中間ポイントに戻します。
+// 倒した敵や壊したブロックなどステージの状態はそのまま残ります
+func (g *Game) respawnAtCheckpoint() {
+	g.switchRoom(&RoomTransition{Target: g.Respawn.Room, Exit: g.Respawn.Pos, Star: -1})
+	for _, c := range g.Characters {
+		if c.IsDefeated() {
+			c.Revive(c.GetMaxHealth())
+		} else {
+			c.Heal(c.GetMaxHealth())
+		}
+	}
+	
+	// 明転して再開する
+	g.Transition = &RoomTransition{Target: g.Respawn.Room, Exit: g.Respawn.Pos, Star: -1, Switched: true}
+}
+
+// continueStage はゲームオーバーからステージの最初でやり直します（残り人数とスコアは最初に戻る）。
+// タイムアタックではタイムも最初からになります
+func (g *Game) continueStage() {
+	g.Lives = StartingLives
+	g.Score = 0
+	if g.Mode == menu.ModeTimeAttack {
+		g.InitializeTimeAttack(g.CurrentStage, g.PlayerCharacters)
+		return
+	}
+	
+	// コンティニューでは見た会話を繰り返さない
+	seen := g.CutscenesSeen
+	g.InitializeStage(g.CurrentStage, g.PlayerCharacters)
+	g.CutscenesSeen = seen
+	g.CutsceneQueue = nil
+}
+
+// drawDeath はやられた演出の表示と、終わりの暗転を描画します
+func (g *Game) drawDeath() {
+	missText := text.New(pixel.V(WindowWidth/2-60, WindowHeight/2+40), g.Atlas)
+	missText.Color = colornames.White
+	fmt.Fprintf(missText, "MISS!")
+	missText.Draw(g.Window, pixel.IM.Scaled(missText.Orig, 4))
+	
+	fade := clampRange((g.DeathTimer-(DeathAnimTime-RoomFadeTime))/RoomFadeTime, 0, 1)
+	if fade <= 0 {
+		return
+	}
+	g.IMDraw.Color = color.RGBA{A: uint8(255 * fade)}
+	g.IMDraw.Push(pixel.V(0, 0))
+	g.IMDraw.Push(pixel.V(WindowWidth, WindowHeight))
+	g.IMDraw.Rectangle(0)
+	g.IMDraw.Draw(g.Window)
+}
@@ -0,0 +1,222 @@
+package game
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelgl"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+
+	"github.com/remmakoshino/kirby-inspired-go/internal/entity"
+	"github.com/remmakoshino/kirby-inspired-go/internal/menu"
+	"github.com/remmakoshino/kirby-inspired-go/internal/netcode"
+	"github.com/remmakoshino/kirby-inspired-go/internal/rng"
+)
+
+// maxNetplayCatchUp は1回の更新で追いつこうとする最大の時間（秒）です
+const maxNetplayCatchUp = 0.25
+
+// NetplayConfig はネット対戦の設定です。Listen と LocalPlayer 以外は両方のマシンで同じにします
+type NetplayConfig struct {
+	Listen      string // 待ち受けアドレス（例: ":7000"）
+	Peer        string // 相手のアドレス（例: "192.168.0.2:7000"）
+	LocalPlayer int    // このマシンで操作するプレイヤー（0: 1P, 1: 2P）
+	InputDelay  int
+	Seed        int64
+	
+	// 試合の内容
+	Mode       menu.GameMode
+	Characters []string // 1P, 2P のキャラクターID
+	Stage      int
+	Rule       menu.VersusRule
+	Stocks     int
+	Minutes    int
+	
+	// 検証用に回線の遅延・ゆらぎ・パケットロスを再現する（ゼロ値なら何もしない）
+	Link netcode.LinkConditions
+}
+
+// Netplay はネット対戦中の状態です
+type Netplay struct {
+	Session     *netcode.Session
+	LocalPlayer int
+	Err         error // 通信エラーやデシンク（発生したら進行を止める）
+	
+	accumulator float64
+	pending     entity.PlayerInput // 次のフレームに渡す入力（押した瞬間の入力は消費されるまで保持）
+}
+
+// NewHeadlessGame はウィンドウなしでゲームを作成します（ネット対戦の検証用）
+func NewHeadlessGame() *Game {
+	return &Game{
+		Camera: NewCamera(pixel.V(WindowWidth/2, WindowHeight/2)),
+	}
+}
+
+// StartMatch はモードに応じてステージか対戦アリーナを初期化します
+func (g *Game) StartMatch(cfg NetplayConfig) {
+	rng.Seed(cfg.Seed)
+	
+	// マシンごとに違うレベルを持ち込むとずれるので、ネット対戦ではレベルを使わない
+	g.Progress = nil
+	if cfg.Mode == menu.ModeVersus {
+		g.InitializeVersus(cfg.Characters, cfg.Rule, cfg.Stocks, cfg.Minutes)
+	} else {
+		g.InitializeStage(cfg.Stage, cfg.Characters)
+	}
+}
+
+// StartNetplay は相手と接続してネット対戦を始めます
+func (g *Game) StartNetplay(cfg NetplayConfig) error {
+	udp, err := netcode.NewUDPTransport(cfg.Listen)
+	if err != nil {
+		return err
+	}
+	if err := udp.Connect(cfg.Peer); err != nil {
+		udp.Close()
+		return err
+	}
+	var transport netcode.Transport = udp
+	if cfg.Link != (netcode.LinkConditions{}) {
+		transport = netcode.NewLossyTransport(udp, cfg.Link)
+	}
+	
+	g.StartMatch(cfg)
+	session, err := netcode.NewSession(g.Simulation(), transport, netcode.Config{
+		LocalPlayer: cfg.LocalPlayer,
+		InputDelay:  cfg.InputDelay,
+	})
+	if err != nil {
+		transport.Close()
+		return err
+	}
+	
+	g.Net = &Netplay{Session: session, LocalPlayer: cfg.LocalPlayer}
+	if g.MenuManager != nil {
+		g.MenuManager.State = menu.StatePlaying
+	}
+	return nil
+}
+
+// netplayFailed は通信エラーやデシンクでネット対戦が止まっているかを返します
+func (g *Game) netplayFailed() bool {
+	return g.Net != nil && g.Net.Err != nil
+}
+
+// stopNetplay はネット対戦を終了します
+func (g *Game) stopNetplay() {
+	if g.Net == nil {
+		return
+	}
+	g.Net.Session.Close()
+	g.Net = nil
+	if g.SaveData != nil {
+		g.loadProgress(g.SaveData)
+	}
+}
+
+// updateNetplay は経過時間の分だけ固定フレームでセッションを進めます
+func (g *Game) updateNetplay(dt float64) {
+	np := g.Net
+	if np.Err != nil {
+		return
+	}
+	
+	// ネット対戦ではどちらのマシンも1人用の割り当てで操作する
+	input := g.readLayout(SoloLayout)
+	g.mergeGamepad(&input, pixelgl.Joystick1)
+	np.pending = latchInput(np.pending, input)
+	
+	np.accumulator = math.Min(np.accumulator+dt, maxNetplayCatchUp)
+	for np.accumulator >= netcode.FixedStep {
+		np.accumulator -= netcode.FixedStep
+		advanced, err := np.Session.AdvanceFrame(netcode.PackInput(np.pending))
+		if err != nil {
+			np.Err = err
+			return
+		}
+		if advanced {
+			np.pending = releasePresses(input)
+		}
+	}
+}
+
+// latchInput は押し続ける入力を最新の状態にし、押した瞬間の入力はフレームが進むまで残します
+func latchInput(pending, current entity.PlayerInput) entity.PlayerInput {
+	current.Jump = current.Jump || pending.Jump
+	current.Attack = current.Attack || pending.Attack
+	current.UseAbility = current.UseAbility || pending.UseAbility
+	current.Evade = current.Evade || pending.Evade
+	current.CreateHelper = current.CreateHelper || pending.CreateHelper
+	if current.SwitchAbility == 0 {
+		current.SwitchAbility = pending.SwitchAbility
+	}
+	return current
+}
+
+// releasePresses は押した瞬間の入力を消します（1フレームで消費されるため）
+func releasePresses(in entity.PlayerInput) entity.PlayerInput {
+	in.Jump = false
+	in.Attack = false
+	in.UseAbility = false
+	in.Evade = false
+	in.CreateHelper = false
+	in.SwitchAbility = 0
+	return in
+}
+
+// Simulation はネットコードから操作するためのゲーム世界を返します
+func (g *Game) Simulation() netcode.Simulation {
+	return netSimulation{g: g}
+}
+
+// netSimulation は Game を netcode.Simulation として扱うための薄い包みです
+type netSimulation struct {
+	g *Game
+}
+
+// Step は1フレーム進めます
+func (s netSimulation) Step(inputs []netcode.Input) {
+	unpacked := make([]entity.PlayerInput, len(inputs))
+	for i, in := range inputs {
+		unpacked[i] = in.Unpack()
+	}
+	s.g.Step(netcode.FixedStep, unpacked)
+}
+
+// SaveState は世界の状態を保存します
+func (s netSimulation) SaveState() ([]byte, error) {
+	return s.g.Snapshot()
+}
+
+// LoadState は世界の状態を復元します
+func (s netSimulation) LoadState(state []byte) error {
+	return s.g.Restore(state)
+}
+
+// drawNetplayStatus はネット対戦の通信状況を表示します
+func (g *Game) drawNetplayStatus() {
+	np := g.Net
+	session := np.Session
+	
+	statusText := text.New(pixel.V(WindowWidth/2-160, 60), g.Atlas)
+	statusText.Color = colornames.Lightgreen
+	fmt.Fprintf(statusText, "NET %dP  Frame %d  Rollbacks %d  Stalls %d",
+		np.LocalPlayer+1, session.Frame(), session.Stats.Rollbacks, session.Stats.Stalls)
+	statusText.Draw(g.Window, pixel.IM.Scaled(statusText.Orig, 1.5))
+	
+	if np.Err == nil {
+		return
+	}
+	errorText := text.New(pixel.V(WindowWidth/2-160, WindowHeight/2+160), g.Atlas)
+	errorText.Color = colornames.Red
+	if frame, desynced := session.Desynced(); desynced {
+		fmt.Fprintf(errorText, "DESYNC at frame %d", frame)
+	} else {
+		fmt.Fprintf(errorText, "Connection error: %v", np.Err)
+	}
+	fmt.Fprintf(errorText, "\nPress R to Return to Menu")
+	errorText.Draw(g.Window, pixel.IM.Scaled(errorText.Orig, 2))
+}
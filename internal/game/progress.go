@@ -0,0 +1,132 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/remmakoshino/kirby-inspired-go/internal/menu"
+	"github.com/remmakoshino/kirby-inspired-go/internal/save"
+)
+
+// refreshSaveSlots はタイトル画面に出すセーブスロットの状態を読み直します
+func (g *Game) refreshSaveSlots() {
+	if g.Saves == nil {
+		return
+	}
+	g.MenuManager.SetSaveSlots(g.Saves.List())
+}
+
+// applySlotAction はタイトル画面で選んだスロットの操作（続きから・はじめから・削除）を行います
+func (g *Game) applySlotAction() {
+	mm := g.MenuManager
+	mm.SlotConfirmed = false
+	if g.Saves == nil {
+		return
+	}
+	
+	slot := mm.SelectedSlot
+	switch mm.SlotAction {
+	case menu.SlotContinue:
+		data, err := g.Saves.Load(slot)
+		if err != nil {
+			mm.Notice = fmt.Sprintf("Could not load slot %d: %v", slot+1, err)
+			return
+		}
+		g.selectSave(slot, data)
+		
+	case menu.SlotNewGame:
+		data := save.New()
+		if err := g.Saves.Save(slot, data); err != nil {
+			mm.Notice = fmt.Sprintf("Could not create slot %d: %v", slot+1, err)
+			return
+		}
+		g.selectSave(slot, data)
+		
+	case menu.SlotDelete:
+		if err := g.Saves.Delete(slot); err != nil {
+			mm.Notice = fmt.Sprintf("Could not delete slot %d: %v", slot+1, err)
+			return
+		}
+		if g.SaveData != nil && g.SaveSlot == slot {
+			g.SaveData = nil
+			g.Progress = nil
+			g.Bag = nil
+			mm.Save = nil
+		}
+		mm.Notice = fmt.Sprintf("Slot %d deleted", slot+1)
+		g.refreshSaveSlots()
+	}
+}
+
+// selectSave はスロットのデータで遊び始め、保存していた設定をメニューに戻します
+func (g *Game) selectSave(slot int, data *save.Data) {
+	g.SaveSlot = slot
+	g.SaveData = data
+	
+	mm := g.MenuManager
+	mm.Save = data
+	mm.ApplySettings(data.Settings)
+	g.loadProgress(data)
+	g.loadInventory(data)
+	mm.Notice = ""
+	mm.State = menu.StateModeSelect
+	g.refreshSaveSlots()
+}
+
+// updateProgress はプレイ時間を数え、ストーリーのステージをクリアしたらセーブデータに記録します（次の扉が開く）。
+// サバイバルではゲームオーバーになった時にたどり着いた波を、タイムアタックではゴールのタイムを記録します。
+// 巻き戻しで何度も呼ばれる Step ではなく、画面の更新ごとに1回だけ呼びます
+func (g *Game) updateProgress(dt float64) {
+	if g.SaveData == nil || g.Net != nil || g.Stage == nil {
+		return
+	}
+	g.SaveData.PlayTime += dt
+	
+	// サバイバルはゲームオーバーで到達した波を記録する
+	if g.Mode == menu.ModeSurvival && g.GameOver && !g.ProgressRecorded {
+		g.ProgressRecorded = true
+		g.recordSurvival()
+		return
+	}
+	
+	// タイムアタックはゴールしたタイムとゴーストを記録する
+	if g.Mode == menu.ModeTimeAttack && g.Victory && !g.ProgressRecorded {
+		g.ProgressRecorded = true
+		g.recordTimeAttack()
+		return
+	}
+	
+	// ボスラッシュはクリアした時間を記録する
+	if g.Mode == menu.ModeBossRush && g.Victory && !g.ProgressRecorded {
+		g.ProgressRecorded = true
+		g.recordBossRush()
+		return
+	}
+	
+	if g.Mode != menu.ModeStory || !g.Victory || g.ProgressRecorded {
+		return
+	}
+	g.ProgressRecorded = true
+	g.SaveData.RecordClear(g.CurrentStage, g.Score, g.StageTime)
+	g.writeSave()
+}
+
+// writeSave はステージで拾ったアイテムと現在の設定を加えて、セーブデータを書き込みます
+func (g *Game) writeSave() {
+	if g.Saves == nil || g.SaveData == nil || g.Net != nil {
+		return
+	}
+	
+	for _, kind := range g.Pickups {
+		g.SaveData.CollectItem(kind.String())
+	}
+	g.Pickups = nil
+	g.storeProgress()
+	g.storeInventory()
+	
+	mm := g.MenuManager
+	g.SaveData.Settings = mm.Settings()
+	if err := g.Saves.Save(g.SaveSlot, g.SaveData); err != nil {
+		mm.Notice = fmt.Sprintf("Save failed: %v", err)
+	}
+	g.refreshSaveSlots()
+}
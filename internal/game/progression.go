@@ -0,0 +1,199 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+
+	"github.com/remmakoshino/kirby-inspired-go/internal/entity"
+	"github.com/remmakoshino/kirby-inspired-go/internal/menu"
+	"github.com/remmakoshino/kirby-inspired-go/internal/save"
+)
+
+// LevelUpShowTime はレベルアップの表示を出す時間です
+const LevelUpShowTime = 2.0
+
+// LevelUp はレベルが上がったキャラクターの表示です
+type LevelUp struct {
+	Player int     // プレイヤーの番号
+	Level  int     // 上がった後のレベル
+	Time   float64 // 表示の残り時間
+}
+
+// progressionEnabled はレベルと経験値が有効かを返します。
+// 対戦とタイムアタックはレベル1の能力値で公平に遊び、ネット対戦と検証用のハーネスでは Progress が nil です
+func (g *Game) progressionEnabled() bool {
+	if g.Progress == nil {
+		return false
+	}
+	return g.Mode == menu.ModeStory || g.Mode == menu.ModeSurvival || g.Mode == menu.ModeBossRush
+}
+
+// loadProgress はスロットのレベルと経験値をこのプレイ用に写します
+func (g *Game) loadProgress(data *save.Data) {
+	g.Progress = make(map[string]*save.CharacterProgress)
+	for id, p := range data.Characters {
+		cp := *p
+		g.Progress[id] = &cp
+	}
+}
+
+// progressOf はキャラクターのレベルと経験値を返します（まだなければレベル1で作成します）
+func (g *Game) progressOf(id string) *save.CharacterProgress {
+	p, ok := g.Progress[id]
+	if !ok {
+		p = &save.CharacterProgress{Level: 1}
+		g.Progress[id] = p
+	}
+	return p
+}
+
+// applyProgression はプレイヤーのキャラクターにレベルと装備の能力値を設定し、体力を満タンにします。
+// レベルが無効なモードでは装備なしのレベル1の能力値にします
+func (g *Game) applyProgression() {
+	for i, c := range g.Characters {
+		if i >= len(g.PlayerCharacters) {
+			break
+		}
+		level := 1
+		if g.progressionEnabled() {
+			level = g.progressOf(g.PlayerCharacters[i]).Level
+		}
+		g.equipCharacter(i, level)
+		c.Heal(c.GetMaxHealth())
+	}
+}
+
+// gainExp はプレイヤーのキャラクターに経験値を渡し、必要な経験値が貯まったらレベルを上げます。
+// 同じキャラクターを2人で選んでいる場合は1回だけ数えます
+func (g *Game) gainExp(amount int) {
+	if amount <= 0 || !g.progressionEnabled() {
+		return
+	}
+	
+	seen := make(map[string]bool)
+	for _, id := range g.PlayerCharacters {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		
+		p := g.progressOf(id)
+		before := p.Level
+		p.Exp += amount
+		for p.Level < entity.MaxLevel && p.Exp >= entity.ExpToNext(p.Level) {
+			p.Exp -= entity.ExpToNext(p.Level)
+			p.Level++
+		}
+		if p.Level >= entity.MaxLevel {
+			p.Exp = 0
+		}
+		if p.Level > before {
+			g.levelUp(id, p.Level)
+		}
+	}
+}
+
+// levelUp はキャラクターを選んでいるプレイヤー全員の能力値を上げ、体力を全回復してレベルアップを表示します
+func (g *Game) levelUp(id string, level int) {
+	for i, c := range g.Characters {
+		if i >= len(g.PlayerCharacters) || g.PlayerCharacters[i] != id {
+			continue
+		}
+		g.equipCharacter(i, level)
+		if !c.IsDefeated() {
+			c.Heal(c.GetMaxHealth())
+		}
+		g.LevelUps = append(g.LevelUps, LevelUp{Player: i, Level: level, Time: LevelUpShowTime})
+	}
+}
+
+// rewardBoss は倒したボスの経験値（初めてならボスの装備品も）を1回だけ渡します
+func (g *Game) rewardBoss() {
+	if g.Boss == nil || g.Boss.IsAlive || g.Boss.Rewarded {
+		return
+	}
+	g.Boss.Rewarded = true
+	g.gainExp(entity.BossExp(g.Boss.Type))
+	g.earnCoins(entity.BossCoins(g.Boss.Type))
+	g.rewardBossItem(g.Boss.Type)
+}
+
+// updateLevelUps はレベルアップの表示の残り時間を減らします
+func (g *Game) updateLevelUps(dt float64) {
+	kept := g.LevelUps[:0]
+	for _, lu := range g.LevelUps {
+		lu.Time -= dt
+		if lu.Time > 0 {
+			kept = append(kept, lu)
+		}
+	}
+	g.LevelUps = kept
+}
+
+// storeProgress はこのプレイのレベルと経験値をセーブデータに書き戻します
+func (g *Game) storeProgress() {
+	if g.Progress == nil {
+		return
+	}
+	for id, p := range g.Progress {
+		cp := *p
+		g.SaveData.Characters[id] = &cp
+	}
+}
+
+// drawLevelUpRings はレベルが上がったキャラクターの周りに広がる光の輪を描画します（カメラ越しに描く）
+func (g *Game) drawLevelUpRings() {
+	for _, lu := range g.LevelUps {
+		if lu.Player >= len(g.Characters) {
+			continue
+		}
+		t := 1 - lu.Time/LevelUpShowTime
+		alpha := uint8(220 * (1 - t))
+		g.IMDraw.Color = color.RGBA{R: alpha, G: uint8(float64(alpha) * 0.85), B: alpha / 4, A: alpha}
+		g.IMDraw.Push(g.Characters[lu.Player].GetPosition())
+		g.IMDraw.Circle(25+t*45, 3)
+	}
+}
+
+// drawLevelUpText はレベルが上がったキャラクターの上に「LEVEL UP!」を浮かび上がらせます
+func (g *Game) drawLevelUpText() {
+	for _, lu := range g.LevelUps {
+		if lu.Player >= len(g.Characters) {
+			continue
+		}
+		rise := (LevelUpShowTime - lu.Time) * 20
+		pos := g.Camera.Matrix().Project(g.Characters[lu.Player].GetPosition().Add(pixel.V(-50, 35+rise)))
+		levelText := text.New(pos, g.Atlas)
+		levelText.Color = colornames.Gold
+		fmt.Fprintf(levelText, "LEVEL UP!\n  Lv %d", lu.Level)
+		levelText.Draw(g.Window, pixel.IM.Scaled(levelText.Orig, 1.5))
+	}
+}
+
+// drawExpBar はHPバーの下にレベルと次のレベルまでの経験値のバーを描画します
+func (g *Game) drawExpBar(barX float64, p *save.CharacterProgress) {
+	barWidth := 150.0
+	barY := WindowHeight - 108.0
+	
+	ratio := 0.0
+	if p.Level < entity.MaxLevel {
+		ratio = float64(p.Exp) / float64(entity.ExpToNext(p.Level))
+	}
+	
+	g.IMDraw.Color = color.RGBA{R: 60, G: 60, B: 80, A: 255}
+	g.IMDraw.Push(pixel.V(barX, barY), pixel.V(barX+barWidth, barY+5))
+	g.IMDraw.Rectangle(0)
+	g.IMDraw.Color = color.RGBA{R: 120, G: 200, B: 255, A: 255}
+	g.IMDraw.Push(pixel.V(barX, barY), pixel.V(barX+barWidth*ratio, barY+5))
+	g.IMDraw.Rectangle(0)
+	g.IMDraw.Draw(g.Window)
+	
+	levelText := text.New(pixel.V(barX+barWidth+6, barY-2), g.Atlas)
+	levelText.Color = colornames.Lightskyblue
+	fmt.Fprintf(levelText, "Lv%d", p.Level)
+	levelText.Draw(g.Window, pixel.IM.Scaled(levelText.Orig, 1.2))
+}
@@ -0,0 +1,211 @@
+package game
+
+import (
+	"image/color"
+
+	"github.com/faiface/pixel"
+
+	"github.com/remmakoshino/kirby-inspired-go/internal/entity"
+	"github.com/remmakoshino/kirby-inspired-go/internal/stage"
+)
+
+// 部屋の移動の演出時間（秒）
+const (
+	RoomFadeTime = 0.35 // 暗転と明転それぞれの時間
+	WarpRideTime = 0.8  // ワープスターで飛び去るまでの時間
+	WarpSpeed    = 700.0
+)
+
+// Room はステージの部屋1つ分の状態です。
+// 部屋を出ても倒した敵や拾ったアイテムの状態はステージにいる間残ります
+type Room struct {
+	Stage      *stage.Stage
+	Enemies    []*entity.Enemy
+	WaddleDees []*entity.WaddleDee
+	WaddleDoos []*entity.WaddleDoo
+	Boss       *entity.Boss
+	Items      []*entity.Item
+	Spawners   []*Spawner
+}
+
+// enemiesLeft は部屋に残っている敵（ボスとスポナーから出た敵を除く）の数を返します
+func (r *Room) enemiesLeft() int {
+	count := 0
+	for _, e := range r.Enemies {
+		if e.IsAlive && e.Spawner == 0 {
+			count++
+		}
+	}
+	for _, w := range r.WaddleDees {
+		if w.IsAlive {
+			count++
+		}
+	}
+	for _, w := range r.WaddleDoos {
+		if w.IsAlive {
+			count++
+		}
+	}
+	return count
+}
+
+// RoomTransition は扉やワープスターで部屋を移動している途中の状態です
+type RoomTransition struct {
+	Target   int
+	Exit     pixel.Vec
+	Star     int     // 乗っているワープスターの番号（扉なら -1）
+	Time     float64 // 今の段階の経過時間
+	Switched bool    // 行き先の部屋に切り替えた（明転中）
+}
+
+// fadeOutTime は暗転し終わるまでの時間を返します（ワープスターは飛び去ってから暗転する）
+func (t *RoomTransition) fadeOutTime() float64 {
+	if t.Star >= 0 {
+		return WarpRideTime + RoomFadeTime
+	}
+	return RoomFadeTime
+}
+
+// Fade は画面の暗さ（0: 通常, 1: 真っ暗）を返します
+func (t *RoomTransition) Fade() float64 {
+	if t.Switched {
+		return clampRange(1-t.Time/RoomFadeTime, 0, 1)
+	}
+	return clampRange((t.Time-(t.fadeOutTime()-RoomFadeTime))/RoomFadeTime, 0, 1)
+}
+
+// currentRoom は今いる部屋の状態をまとめます（今いる部屋は Game のフィールドが持つ）
+func (g *Game) currentRoom() *Room {
+	return &Room{
+		Stage:      g.Stage,
+		Enemies:    g.Enemies,
+		WaddleDees: g.WaddleDees,
+		WaddleDoos: g.WaddleDoos,
+		Boss:       g.Boss,
+		Items:      g.Items,
+		Spawners:   g.Spawners,
+	}
+}
+
+// allRooms は今いる部屋を含むステージのすべての部屋を返します
+func (g *Game) allRooms() []*Room {
+	rooms := []*Room{g.currentRoom()}
+	for _, room := range g.Rooms {
+		if room != nil {
+			rooms = append(rooms, room)
+		}
+	}
+	return rooms
+}
+
+// loadRoom は部屋に入ります。部屋の状態は Game のフィールドに移し、Rooms の要素は nil にします
+func (g *Game) loadRoom(index int) {
+	room := g.Rooms[index]
+	g.Rooms[index] = nil
+	g.CurrentRoom = index
+	
+	g.Stage = room.Stage
+	g.Enemies = room.Enemies
+	g.WaddleDees = room.WaddleDees
+	g.WaddleDoos = room.WaddleDoos
+	g.Boss = room.Boss
+	g.Items = room.Items
+	g.Spawners = room.Spawners
+	g.Projectiles = nil
+}
+
+// checkRoomEntrances は扉の前で上を押したか、ワープスターに触れたプレイヤーがいれば部屋の移動を始めます
+func (g *Game) checkRoomEntrances(inputs []entity.PlayerInput) {
+	for len(g.DoorHeld) < len(g.Characters) {
+		g.DoorHeld = append(g.DoorHeld, false)
+	}
+	g.DoorHeld = g.DoorHeld[:len(g.Characters)]
+	
+	for i, c := range g.Characters {
+		up := i < len(inputs) && inputs[i].Up
+		pressed := up && !g.DoorHeld[i]
+		g.DoorHeld[i] = up
+		if g.isCPU(i) || c.IsDefeated() || g.Transition != nil {
+			continue
+		}
+		
+		bounds := c.GetBounds()
+		if pressed {
+			for _, door := range g.Stage.Doors {
+				if bounds.Intersects(door.GetBounds()) {
+					g.Transition = &RoomTransition{Target: door.Target, Exit: door.Exit, Star: -1}
+					break
+				}
+			}
+		}
+		for s, star := range g.Stage.WarpStars {
+			if g.Transition == nil && bounds.Intersects(star.GetBounds()) {
+				g.Transition = &RoomTransition{Target: star.Target, Exit: star.Exit, Star: s}
+			}
+		}
+	}
+}
+
+// updateTransition は部屋の移動の演出を進め、暗転したところで行き先の部屋に切り替えます
+func (g *Game) updateTransition(dt float64) {
+	t := g.Transition
+	t.Time += dt
+	
+	if t.Switched {
+		if t.Time >= RoomFadeTime {
+			g.Transition = nil
+		}
+		return
+	}
+	
+	// ワープスターは全員を乗せて斜め上へ飛び去る
+	if t.Star >= 0 && t.Star < len(g.Stage.WarpStars) {
+		star := g.Stage.WarpStars[t.Star]
+		star.Pos = star.Pos.Add(pixel.V(0.6, 0.8).Scaled(WarpSpeed * dt))
+		for i, c := range g.Characters {
+			offset := (float64(i) - float64(len(g.Characters)-1)/2) * 20
+			c.SetPosition(star.Pos.Add(pixel.V(offset, c.GetRadius())))
+			c.SetVelocity(pixel.ZV)
+		}
+	}
+	
+	if t.Time >= t.fadeOutTime() {
+		g.switchRoom(t)
+		t.Switched = true
+		t.Time = 0
+	}
+}
+
+// switchRoom は今の部屋の状態を残して行き先の部屋に入り、全員を出口に移します。
+// キャラクターはそのまま連れて行くので、体力とコピー能力は変わりません
+func (g *Game) switchRoom(t *RoomTransition) {
+	if t.Star >= 0 && t.Star < len(g.Stage.WarpStars) {
+		g.Stage.WarpStars[t.Star].Reset()
+	}
+	g.Rooms[g.CurrentRoom] = g.currentRoom()
+	g.loadRoom(t.Target)
+	
+	for i, c := range g.Characters {
+		offset := (float64(i) - float64(len(g.Characters)-1)/2) * 40
+		c.SetPosition(t.Exit.Add(pixel.V(offset, 0)))
+		c.SetVelocity(pixel.ZV)
+	}
+	g.setFallPoints(t.Exit)
+	g.Camera.Reset(t.Exit, g.Stage.Width, g.Stage.Height)
+	
+	// ボスの部屋に入ったら戦う前の会話（明転してから流す）
+	g.queueBossCutscene()
+}
+
+// drawTransition は部屋の移動中の暗転を描画します
+func (g *Game) drawTransition() {
+	fade := g.Transition.Fade()
+	if fade <= 0 {
+		return
+	}
+	g.IMDraw.Color = color.RGBA{A: uint8(255 * fade)}
+	g.IMDraw.Push(pixel.V(0, 0))
+	g.IMDraw.Push(pixel.V(WindowWidth, WindowHeight))
+	g.IMDraw.Rectangle(0)
+	g.IMDraw.Draw(g.Window)
+}
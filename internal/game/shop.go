@@ -0,0 +1,377 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelgl"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+
+	"github.com/remmakoshino/kirby-inspired-go/internal/ability"
+	"github.com/remmakoshino/kirby-inspired-go/internal/entity"
+	"github.com/remmakoshino/kirby-inspired-go/internal/inventory"
+	"github.com/remmakoshino/kirby-inspired-go/internal/stage"
+)
+
+// 店とコイン
+const (
+	PointStarCoins     = 5     // ポイントスターを取った時にもらえるコイン
+	VendorPromptHeight = 120.0 // 店を開く操作を表示する、屋台の足元からの高さ
+)
+
+// ShopBackButtons は店の確認を取り消す・店を閉じるボタンです
+var ShopBackButtons = []pixelgl.Button{pixelgl.KeyEscape, pixelgl.KeyBackspace}
+
+// ShopMenu は屋台で開いた店の画面の状態です
+type ShopMenu struct {
+	Selling    bool   // 売る画面（false なら買う画面）
+	Selection  int    // 選んでいる行
+	Confirming bool   // 選んだ行の売り買いを確認している
+	Yes        bool   // 確認で「はい」を選んでいる
+	Message    string // 最後の売り買いの結果
+}
+
+// shopRow は店の画面の1行です（買う画面は品物、売る画面は持っているアイテム）
+type shopRow struct {
+	Offer inventory.Offer
+	Item  inventory.Item // 売る画面のアイテム・買う画面のアイテムの品物の定義
+	Price int            // 今の値段（売る画面はもらえるコイン）
+}
+
+// name は行の品物の表示名を返します
+func (r shopRow) name() string {
+	if r.Offer.IsAbility() {
+		return r.Offer.Name
+	}
+	return r.Item.Name
+}
+
+// description は行の品物の説明を返します
+func (r shopRow) description() string {
+	if r.Offer.IsAbility() {
+		return r.Offer.Description
+	}
+	return r.Item.Description
+}
+
+// shopCleared は値段と品揃えを決める、クリアしたステージの数を返します
+func (g *Game) shopCleared() int {
+	if g.SaveData == nil {
+		return 0
+	}
+	return g.SaveData.ClearedCount()
+}
+
+// earnCoins はコインを持ち物に加えます（持ち物を使えないモードでは何もしない）
+func (g *Game) earnCoins(n int) {
+	if !g.inventoryEnabled() {
+		return
+	}
+	g.Bag.Earn(n)
+}
+
+// dropCoins は倒した敵の位置にコインを落とします（持ち物を使えないモードでは落とさない）
+func (g *Game) dropCoins(e *entity.Enemy) {
+	coins := entity.EnemyCoins(e.Type)
+	if coins <= 0 || !g.inventoryEnabled() {
+		return
+	}
+	it := entity.NewDroppedItem(e.Position, entity.ItemCoin)
+	// 敵が向いていた方と逆に飛ばす
+	it.Velocity.X = -e.MoveDirection * entity.ItemCoinPop
+	it.Coins = coins
+	g.Items = append(g.Items, it)
+}
+
+// vendorInReach は屋台の前にいる、人間が操作しているプレイヤーとその屋台を返します（いなければ -1 と nil）
+func (g *Game) vendorInReach() (int, *stage.Vendor) {
+	for i, c := range g.Characters {
+		if g.isCPU(i) || c.IsDefeated() {
+			continue
+		}
+		for _, v := range g.Stage.Vendors {
+			if c.GetBounds().Intersects(v.GetBounds()) {
+				return i, v
+			}
+		}
+	}
+	return -1, nil
+}
+
+// shopRows は店の画面の行を返します（買う画面は並んでいる品物、売る画面は店が買い取るアイテム）
+func (g *Game) shopRows(selling bool) []shopRow {
+	cleared := g.shopCleared()
+	var rows []shopRow
+	if selling {
+		for _, it := range g.Catalog.Items {
+			if g.Bag.Count(it.ID) == 0 {
+				continue
+			}
+			if price, ok := g.Shop.SellPrice(it.ID, cleared); ok {
+				rows = append(rows, shopRow{Item: it, Price: price})
+			}
+		}
+		return rows
+	}
+	for _, o := range g.Shop.Available(cleared) {
+		row := shopRow{Offer: o, Price: g.Shop.Price(o, cleared)}
+		if !o.IsAbility() {
+			row.Item, _ = g.Catalog.Lookup(o.Item)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// updateShop は屋台の前で上を押したら店を開き、開いている間は売り買いを処理します。
+// 店を開いた・操作した・閉じたフレームは true を返します
+func (g *Game) updateShop() bool {
+	if g.ShopMenu == nil {
+		if !g.inventoryEnabled() || g.Shop == nil || g.Transition != nil || g.DeathTimer > 0 {
+			return false
+		}
+		// 扉と同じく、上を押した瞬間だけ話しかける
+		player, _ := g.vendorInReach()
+		if player < 0 || player >= len(g.DoorHeld) || g.DoorHeld[player] || !g.readInput(player).Up {
+			return false
+		}
+		g.ShopMenu = &ShopMenu{}
+		return true
+	}
+	
+	sm := g.ShopMenu
+	win := g.Window
+	left := win.JustPressed(pixelgl.KeyLeft) || win.JustPressed(pixelgl.KeyA)
+	right := win.JustPressed(pixelgl.KeyRight) || win.JustPressed(pixelgl.KeyD)
+	choose := win.JustPressed(pixelgl.KeyEnter) || win.JustPressed(pixelgl.KeySpace)
+	rows := g.shopRows(sm.Selling)
+	
+	// 確認中は左右で「はい」「いいえ」を選ぶ
+	if sm.Confirming {
+		if left || right {
+			sm.Yes = !sm.Yes
+		}
+		if g.anyJustPressed(ShopBackButtons) || choose && !sm.Yes {
+			sm.Confirming = false
+			sm.Message = ""
+			return true
+		}
+		if choose && sm.Selection < len(rows) {
+			sm.Confirming = false
+			g.tradeShopRow(rows[sm.Selection])
+			if rows = g.shopRows(sm.Selling); sm.Selection >= len(rows) {
+				sm.Selection = len(rows) - 1
+			}
+			if sm.Selection < 0 {
+				sm.Selection = 0
+			}
+		}
+		return true
+	}
+	
+	if g.anyJustPressed(ShopBackButtons) {
+		g.ShopMenu = nil
+		// 上を押したままでもすぐに開き直さない
+		for i := range g.DoorHeld {
+			g.DoorHeld[i] = true
+		}
+		return true
+	}
+	
+	// 左右で買う画面と売る画面を切り替え、上下で行を選ぶ
+	if left || right {
+		sm.Selling = !sm.Selling
+		sm.Selection = 0
+		sm.Message = ""
+		rows = g.shopRows(sm.Selling)
+	}
+	if win.JustPressed(pixelgl.KeyUp) || win.JustPressed(pixelgl.KeyW) {
+		sm.Selection--
+	}
+	if win.JustPressed(pixelgl.KeyDown) || win.JustPressed(pixelgl.KeyS) {
+		sm.Selection++
+	}
+	if len(rows) > 0 {
+		sm.Selection = (sm.Selection + len(rows)) % len(rows)
+	}
+	
+	if choose && len(rows) > 0 {
+		sm.Confirming = true
+		sm.Yes = true
+	}
+	return true
+}
+
+// tradeShopRow は確認した行の品物を買うか、アイテムを売ります
+func (g *Game) tradeShopRow(row shopRow) {
+	sm := g.ShopMenu
+	cleared := g.shopCleared()
+	
+	if sm.Selling {
+		coins, err := g.Shop.Sell(g.Bag, row.Item.ID, cleared)
+		if err != nil {
+			sm.Message = err.Error()
+			return
+		}
+		sm.Message = fmt.Sprintf("Sold %s for %d coins", row.Item.Name, coins)
+		return
+	}
+	
+	err := g.Shop.Buy(g.Bag, g.Catalog, row.Offer, cleared)
+	switch {
+	case errors.Is(err, inventory.ErrNotEnoughCoins):
+		sm.Message = fmt.Sprintf("Not enough coins (%d more)", row.Price-g.Bag.Coins)
+	case err != nil:
+		sm.Message = err.Error()
+	case row.Offer.IsAbility():
+		sm.Message = fmt.Sprintf("Learned %s! Copy it from the pause screen", row.Offer.Name)
+	default:
+		sm.Message = fmt.Sprintf("Bought %s", row.Item.Name)
+	}
+}
+
+// abilityName はコピー能力の種類の表示名を返します
+func abilityName(abilityType string) string {
+	if ab := ability.CreateAbilityFromType(abilityType); ab != nil {
+		return ab.GetName()
+	}
+	return abilityType
+}
+
+// drawVendorPrompt は屋台の前にいる時に、店を開く操作を屋台の上に表示します
+func (g *Game) drawVendorPrompt() {
+	if g.ShopMenu != nil || !g.inventoryEnabled() {
+		return
+	}
+	_, v := g.vendorInReach()
+	if v == nil {
+		return
+	}
+	pos := g.Camera.Matrix().Project(v.Pos.Add(pixel.V(-40, VendorPromptHeight)))
+	promptText := text.New(pos, g.Atlas)
+	promptText.Color = colornames.Yellow
+	fmt.Fprintf(promptText, "UP: Shop")
+	promptText.Draw(g.Window, pixel.IM.Scaled(promptText.Orig, 1.5))
+}
+
+// drawShop は店の画面（品物と値段の一覧と、売り買いの確認）を描画します
+func (g *Game) drawShop() {
+	sm := g.ShopMenu
+	
+	// 画面を暗くする
+	g.IMDraw.Color = color.RGBA{R: 0, G: 0, B: 0, A: 170}
+	g.IMDraw.Push(pixel.V(0, 0), pixel.V(WindowWidth, WindowHeight))
+	g.IMDraw.Rectangle(0)
+	g.IMDraw.Draw(g.Window)
+	g.IMDraw.Clear()
+	
+	titleText := text.New(pixel.V(WindowWidth/2-60, WindowHeight-100), g.Atlas)
+	titleText.Color = colornames.Yellow
+	fmt.Fprintf(titleText, "SHOP")
+	titleText.Draw(g.Window, pixel.IM.Scaled(titleText.Orig, 4))
+	
+	// 買う・売るの見出しと持っているコイン
+	headerText := text.New(pixel.V(WindowWidth/2-300, WindowHeight-150), g.Atlas)
+	headerText.Color = colornames.Lightskyblue
+	if sm.Selling {
+		fmt.Fprintf(headerText, "  BUY  [SELL]")
+	} else {
+		fmt.Fprintf(headerText, "[BUY]  SELL ")
+	}
+	fmt.Fprintf(headerText, "      Coins: %d", g.Bag.Coins)
+	headerText.Draw(g.Window, pixel.IM.Scaled(headerText.Orig, 1.6))
+	
+	rows := g.shopRows(sm.Selling)
+	y := WindowHeight - 200.0
+	if len(rows) == 0 {
+		emptyText := text.New(pixel.V(WindowWidth/2-300, y), g.Atlas)
+		emptyText.Color = colornames.Lightgray
+		fmt.Fprintf(emptyText, "  Nothing to sell")
+		emptyText.Draw(g.Window, pixel.IM.Scaled(emptyText.Orig, 1.5))
+		y -= 24
+	}
+	for i, row := range rows {
+		rowText := text.New(pixel.V(WindowWidth/2-300, y), g.Atlas)
+		rowText.Color = colornames.White
+		if i == sm.Selection {
+			rowText.Color = colornames.Yellow
+			fmt.Fprintf(rowText, "> ")
+		} else {
+			fmt.Fprintf(rowText, "  ")
+		}
+		
+		// 覚えたコピー能力と、買えない値段の品物は暗く表示する
+		owned := ""
+		switch {
+		case sm.Selling:
+			owned = fmt.Sprintf("x%d", g.Bag.Count(row.Item.ID))
+		case row.Offer.IsAbility():
+			if g.Bag.Learned(row.Offer.Ability) {
+				owned = "learned"
+				rowText.Color = colornames.Gray
+			}
+		default:
+			if n := g.Bag.Count(row.Item.ID); n > 0 {
+				owned = fmt.Sprintf("x%d", n)
+			}
+		}
+		if !sm.Selling && row.Price > g.Bag.Coins && i != sm.Selection {
+			rowText.Color = colornames.Gray
+		}
+		fmt.Fprintf(rowText, "%-18s %5dc  %-8s %s", row.name(), row.Price, owned, row.description())
+		rowText.Draw(g.Window, pixel.IM.Scaled(rowText.Orig, 1.5))
+		y -= 24
+	}
+	
+	if sm.Message != "" {
+		messageText := text.New(pixel.V(WindowWidth/2-300, y-20), g.Atlas)
+		messageText.Color = colornames.Lightgreen
+		fmt.Fprintf(messageText, "%s", sm.Message)
+		messageText.Draw(g.Window, pixel.IM.Scaled(messageText.Orig, 1.5))
+	}
+	
+	if sm.Confirming && sm.Selection < len(rows) {
+		g.drawShopConfirm(rows[sm.Selection])
+	}
+	
+	helpText := text.New(pixel.V(WindowWidth/2-300, 40), g.Atlas)
+	helpText.Color = colornames.Lightgray
+	fmt.Fprintf(helpText, "Up/Down: Select  Left/Right: Buy / Sell  Enter: Choose  ESC: Leave")
+	helpText.Draw(g.Window, pixel.IM.Scaled(helpText.Orig, 1.3))
+}
+
+// drawShopConfirm は売り買いの確認の枠を画面の中央に描画します
+func (g *Game) drawShopConfirm(row shopRow) {
+	sm := g.ShopMenu
+	box := pixel.R(WindowWidth/2-260, WindowHeight/2-60, WindowWidth/2+260, WindowHeight/2+60)
+	
+	g.IMDraw.Color = color.RGBA{R: 30, G: 30, B: 60, A: 240}
+	g.IMDraw.Push(box.Min, box.Max)
+	g.IMDraw.Rectangle(0)
+	g.IMDraw.Color = colornames.Gold
+	g.IMDraw.Push(box.Min, box.Max)
+	g.IMDraw.Rectangle(3)
+	g.IMDraw.Draw(g.Window)
+	g.IMDraw.Clear()
+	
+	questionText := text.New(pixel.V(box.Min.X+24, box.Max.Y-40), g.Atlas)
+	questionText.Color = colornames.White
+	if sm.Selling {
+		fmt.Fprintf(questionText, "Sell %s for %d coins?", row.name(), row.Price)
+	} else {
+		fmt.Fprintf(questionText, "Buy %s for %d coins?", row.name(), row.Price)
+	}
+	questionText.Draw(g.Window, pixel.IM.Scaled(questionText.Orig, 1.6))
+	
+	answerText := text.New(pixel.V(box.Min.X+150, box.Min.Y+30), g.Atlas)
+	answerText.Color = colornames.Yellow
+	if sm.Yes {
+		fmt.Fprintf(answerText, "> Yes      No")
+	} else {
+		fmt.Fprintf(answerText, "  Yes    > No")
+	}
+	answerText.Draw(g.Window, pixel.IM.Scaled(answerText.Orig, 2))
+}
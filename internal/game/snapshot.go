@@ -0,0 +1,478 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelgl"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+
+	"github.com/remmakoshino/kirby-inspired-go/internal/ability"
+	"github.com/remmakoshino/kirby-inspired-go/internal/entity"
+	"github.com/remmakoshino/kirby-inspired-go/internal/inventory"
+	"github.com/remmakoshino/kirby-inspired-go/internal/menu"
+	"github.com/remmakoshino/kirby-inspired-go/internal/rng"
+	"github.com/remmakoshino/kirby-inspired-go/internal/save"
+	"github.com/remmakoshino/kirby-inspired-go/internal/stage"
+)
+
+// SnapshotVersion はスナップショットの形式のバージョンです。
+// 保存する内容を変えたら上げてください
+const SnapshotVersion = 16
+
+// ErrSnapshotVersion は対応していないバージョンのスナップショットを復元しようとした時のエラーです
+var ErrSnapshotVersion = errors.New("game: unsupported snapshot version")
+
+// helperCharacterID はヘルパーを表すキャラクターIDです（キャラクター登録には含まれない）
+const helperCharacterID = "Helper"
+
+// worldState はスナップショットに保存するゲーム世界の状態です。
+// 同じ世界からは必ず同じバイト列になるよう、マップを使わずスライスで持ちます
+type worldState struct {
+	Version          int
+	Score            int
+	Lives            int
+	CurrentStage     int
+	StageTime        float64
+	Pickups          []entity.ItemKind
+	GameOver         bool
+	Victory          bool
+	Mode             menu.GameMode
+	PlayerCharacters []string
+	RNG              uint64
+	
+	Characters  []characterState
+	Brains      []*entity.HelperBrain
+	Enemies     []*entity.Enemy
+	WaddleDees  []*entity.WaddleDee
+	WaddleDoos  []*entity.WaddleDoo
+	Boss        *entity.Boss
+	Projectiles []*entity.Projectile
+	Items       []*entity.Item
+	Spawners    []*Spawner
+	Stage       *stage.Stage
+	Camera      *Camera
+	Versus      *VersusMatch
+	Survival    *SurvivalRun
+	BossRush    *BossRush
+	TimeAttack  *TimeAttackRun
+	
+	// レベルと経験値（レベルを使わない時は nil）
+	Progress []progressState
+	LevelUps []LevelUp
+	
+	// 持ち物と装備、コインと覚えたコピー能力（持ち物を使えないモードでは nil）
+	Inventory      []bagState
+	Equipment      []equipmentState
+	Coins          int
+	Abilities      []string
+	ItemNotice     string
+	ItemNoticeTime float64
+	
+	// 会話とカットシーン
+	Cutscene      *Cutscene
+	CutsceneQueue []string
+	CutscenesSeen []string
+	
+	// ほかの部屋と部屋の移動
+	Rooms       []*Room
+	CurrentRoom int
+	Transition  *RoomTransition
+	DoorHeld    []bool
+	
+	// やられた時の復帰
+	Respawn    RespawnPoint
+	DeathTimer float64
+}
+
+// characterState はキャラクター1人分の状態です。
+// 本体は状態遷移の処理を持つため、復元時はコンストラクタで作り直してから値を上書きします
+type characterState struct {
+	ID   string
+	Body json.RawMessage
+	
+	// コピー能力（専用技を持つキャラクターは Slots と CurrentSlot を使う）
+	Ability     *abilityState
+	Slots       []abilityState
+	CurrentSlot int
+}
+
+// progressState はキャラクター1人分のレベルと経験値です
+type progressState struct {
+	ID    string
+	Level int
+	Exp   int
+}
+
+// bagState は持っているアイテム1種類の数です
+type bagState struct {
+	ID    string
+	Count int
+}
+
+// equipmentState はキャラクターが枠に装備しているアイテムです
+type equipmentState struct {
+	Character string
+	Slot      string
+	Item      string
+}
+
+// abilityState は能力の種類と値です
+type abilityState struct {
+	Type string
+	Data json.RawMessage
+}
+
+// Snapshot はゲーム世界の状態（キャラクター・敵・ボスのAI・能力のクールダウン・
+// ステージ・乱数など）をバイト列にします。同じ状態からは必ず同じバイト列になります
+func (g *Game) Snapshot() ([]byte, error) {
+	ws := worldState{
+		Version:          SnapshotVersion,
+		Score:            g.Score,
+		Lives:            g.Lives,
+		CurrentStage:     g.CurrentStage,
+		StageTime:        g.StageTime,
+		Pickups:          g.Pickups,
+		GameOver:         g.GameOver,
+		Victory:          g.Victory,
+		Mode:             g.Mode,
+		PlayerCharacters: g.PlayerCharacters,
+		RNG:              rng.State(),
+		Brains:           g.Brains,
+		Enemies:          g.Enemies,
+		WaddleDees:       g.WaddleDees,
+		WaddleDoos:       g.WaddleDoos,
+		Boss:             g.Boss,
+		Projectiles:      g.Projectiles,
+		Items:            g.Items,
+		Spawners:         g.Spawners,
+		Stage:            g.Stage,
+		Camera:           g.Camera,
+		Versus:           g.Versus,
+		Survival:         g.Survival,
+		BossRush:         g.BossRush,
+		TimeAttack:       g.TimeAttack,
+		Progress:         g.saveProgress(),
+		LevelUps:         g.LevelUps,
+		ItemNotice:       g.ItemNotice,
+		ItemNoticeTime:   g.ItemNoticeTime,
+		Cutscene:         g.Cutscene,
+		CutsceneQueue:    g.CutsceneQueue,
+		CutscenesSeen:    g.CutscenesSeen,
+		Rooms:            g.Rooms,
+		CurrentRoom:      g.CurrentRoom,
+		Transition:       g.Transition,
+		DoorHeld:         g.DoorHeld,
+		Respawn:          g.Respawn,
+		DeathTimer:       g.DeathTimer,
+	}
+	
+	ws.Inventory, ws.Equipment = g.saveBag()
+	if ws.Inventory != nil {
+		ws.Coins = g.Bag.Coins
+		ws.Abilities = g.Bag.Abilities
+	}
+	
+	for i, c := range g.Characters {
+		cs, err := g.saveCharacter(i, c)
+		if err != nil {
+			return nil, err
+		}
+		ws.Characters = append(ws.Characters, cs)
+	}
+	
+	return json.Marshal(ws)
+}
+
+// saveCharacter はキャラクター1人分の状態を作ります
+func (g *Game) saveCharacter(i int, c entity.PlayableCharacter) (characterState, error) {
+	cs := characterState{ID: helperCharacterID}
+	if _, ok := c.(*entity.Helper); !ok {
+		if i >= len(g.PlayerCharacters) {
+			return cs, fmt.Errorf("game: no character id for player %d", i+1)
+		}
+		cs.ID = g.PlayerCharacters[i]
+	}
+	
+	body, err := json.Marshal(c)
+	if err != nil {
+		return cs, err
+	}
+	cs.Body = body
+	
+	if owner, ok := c.(entity.AbilitySlotOwner); ok {
+		for slot, ab := range owner.AbilitySlots() {
+			state, err := saveAbility(ab)
+			if err != nil {
+				return cs, err
+			}
+			cs.Slots = append(cs.Slots, state)
+			if ab == c.GetAbility() {
+				cs.CurrentSlot = slot
+			}
+		}
+		return cs, nil
+	}
+	
+	if ab := c.GetAbility(); ab != nil {
+		state, err := saveAbility(ab)
+		if err != nil {
+			return cs, err
+		}
+		cs.Ability = &state
+	}
+	return cs, nil
+}
+
+// saveAbility は能力の種類と値を保存します
+func saveAbility(ab ability.Ability) (abilityState, error) {
+	kind := ability.TypeOf(ab)
+	if kind == "" {
+		return abilityState{}, fmt.Errorf("game: cannot save ability %T", ab)
+	}
+	data, err := json.Marshal(ab)
+	return abilityState{Type: kind, Data: data}, err
+}
+
+// loadAbility は保存した能力を作り直します
+func loadAbility(state abilityState) (ability.Ability, error) {
+	ab := ability.CreateAbilityFromType(state.Type)
+	if ab == nil {
+		return nil, fmt.Errorf("game: unknown ability %q", state.Type)
+	}
+	return ab, json.Unmarshal(state.Data, ab)
+}
+
+// Restore は Snapshot で保存した状態に戻します。
+// 失敗した場合はゲーム世界を変更しません
+func (g *Game) Restore(data []byte) error {
+	var ws worldState
+	if err := json.Unmarshal(data, &ws); err != nil {
+		return err
+	}
+	if ws.Version != SnapshotVersion {
+		return fmt.Errorf("%w: %d", ErrSnapshotVersion, ws.Version)
+	}
+	
+	characters := make([]entity.PlayableCharacter, 0, len(ws.Characters))
+	for _, cs := range ws.Characters {
+		c, err := loadCharacter(cs)
+		if err != nil {
+			return err
+		}
+		characters = append(characters, c)
+	}
+	
+	g.Score = ws.Score
+	g.Lives = ws.Lives
+	g.CurrentStage = ws.CurrentStage
+	g.StageTime = ws.StageTime
+	g.Pickups = ws.Pickups
+	g.GameOver = ws.GameOver
+	g.Victory = ws.Victory
+	g.Mode = ws.Mode
+	g.PlayerCharacters = ws.PlayerCharacters
+	rng.SetState(ws.RNG)
+	
+	g.Characters = characters
+	g.Brains = ws.Brains
+	g.Enemies = ws.Enemies
+	g.WaddleDees = ws.WaddleDees
+	g.WaddleDoos = ws.WaddleDoos
+	g.Boss = ws.Boss
+	g.Projectiles = ws.Projectiles
+	g.Items = ws.Items
+	g.Spawners = ws.Spawners
+	g.Stage = ws.Stage
+	g.Camera = ws.Camera
+	g.Versus = ws.Versus
+	g.Survival = ws.Survival
+	g.BossRush = ws.BossRush
+	g.TimeAttack = ws.TimeAttack
+	g.restoreProgress(ws.Progress)
+	g.LevelUps = ws.LevelUps
+	g.restoreBag(ws.Inventory, ws.Equipment, ws.Coins, ws.Abilities)
+	g.ItemNotice = ws.ItemNotice
+	g.ItemNoticeTime = ws.ItemNoticeTime
+	g.Cutscene = ws.Cutscene
+	g.CutsceneQueue = ws.CutsceneQueue
+	g.CutscenesSeen = ws.CutscenesSeen
+	g.Rooms = ws.Rooms
+	g.CurrentRoom = ws.CurrentRoom
+	g.Transition = ws.Transition
+	g.DoorHeld = ws.DoorHeld
+	g.Respawn = ws.Respawn
+	g.DeathTimer = ws.DeathTimer
+	return nil
+}
+
+// loadCharacter はキャラクターを作り直して保存した状態を書き戻します
+func loadCharacter(cs characterState) (entity.PlayableCharacter, error) {
+	var c entity.PlayableCharacter
+	if cs.ID == helperCharacterID {
+		c = entity.NewHelper(pixel.ZV, nil)
+	} else {
+		c = entity.NewPlayableCharacter(cs.ID, pixel.ZV)
+	}
+	if c == nil {
+		return nil, fmt.Errorf("game: unknown character %q", cs.ID)
+	}
+	
+	if err := json.Unmarshal(cs.Body, c); err != nil {
+		return nil, err
+	}
+	
+	// 専用技はコンストラクタが作った技に値を書き戻す
+	if owner, ok := c.(entity.AbilitySlotOwner); ok {
+		slots := owner.AbilitySlots()
+		if len(slots) != len(cs.Slots) {
+			return nil, fmt.Errorf("game: %s has %d ability slots, state has %d", cs.ID, len(slots), len(cs.Slots))
+		}
+		for i, state := range cs.Slots {
+			if ability.TypeOf(slots[i]) != state.Type {
+				return nil, fmt.Errorf("game: %s slot %d is %q, state has %q", cs.ID, i, ability.TypeOf(slots[i]), state.Type)
+			}
+			if err := json.Unmarshal(state.Data, slots[i]); err != nil {
+				return nil, err
+			}
+		}
+		if cs.CurrentSlot >= 0 && cs.CurrentSlot < len(slots) {
+			c.SetAbility(slots[cs.CurrentSlot])
+		}
+		return c, nil
+	}
+	
+	var ab ability.Ability
+	if cs.Ability != nil {
+		var err error
+		if ab, err = loadAbility(*cs.Ability); err != nil {
+			return nil, err
+		}
+	}
+	c.SetAbility(ab)
+	return c, nil
+}
+
+// saveProgress はレベルと経験値をキャラクターの登録順のスライスにします
+func (g *Game) saveProgress() []progressState {
+	if g.Progress == nil {
+		return nil
+	}
+	states := []progressState{}
+	for _, id := range entity.CharacterIDs() {
+		if p, ok := g.Progress[id]; ok {
+			states = append(states, progressState{ID: id, Level: p.Level, Exp: p.Exp})
+		}
+	}
+	return states
+}
+
+// restoreProgress は saveProgress で保存したレベルと経験値に戻します
+func (g *Game) restoreProgress(states []progressState) {
+	if states == nil {
+		g.Progress = nil
+		return
+	}
+	g.Progress = make(map[string]*save.CharacterProgress, len(states))
+	for _, ps := range states {
+		g.Progress[ps.ID] = &save.CharacterProgress{Level: ps.Level, Exp: ps.Exp}
+	}
+}
+
+// saveBag は持ち物と装備をアイテムの定義順・キャラクターの登録順のスライスにします。
+// 持ち物を使えないモード（対戦やネット対戦）では保存しないので、マシンごとの持ち物の違いでずれません
+func (g *Game) saveBag() ([]bagState, []equipmentState) {
+	if !g.inventoryEnabled() {
+		return nil, nil
+	}
+	items := []bagState{}
+	for _, it := range g.Catalog.Items {
+		if n := g.Bag.Count(it.ID); n > 0 {
+			items = append(items, bagState{ID: it.ID, Count: n})
+		}
+	}
+	equipment := []equipmentState{}
+	for _, id := range entity.CharacterIDs() {
+		for _, slot := range inventory.Slots {
+			if item := g.Bag.Equipped(id, slot); item != "" {
+				equipment = append(equipment, equipmentState{Character: id, Slot: string(slot), Item: item})
+			}
+		}
+	}
+	return items, equipment
+}
+
+// restoreBag は saveBag で保存した持ち物と装備、コインと覚えたコピー能力に戻します（保存していなければ今の持ち物のまま）
+func (g *Game) restoreBag(items []bagState, equipment []equipmentState, coins int, abilities []string) {
+	if items == nil {
+		return
+	}
+	g.Bag = inventory.NewBag()
+	g.Bag.Coins = coins
+	g.Bag.Abilities = append([]string(nil), abilities...)
+	for _, bs := range items {
+		g.Bag.Items[bs.ID] = bs.Count
+	}
+	for _, es := range equipment {
+		if g.Bag.Equipment[es.Character] == nil {
+			g.Bag.Equipment[es.Character] = make(map[string]string)
+		}
+		g.Bag.Equipment[es.Character][es.Slot] = es.Item
+	}
+}
+
+// デバッグ用セーブステートのキー
+const (
+	SaveStateKey             = pixelgl.KeyF5
+	LoadStateKey             = pixelgl.KeyF9
+	SaveStateMessageDuration = 1.5 // 通知を表示する秒数
+)
+
+// updateSaveStates はデバッグ用のセーブステートを保存・復元します
+func (g *Game) updateSaveStates(dt float64) {
+	if g.SaveStateMessageTime > 0 {
+		g.SaveStateMessageTime -= dt
+	}
+	if g.Stage == nil {
+		return
+	}
+	
+	if g.Window.JustPressed(SaveStateKey) {
+		data, err := g.Snapshot()
+		if err != nil {
+			g.showSaveStateMessage(fmt.Sprintf("Save failed: %v", err))
+			return
+		}
+		g.QuickSave = data
+		g.showSaveStateMessage("State Saved")
+	}
+	
+	if g.Window.JustPressed(LoadStateKey) {
+		if g.QuickSave == nil {
+			g.showSaveStateMessage("No Saved State")
+			return
+		}
+		if err := g.Restore(g.QuickSave); err != nil {
+			g.showSaveStateMessage(fmt.Sprintf("Load failed: %v", err))
+			return
+		}
+		g.showSaveStateMessage("State Loaded")
+	}
+}
+
+// showSaveStateMessage はセーブステートの通知を表示します
+func (g *Game) showSaveStateMessage(message string) {
+	g.SaveStateMessage = message
+	g.SaveStateMessageTime = SaveStateMessageDuration
+}
+
+// drawSaveStateMessage はセーブステートの通知を描画します
+func (g *Game) drawSaveStateMessage() {
+	messageText := text.New(pixel.V(WindowWidth-220, WindowHeight-40), g.Atlas)
+	messageText.Color = colornames.Yellow
+	fmt.Fprintf(messageText, "%s", g.SaveStateMessage)
+	messageText.Draw(g.Window, pixel.IM.Scaled(messageText.Orig, 1.5))
+}
@@ -0,0 +1,176 @@
+package game
+
+import (
+	"github.com/faiface/pixel"
+
+	"github.com/remmakoshino/kirby-inspired-go/internal/entity"
+	"github.com/remmakoshino/kirby-inspired-go/internal/rng"
+)
+
+// SpawnerKind は敵の出し方の種類です
+type SpawnerKind int
+
+const (
+	SpawnRespawn SpawnerKind = iota // 倒されても画面外に出た位置から復活する
+	SpawnTimed                      // 一定時間ごとに出す（同時に Max 体まで）
+	SpawnTrigger                    // プレイヤーが範囲に入った時に一度だけ Count 体出す
+	SpawnEdge                       // 画面の左右の端の外から飛んでくる（ブロントバートなど）
+)
+
+// スポナーが画面の外と判定する余裕と、画面の端から出てきた敵が消える距離
+const (
+	SpawnOffscreenMargin = 40.0
+	SpawnDespawnMargin   = 160.0
+	SpawnTriggerSpacing  = 40.0 // 範囲に入った時にまとめて出す敵の間隔
+)
+
+// Spawner は部屋に置く敵の出現地点です。出てきた敵は Enemy.Spawner にスポナーの番号+1を持ち、
+// ステージクリアに必要な敵の数には数えません
+type Spawner struct {
+	Kind     SpawnerKind
+	Pos      pixel.Vec // 出現位置（SpawnEdge では使わない）
+	Type     entity.EnemyType
+	Region   pixel.Rect // プレイヤーがこの範囲にいる間だけ動く（空なら部屋全体。SpawnTrigger では必須）
+	Interval float64    // 出す間隔（SpawnRespawn では倒されてから復活するまでの最短時間）
+	Max      int        // 同時に出ている数の上限
+	Count    int        // SpawnTrigger でまとめて出す数
+	
+	Timer     float64
+	Triggered bool
+	Side      float64 // SpawnEdge が次に敵を出す画面の端（-1: 左, 1: 右）
+}
+
+// NewSpawner は部屋のデータからスポナーを作成します
+func NewSpawner(def Spawner) *Spawner {
+	sp := def
+	sp.Timer = 0
+	sp.Triggered = false
+	if sp.Max <= 0 {
+		sp.Max = 1
+	}
+	if sp.Side == 0 {
+		sp.Side = 1
+	}
+	return &sp
+}
+
+// newEnemy はこのスポナーの敵を作成します（id はスポナーの番号+1）
+func (sp *Spawner) newEnemy(id int, pos pixel.Vec) *entity.Enemy {
+	e := entity.NewEnemy(pos, sp.Type)
+	e.Spawner = id
+	return e
+}
+
+// updateSpawners は今いる部屋のスポナーから敵を出し、倒されたか画面の外へ飛び去った敵を片付けます
+func (g *Game) updateSpawners(dt float64) {
+	g.pruneSpawnedEnemies()
+	
+	for i, sp := range g.Spawners {
+		id := i + 1
+		alive := g.spawnedAlive(id)
+		
+		switch sp.Kind {
+		case SpawnRespawn:
+			// 倒されたら、出現位置が画面の外にある時だけ復活する
+			if alive >= sp.Max {
+				sp.Timer = 0
+				continue
+			}
+			sp.Timer += dt
+			if sp.Timer >= sp.Interval && !g.Camera.Contains(sp.Pos, SpawnOffscreenMargin) {
+				g.Enemies = append(g.Enemies, sp.newEnemy(id, sp.Pos))
+				sp.Timer = 0
+			}
+		case SpawnTimed:
+			if alive >= sp.Max || !g.playerInRegion(sp.Region) {
+				continue
+			}
+			sp.Timer += dt
+			if sp.Timer >= sp.Interval {
+				g.Enemies = append(g.Enemies, sp.newEnemy(id, sp.Pos))
+				sp.Timer = 0
+			}
+		case SpawnTrigger:
+			if sp.Triggered || sp.Region.Area() == 0 || !g.playerInRegion(sp.Region) {
+				continue
+			}
+			sp.Triggered = true
+			for n := 0; n < sp.Count; n++ {
+				offset := (float64(n) - float64(sp.Count-1)/2) * SpawnTriggerSpacing
+				g.Enemies = append(g.Enemies, sp.newEnemy(id, sp.Pos.Add(pixel.V(offset, 0))))
+			}
+		case SpawnEdge:
+			if alive >= sp.Max || !g.playerInRegion(sp.Region) {
+				continue
+			}
+			sp.Timer += dt
+			if sp.Timer >= sp.Interval {
+				g.spawnFromEdge(sp, id)
+				sp.Timer = 0
+			}
+		}
+	}
+}
+
+// spawnFromEdge は画面の左右の端のすぐ外から、交互に画面の中へ向かう敵を出します。
+// カメラがステージの端で止まっている側では、ステージの端から出します
+func (g *Game) spawnFromEdge(sp *Spawner, id int) {
+	halfW := WindowWidth/2/g.Camera.Zoom + SpawnOffscreenMargin
+	halfH := WindowHeight / 2 / g.Camera.Zoom
+	
+	// 高さは範囲の中（範囲がなければ画面の中）から選ぶ
+	minY, maxY := g.Camera.Position.Y-halfH*0.6, g.Camera.Position.Y+halfH*0.6
+	if sp.Region.Area() > 0 {
+		minY, maxY = sp.Region.Min.Y, sp.Region.Max.Y
+	}
+	y := minY + rng.Float64()*(maxY-minY)
+	
+	side := sp.Side
+	sp.Side = -sp.Side
+	x := clampRange(g.Camera.Position.X+side*halfW, 0, g.Stage.Width)
+	e := sp.newEnemy(id, pixel.V(x, y))
+	e.MoveDirection = -side
+	g.Enemies = append(g.Enemies, e)
+}
+
+// pruneSpawnedEnemies はスポナーから出て倒された敵を取り除きます（ドロップの抽選が済んだもの）。
+// 画面の端から出てきた敵は、画面から遠く離れると消えます
+func (g *Game) pruneSpawnedEnemies() {
+	kept := g.Enemies[:0]
+	for _, e := range g.Enemies {
+		if e.Spawner > 0 && e.IsAlive && e.Spawner <= len(g.Spawners) &&
+			g.Spawners[e.Spawner-1].Kind == SpawnEdge && !g.Camera.Contains(e.Position, SpawnDespawnMargin) {
+			e.IsAlive = false
+			e.DropChecked = true
+		}
+		if e.Spawner > 0 && !e.IsAlive && e.DropChecked {
+			continue
+		}
+		kept = append(kept, e)
+	}
+	g.Enemies = kept
+}
+
+// spawnedAlive はスポナーから出て生きている敵の数を返します
+func (g *Game) spawnedAlive(id int) int {
+	count := 0
+	for _, e := range g.Enemies {
+		if e.Spawner == id && e.IsAlive {
+			count++
+		}
+	}
+	return count
+}
+
+// playerInRegion は人間が操作する生存プレイヤーが範囲にいるかを返します（範囲が空なら常に true）
+func (g *Game) playerInRegion(region pixel.Rect) bool {
+	if region.Area() == 0 {
+		return true
+	}
+	for _, pos := range g.livingPlayerPositions() {
+		if region.Contains(pos) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,613 @@
+package game
+
+import (
+	"image/color"
+
+	"github.com/faiface/pixel"
+
+	"github.com/remmakoshino/kirby-inspired-go/internal/entity"
+	"github.com/remmakoshino/kirby-inspired-go/internal/stage"
+)
+
+// enemySpawn は基本の敵の配置です
+type enemySpawn struct {
+	Pos  pixel.Vec
+	Type entity.EnemyType
+}
+
+// itemSpawn はステージに置くアイテムです（時間で消えない）
+type itemSpawn struct {
+	Pos  pixel.Vec
+	Kind entity.ItemKind
+}
+
+// portal は扉やワープスターの配置です
+type portal struct {
+	Pos    pixel.Vec // 扉は下端の中央、ワープスターは中心
+	Target int       // 行き先の部屋の番号
+	Exit   pixel.Vec // 行き先の部屋で出てくる位置
+}
+
+// roomDefinition はステージの部屋1つ分の内容です
+type roomDefinition struct {
+	Width         float64 // 0 なら画面の幅（広い部屋はカメラがスクロールする）
+	Background    color.RGBA
+	PlatformColor color.RGBA
+	Platforms     []pixel.Rect // nil なら標準の地形
+	IcePlatforms  []pixel.Rect // 滑る氷の足場
+	Hazards       []stage.Hazard
+	Blocks        []stage.Block  // 壊せるブロック・導火線・ゲート
+	Switches      []stage.Switch // ゲートを開くスイッチ
+	WaddleDees    []pixel.Vec
+	WaddleDoos    []pixel.Vec
+	Enemies       []enemySpawn
+	Items         []itemSpawn
+	Spawners      []Spawner   // 敵の出現地点（出てきた敵はクリアの条件に数えない）
+	Checkpoints   []pixel.Vec // 中間ポイントの足元の位置
+	Vendors       []pixel.Vec // 店の屋台の足元の位置
+	Boss          func(pos pixel.Vec) *entity.Boss
+	Doors         []portal
+	WarpStars     []portal
+}
+
+// blockColumn は x, y を左下に count 個積み上げたブロックを返します
+func blockColumn(kind stage.BlockKind, x, y float64, count, group int) []stage.Block {
+	blocks := make([]stage.Block, 0, count)
+	for i := 0; i < count; i++ {
+		bottom := y + float64(i)*stage.BlockSize
+		blocks = append(blocks, stage.Block{Kind: kind, Rect: pixel.R(x, bottom, x+stage.BlockSize, bottom+stage.BlockSize), Group: group})
+	}
+	return blocks
+}
+
+// blockRow は x, y を左下に count 個横に並べたブロックを返します
+func blockRow(kind stage.BlockKind, x, y float64, count, group int) []stage.Block {
+	blocks := make([]stage.Block, 0, count)
+	for i := 0; i < count; i++ {
+		left := x + float64(i)*stage.BlockSize
+		blocks = append(blocks, stage.Block{Kind: kind, Rect: pixel.R(left, y, left+stage.BlockSize, y+stage.BlockSize), Group: group})
+	}
+	return blocks
+}
+
+// fuseLine は x, y から右へ count 区間の導火線を返します（床に這う細いブロック）
+func fuseLine(x, y float64, count int) []stage.Block {
+	blocks := make([]stage.Block, 0, count)
+	for i := 0; i < count; i++ {
+		left := x + float64(i)*stage.BlockSize
+		blocks = append(blocks, stage.Block{Kind: stage.BlockFuse, Rect: pixel.R(left, y, left+stage.BlockSize, y+6)})
+	}
+	return blocks
+}
+
+// joinBlocks はブロックの並びをつなげます
+func joinBlocks(parts ...[]stage.Block) []stage.Block {
+	var blocks []stage.Block
+	for _, part := range parts {
+		blocks = append(blocks, part...)
+	}
+	return blocks
+}
+
+// stageDefinition はストーリーのステージの内容です。最初の部屋から始まり、扉とワープスターで部屋を移動します。
+// ボスのいるステージはボスを倒すと、それ以外はすべての部屋の敵を全滅させるとクリアです
+type stageDefinition struct {
+	Name  string
+	Rooms []roomDefinition
+}
+
+// 地形の色
+var (
+	skyBackground    = color.RGBA{R: 135, G: 206, B: 235, A: 255}
+	grassPlatform    = color.RGBA{R: 100, G: 150, B: 100, A: 255}
+	cloudBackground  = color.RGBA{R: 190, G: 220, B: 250, A: 255}
+	cloudPlatform    = color.RGBA{R: 245, G: 245, B: 255, A: 255}
+	halberdSky       = color.RGBA{R: 70, G: 80, B: 120, A: 255}
+	halberdPlatform  = color.RGBA{R: 150, G: 150, B: 170, A: 255}
+	halberdInterior  = color.RGBA{R: 50, G: 55, B: 80, A: 255}
+	engineBackground = color.RGBA{R: 60, G: 40, B: 40, A: 255}
+	enginePlatform   = color.RGBA{R: 170, G: 110, B: 70, A: 255}
+	sandPlatform     = color.RGBA{R: 210, G: 190, B: 120, A: 255}
+	castleBackground = color.RGBA{R: 120, G: 90, B: 130, A: 255}
+	castlePlatform   = color.RGBA{R: 160, G: 140, B: 120, A: 255}
+	iceColor         = color.RGBA{R: 170, G: 215, B: 240, A: 255}
+)
+
+// stageDefinitions はステージ番号ごとの内容です（ワールドマップの扉の番号と対応）
+var stageDefinitions = map[int]stageDefinition{
+	1: {
+		Name: "Green Greens",
+		Rooms: []roomDefinition{
+			{
+				Background:    skyBackground,
+				PlatformColor: grassPlatform,
+				Platforms: []pixel.Rect{
+					pixel.R(150, 120, 350, 140),
+					pixel.R(450, 200, 600, 220),
+					pixel.R(700, 120, 900, 140),
+				},
+				// 扉の手前の星ブロック（どの攻撃でも壊せる）
+				Blocks:     blockColumn(stage.BlockStar, 620, 0, 2, 0),
+				WaddleDees: []pixel.Vec{pixel.V(250, 150), pixel.V(500, 100), pixel.V(800, 150)},
+				Doors:      []portal{{Pos: pixel.V(970, 0), Target: 1, Exit: pixel.V(80, 40)}},
+			},
+			{
+				// 横に長い森の道
+				Width:         1800,
+				Background:    skyBackground,
+				PlatformColor: grassPlatform,
+				Platforms: []pixel.Rect{
+					pixel.R(250, 140, 450, 160),
+					pixel.R(600, 240, 800, 260),
+					pixel.R(950, 140, 1150, 160),
+					pixel.R(1300, 240, 1500, 260),
+				},
+				// 森の池（泳いで渡る）
+				Hazards:    []stage.Hazard{{Kind: stage.HazardWater, Rect: pixel.R(500, 0, 1250, 120)}},
+				Items: []itemSpawn{
+					{Pos: pixel.V(350, 180), Kind: entity.ItemFood},
+					{Pos: pixel.V(650, 280), Kind: entity.ItemPointStar},
+					{Pos: pixel.V(750, 280), Kind: entity.ItemPointStar},
+					{Pos: pixel.V(1400, 280), Kind: entity.ItemFood},
+				},
+				WaddleDees:  []pixel.Vec{pixel.V(700, 280), pixel.V(1400, 280)},
+				Enemies:     []enemySpawn{{Pos: pixel.V(1050, 180), Type: entity.EnemyTypeJumper}},
+				Checkpoints: []pixel.Vec{pixel.V(1320, 0)},
+				// 森の入り口の屋台
+				Vendors: []pixel.Vec{pixel.V(160, 0)},
+				// 池の手前で復活するワドルディ役と、森の上を飛んでくるブロントバート
+				Spawners: []Spawner{
+					{Kind: SpawnRespawn, Pos: pixel.V(320, 40), Type: entity.EnemyTypeWalker, Interval: 3},
+					{Kind: SpawnEdge, Type: entity.EnemyTypeBronto, Region: pixel.R(200, 0, 1600, 400), Interval: 4, Max: 2},
+				},
+				Doors: []portal{{Pos: pixel.V(40, 0), Target: 0, Exit: pixel.V(930, 40)}},
+			},
+		},
+	},
+	2: {
+		Name: "Float Islands",
+		Rooms: []roomDefinition{
+			{
+				Background:    skyBackground,
+				PlatformColor: sandPlatform,
+				Platforms: []pixel.Rect{
+					pixel.R(80, 160, 240, 180),
+					pixel.R(320, 260, 480, 280),
+					pixel.R(560, 160, 720, 180),
+					pixel.R(800, 260, 960, 280),
+					pixel.R(430, 420, 600, 440),
+				},
+				WaddleDees: []pixel.Vec{pixel.V(160, 200), pixel.V(640, 200)},
+				Enemies: []enemySpawn{
+					{Pos: pixel.V(400, 320), Type: entity.EnemyTypeFlyer},
+					{Pos: pixel.V(880, 300), Type: entity.EnemyTypeJumper},
+				},
+				// 一番上の島のワープスターで空の島へ
+				WarpStars: []portal{{Pos: pixel.V(515, 470), Target: 1, Exit: pixel.V(150, 300)}},
+			},
+			{
+				Background:    cloudBackground,
+				PlatformColor: sandPlatform,
+				Platforms: []pixel.Rect{
+					pixel.R(60, 240, 260, 260),
+					pixel.R(380, 340, 560, 360),
+					pixel.R(680, 240, 880, 260),
+				},
+				// 島の間を吹き抜ける追い風
+				Hazards: []stage.Hazard{{Kind: stage.HazardWind, Rect: pixel.R(260, 0, 680, WindowHeight), Wind: pixel.V(120, 0)}},
+				Enemies: []enemySpawn{
+					{Pos: pixel.V(470, 420), Type: entity.EnemyTypeFlyer},
+					{Pos: pixel.V(780, 300), Type: entity.EnemyTypeWalker},
+				},
+				Doors: []portal{{Pos: pixel.V(960, 0), Target: 0, Exit: pixel.V(880, 300)}},
+			},
+		},
+	},
+	3: {
+		Name: "Bubbly Clouds",
+		Rooms: []roomDefinition{
+			{
+				Background:    cloudBackground,
+				PlatformColor: cloudPlatform,
+				Platforms: []pixel.Rect{
+					pixel.R(100, 140, 260, 160),
+					pixel.R(330, 240, 470, 260),
+					pixel.R(540, 340, 680, 360),
+					pixel.R(750, 240, 890, 260),
+					pixel.R(200, 420, 340, 440),
+				},
+				WaddleDoos: []pixel.Vec{pixel.V(400, 280)},
+				Enemies: []enemySpawn{
+					{Pos: pixel.V(250, 300), Type: entity.EnemyTypeFlyer},
+					{Pos: pixel.V(620, 420), Type: entity.EnemyTypeFlyer},
+				},
+				Doors: []portal{{Pos: pixel.V(820, 260), Target: 1, Exit: pixel.V(100, 40)}},
+			},
+			{
+				// 雲の中の通路
+				Width:         1600,
+				Background:    color.RGBA{R: 160, G: 180, B: 220, A: 255},
+				PlatformColor: cloudPlatform,
+				Platforms: []pixel.Rect{
+					pixel.R(300, 160, 500, 180),
+					pixel.R(1300, 320, 1500, 340),
+				},
+				IcePlatforms: []pixel.Rect{
+					pixel.R(650, 280, 850, 300),
+					pixel.R(1000, 160, 1200, 180),
+				},
+				WaddleDoos: []pixel.Vec{pixel.V(750, 320)},
+				Enemies:    []enemySpawn{{Pos: pixel.V(1100, 200), Type: entity.EnemyTypeJumper}},
+				Doors:      []portal{{Pos: pixel.V(50, 0), Target: 0, Exit: pixel.V(820, 300)}},
+				WarpStars:  []portal{{Pos: pixel.V(1400, 380), Target: 2, Exit: pixel.V(512, 300)}},
+				// 雲の通路の途中
+				Checkpoints: []pixel.Vec{pixel.V(400, 180)},
+				Spawners: []Spawner{
+					{Kind: SpawnRespawn, Pos: pixel.V(1200, 420), Type: entity.EnemyTypeFlyer, Interval: 4},
+					{Kind: SpawnEdge, Type: entity.EnemyTypeBronto, Region: pixel.R(0, 150, 1600, 500), Interval: 5, Max: 2},
+				},
+			},
+			{
+				// 雲の上
+				Background:    color.RGBA{R: 230, G: 200, B: 240, A: 255},
+				PlatformColor: cloudPlatform,
+				Platforms: []pixel.Rect{
+					pixel.R(150, 200, 350, 220),
+					pixel.R(670, 200, 870, 220),
+				},
+				IcePlatforms: []pixel.Rect{pixel.R(420, 120, 600, 140)},
+				Items:        []itemSpawn{{Pos: pixel.V(250, 240), Kind: entity.ItemCandy}},
+				// 雲の切れ目の上昇気流
+				Hazards: []stage.Hazard{
+					{Kind: stage.HazardWind, Rect: pixel.R(880, 0, 1000, 500), Wind: pixel.V(0, 160)},
+				},
+				Enemies: []enemySpawn{
+					{Pos: pixel.V(250, 260), Type: entity.EnemyTypeFlyer},
+					{Pos: pixel.V(770, 260), Type: entity.EnemyTypeFlyer},
+				},
+				Doors: []portal{{Pos: pixel.V(512, 140), Target: 1, Exit: pixel.V(1300, 380)}},
+			},
+		},
+	},
+	4: {
+		Name: "Dedede Castle",
+		Rooms: []roomDefinition{
+			{
+				// 城の入り口: ワドルディ中心
+				Width:         1400,
+				Background:    castleBackground,
+				PlatformColor: castlePlatform,
+				Platforms: []pixel.Rect{
+					pixel.R(200, 120, 400, 140),
+					pixel.R(550, 220, 850, 240),
+					pixel.R(1000, 120, 1200, 140),
+				},
+				// 床のトゲ
+				Hazards: []stage.Hazard{
+					{Kind: stage.HazardSpikes, Rect: pixel.R(450, 0, 540, 24)},
+					{Kind: stage.HazardSpikes, Rect: pixel.R(880, 0, 970, 24)},
+				},
+				// 足場の上のスイッチでリングへの扉の前のゲートが開く。
+				// 床のメタルブロックはストーン（ロッキーからコピー）かハンマーで壊せる
+				Blocks: joinBlocks(
+					blockColumn(stage.BlockGate, 1270, 0, 24, 1),
+					blockColumn(stage.BlockMetal, 1000, 0, 3, 0),
+					blockColumn(stage.BlockMetal, 1032, 0, 3, 0),
+				),
+				Switches:   []stage.Switch{{Pos: pixel.V(700, 240), Group: 1}},
+				// リングの前のマキシムトマトと中間ポイント
+				Items:       []itemSpawn{{Pos: pixel.V(1100, 160), Kind: entity.ItemMaxTomato}},
+				Checkpoints: []pixel.Vec{pixel.V(1180, 0)},
+				// リングの前に準備できる屋台
+				Vendors: []pixel.Vec{pixel.V(1115, 0)},
+				// ゲートの前に来ると上から待ち伏せの敵が降ってくる
+				Spawners: []Spawner{
+					{Kind: SpawnTrigger, Pos: pixel.V(1130, 400), Type: entity.EnemyTypeWalker, Region: pixel.R(1000, 0, 1270, 300), Count: 3},
+				},
+				WaddleDees: []pixel.Vec{pixel.V(300, 160), pixel.V(700, 260), pixel.V(1100, 160)},
+				Enemies: []enemySpawn{
+					{Pos: pixel.V(900, 300), Type: entity.EnemyTypeFlyer},
+					{Pos: pixel.V(150, 40), Type: entity.EnemyTypeRocky},
+				},
+				Doors: []portal{{Pos: pixel.V(1340, 0), Target: 1, Exit: pixel.V(120, 40)}},
+			},
+			{
+				// リング: デデデ大王
+				Background:    skyBackground,
+				PlatformColor: grassPlatform,
+				WaddleDees:    []pixel.Vec{pixel.V(400, 200)},
+				WaddleDoos:    []pixel.Vec{pixel.V(500, 250)},
+				Enemies:       []enemySpawn{{Pos: pixel.V(650, 180), Type: entity.EnemyTypeJumper}},
+				Boss:          entity.NewDededeBoss,
+				Doors:         []portal{{Pos: pixel.V(60, 0), Target: 0, Exit: pixel.V(1300, 40)}},
+				Checkpoints:   []pixel.Vec{pixel.V(140, 0)},
+			},
+		},
+	},
+	5: {
+		Name: "Upper Deck",
+		Rooms: []roomDefinition{
+			{
+				Background:    halberdSky,
+				PlatformColor: halberdPlatform,
+				Platforms: []pixel.Rect{
+					pixel.R(0, 100, 400, 120),
+					pixel.R(600, 100, 1024, 120),
+					pixel.R(420, 240, 580, 260),
+				},
+				// 甲板を吹く向かい風
+				Hazards:    []stage.Hazard{{Kind: stage.HazardWind, Rect: pixel.R(0, 120, 1024, 420), Wind: pixel.V(-90, 0)}},
+				WaddleDees: []pixel.Vec{pixel.V(150, 150), pixel.V(800, 150)},
+				WaddleDoos: []pixel.Vec{pixel.V(500, 280)},
+				Doors:      []portal{{Pos: pixel.V(960, 120), Target: 1, Exit: pixel.V(80, 40)}},
+			},
+			{
+				// 船室
+				Background:    halberdInterior,
+				PlatformColor: halberdPlatform,
+				Platforms: []pixel.Rect{
+					pixel.R(200, 160, 420, 180),
+					pixel.R(600, 160, 820, 180),
+				},
+				WaddleDoos: []pixel.Vec{pixel.V(310, 200)},
+				Enemies:    []enemySpawn{{Pos: pixel.V(710, 200), Type: entity.EnemyTypeWalker}},
+				Doors:      []portal{{Pos: pixel.V(40, 0), Target: 0, Exit: pixel.V(920, 160)}},
+			},
+		},
+	},
+	6: {
+		Name: "Engine Room",
+		Rooms: []roomDefinition{
+			{
+				Background:    engineBackground,
+				PlatformColor: enginePlatform,
+				Platforms: []pixel.Rect{
+					pixel.R(100, 130, 300, 150),
+					pixel.R(420, 130, 600, 150),
+					pixel.R(720, 130, 920, 150),
+					pixel.R(250, 300, 450, 320),
+					pixel.R(570, 300, 770, 320),
+				},
+				// 足場の間の溶岩
+				Hazards: []stage.Hazard{
+					{Kind: stage.HazardLava, Rect: pixel.R(300, 0, 420, 30)},
+					{Kind: stage.HazardLava, Rect: pixel.R(600, 0, 720, 30)},
+				},
+				// メタルブロックに囲まれた爆弾ブロックは、壁の下を通る導火線にファイア（ホットヘッドからコピー）で
+				// 火をつけると爆発し、囲いごと吹き飛ぶ
+				Blocks: joinBlocks(
+					fuseLine(740, 0, 5),
+					blockColumn(stage.BlockMetal, 836, 8, 3, 1),
+					blockColumn(stage.BlockMetal, 868, 8, 3, 1),
+					blockRow(stage.BlockBomb, 900, 0, 1, 1),
+					blockColumn(stage.BlockMetal, 900, 32, 2, 1),
+					blockColumn(stage.BlockMetal, 932, 0, 3, 1),
+				),
+				WaddleDoos: []pixel.Vec{pixel.V(200, 180), pixel.V(820, 180)},
+				Enemies: []enemySpawn{
+					{Pos: pixel.V(500, 200), Type: entity.EnemyTypeJumper},
+					{Pos: pixel.V(350, 360), Type: entity.EnemyTypeJumper},
+					{Pos: pixel.V(150, 40), Type: entity.EnemyTypeHothead},
+				},
+				// 機関室の奥から次々に出てくる作業員
+				Spawners: []Spawner{
+					{Kind: SpawnTimed, Pos: pixel.V(40, 40), Type: entity.EnemyTypeWalker, Interval: 6, Max: 2},
+				},
+			},
+		},
+	},
+	7: {
+		Name: "Cannon Bay",
+		Rooms: []roomDefinition{
+			{
+				Background:    halberdSky,
+				PlatformColor: halberdPlatform,
+				Platforms: []pixel.Rect{
+					pixel.R(80, 200, 280, 220),
+					pixel.R(380, 120, 640, 140),
+					pixel.R(740, 200, 940, 220),
+					pixel.R(430, 360, 590, 380),
+				},
+				WaddleDees: []pixel.Vec{pixel.V(500, 160)},
+				WaddleDoos: []pixel.Vec{pixel.V(180, 240), pixel.V(840, 240)},
+				Enemies:    []enemySpawn{{Pos: pixel.V(500, 420), Type: entity.EnemyTypeFlyer}},
+				// 砲台の上のワープスターで甲板の先へ
+				WarpStars: []portal{{Pos: pixel.V(510, 420), Target: 1, Exit: pixel.V(150, 200)}},
+			},
+			{
+				Width:         1600,
+				Background:    halberdSky,
+				PlatformColor: halberdPlatform,
+				Platforms: []pixel.Rect{
+					pixel.R(0, 100, 500, 120),
+					pixel.R(650, 180, 950, 200),
+					pixel.R(1100, 100, 1600, 120),
+				},
+				// 甲板の切れ目のトゲ
+				Hazards: []stage.Hazard{{Kind: stage.HazardSpikes, Rect: pixel.R(540, 0, 1060, 24)}},
+				// 爆弾ブロックを叩くと積まれたメタルブロックまで連鎖して壊れる
+				Blocks: joinBlocks(
+					blockRow(stage.BlockBomb, 660, 200, 1, 2),
+					blockRow(stage.BlockMetal, 760, 200, 4, 2),
+					blockRow(stage.BlockMetal, 760, 232, 4, 2),
+					blockRow(stage.BlockStar, 1200, 120, 3, 0),
+				),
+				// 星ブロックの上の1UP
+				Items:       []itemSpawn{{Pos: pixel.V(1248, 170), Kind: entity.Item1Up}},
+				Checkpoints: []pixel.Vec{pixel.V(250, 120)},
+				WaddleDoos: []pixel.Vec{pixel.V(800, 220)},
+				Enemies: []enemySpawn{
+					{Pos: pixel.V(400, 300), Type: entity.EnemyTypeFlyer},
+					{Pos: pixel.V(1300, 160), Type: entity.EnemyTypeJumper},
+				},
+				// 甲板の上を次々に飛んでくるブロントバート
+				Spawners: []Spawner{
+					{Kind: SpawnEdge, Type: entity.EnemyTypeBronto, Region: pixel.R(0, 100, 1600, 500), Interval: 3.5, Max: 3},
+				},
+				Doors: []portal{{Pos: pixel.V(1540, 120), Target: 0, Exit: pixel.V(180, 240)}},
+			},
+		},
+	},
+	8: {
+		Name: "Halberd Bridge",
+		Rooms: []roomDefinition{
+			{
+				// ブリッジへの通路: ワドルドゥ中心
+				Background:    halberdInterior,
+				PlatformColor: halberdPlatform,
+				Platforms: []pixel.Rect{
+					pixel.R(150, 140, 350, 160),
+					pixel.R(650, 140, 850, 160),
+					pixel.R(400, 280, 600, 300),
+				},
+				WaddleDoos: []pixel.Vec{pixel.V(250, 180), pixel.V(750, 180)},
+				Enemies:    []enemySpawn{{Pos: pixel.V(500, 360), Type: entity.EnemyTypeFlyer}},
+				Items:      []itemSpawn{{Pos: pixel.V(500, 330), Kind: entity.ItemMaxTomato}},
+				Vendors:    []pixel.Vec{pixel.V(70, 0)},
+				Doors:      []portal{{Pos: pixel.V(500, 300), Target: 1, Exit: pixel.V(120, 40)}},
+			},
+			{
+				// ブリッジ: メタナイト
+				Background:    skyBackground,
+				PlatformColor: grassPlatform,
+				WaddleDees:    []pixel.Vec{pixel.V(300, 150)},
+				WaddleDoos:    []pixel.Vec{pixel.V(600, 250), pixel.V(800, 150)},
+				Enemies:       []enemySpawn{{Pos: pixel.V(650, 180), Type: entity.EnemyTypeJumper}},
+				Boss:          entity.NewMetaKnightBoss,
+				Doors:         []portal{{Pos: pixel.V(60, 0), Target: 0, Exit: pixel.V(500, 340)}},
+				Checkpoints:   []pixel.Vec{pixel.V(140, 0)},
+			},
+		},
+	},
+}
+
+// stageDefinitionFor はステージの内容を返します（ない番号なら最初のステージ）
+func stageDefinitionFor(stageNum int) stageDefinition {
+	if def, ok := stageDefinitions[stageNum]; ok {
+		return def
+	}
+	return stageDefinitions[1]
+}
+
+// buildStage は部屋の地形とブロック・扉・ワープスターを作成します
+func (def roomDefinition) buildStage(width, height float64) *stage.Stage {
+	if def.Width > 0 {
+		width = def.Width
+	}
+	
+	var s *stage.Stage
+	if def.Platforms == nil {
+		s = stage.CreateDefaultStage(width, height)
+	} else {
+		s = stage.NewStage(width, height)
+		for _, r := range def.Platforms {
+			s.AddPlatform(stage.NewPlatform(r.Min.X, r.Min.Y, r.W(), r.H()))
+		}
+	}
+	s.Background = def.Background
+	for _, platform := range s.Platforms {
+		platform.Color = def.PlatformColor
+	}
+	for _, r := range def.IcePlatforms {
+		platform := stage.NewPlatform(r.Min.X, r.Min.Y, r.W(), r.H())
+		platform.Color = iceColor
+		platform.Ice = true
+		s.AddPlatform(platform)
+	}
+	for _, h := range def.Hazards {
+		hazard := h
+		s.AddHazard(&hazard)
+	}
+	for _, b := range def.Blocks {
+		s.AddBlock(stage.NewBlock(b.Kind, b.Rect, b.Group))
+	}
+	for _, sw := range def.Switches {
+		s.AddSwitch(stage.NewSwitch(sw.Pos, sw.Group))
+	}
+	
+	for _, d := range def.Doors {
+		s.AddDoor(stage.NewDoor(d.Pos, d.Target, d.Exit))
+	}
+	for _, w := range def.WarpStars {
+		s.AddWarpStar(stage.NewWarpStar(w.Pos, w.Target, w.Exit))
+	}
+	for _, pos := range def.Checkpoints {
+		s.AddCheckpoint(stage.NewCheckpoint(pos))
+	}
+	for _, pos := range def.Vendors {
+		s.AddVendor(stage.NewVendor(pos))
+	}
+	return s
+}
+
+// buildRoom は部屋の地形と敵・ボスを配置します
+func (def roomDefinition) buildRoom(width, height float64) *Room {
+	s := def.buildStage(width, height)
+	room := &Room{
+		Stage:      s,
+		Enemies:    []*entity.Enemy{},
+		WaddleDees: []*entity.WaddleDee{},
+		WaddleDoos: []*entity.WaddleDoo{},
+	}
+	
+	for _, pos := range def.WaddleDees {
+		room.WaddleDees = append(room.WaddleDees, entity.NewWaddleDee(pos))
+	}
+	for _, pos := range def.WaddleDoos {
+		room.WaddleDoos = append(room.WaddleDoos, entity.NewWaddleDoo(pos))
+	}
+	for _, spawn := range def.Enemies {
+		room.Enemies = append(room.Enemies, entity.NewEnemy(spawn.Pos, spawn.Type))
+	}
+	for _, spawn := range def.Items {
+		room.Items = append(room.Items, entity.NewPlacedItem(spawn.Pos, spawn.Kind))
+	}
+	for i, spawn := range def.Spawners {
+		sp := NewSpawner(spawn)
+		room.Spawners = append(room.Spawners, sp)
+		// 復活する敵は最初から置いておく
+		if sp.Kind == SpawnRespawn {
+			room.Enemies = append(room.Enemies, sp.newEnemy(i+1, sp.Pos))
+		}
+	}
+	if def.Boss != nil {
+		room.Boss = def.Boss(pixel.V(s.Width-200, 200))
+	}
+	return room
+}
+
+// setupStageRooms はステージのすべての部屋を作り、最初の部屋に入ります
+func (g *Game) setupStageRooms(def stageDefinition) {
+	g.Rooms = make([]*Room, len(def.Rooms))
+	for i, room := range def.Rooms {
+		g.Rooms[i] = room.buildRoom(WindowWidth, WindowHeight)
+	}
+	g.CurrentRoom = 0
+	g.loadRoom(0)
+}
+
+// stageCleared はストーリーのステージをクリアしたかを返します。
+// ボスのいるステージはボスを倒したか、いなければすべての部屋の敵を倒したかで決まります
+func (g *Game) stageCleared() bool {
+	rooms := g.allRooms()
+	
+	hasBoss := false
+	for _, room := range rooms {
+		if room.Boss != nil {
+			hasBoss = true
+			if room.Boss.IsAlive {
+				return false
+			}
+		}
+	}
+	if hasBoss {
+		return true
+	}
+	
+	for _, room := range rooms {
+		if room.enemiesLeft() > 0 {
+			return false
+		}
+	}
+	return true
+}
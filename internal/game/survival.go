@@ -0,0 +1,245 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+
+	"github.com/remmakoshino/kirby-inspired-go/internal/entity"
+	"github.com/remmakoshino/kirby-inspired-go/internal/menu"
+	"github.com/remmakoshino/kirby-inspired-go/internal/rng"
+	"github.com/remmakoshino/kirby-inspired-go/internal/stage"
+)
+
+const (
+	SurvivalBreakTime    = 4.0  // 波を倒してから次の波までの休憩
+	SurvivalBossEvery    = 5    // ボスの波の間隔（5の倍数の波にボスが出る）
+	SurvivalBaseEnemies  = 3    // 最初の波の敵の数
+	SurvivalMaxEnemies   = 12   // 1つの波の敵の数の上限
+	SurvivalTierEvery    = 3    // 強い段階の敵が混ざり始める波の間隔
+	SurvivalHealthGrowth = 0.1  // 波ごとの敵の体力の増え方
+	SurvivalBossGrowth   = 0.25 // ボスの波ごとのボスの体力の増え方
+	SurvivalWaveScore    = 100  // 波を倒した時のスコア（波の番号をかける）
+	SurvivalHealItems    = 2    // 休憩中に降ってくる回復アイテムの数
+)
+
+// SurvivalRun はサバイバルの進行状況を表します
+type SurvivalRun struct {
+	Wave      int     // 今の波（1から）
+	Break     float64 // 次の波までの休憩の残り時間（戦闘中は0）
+	Best      int     // 始めた時点の最高記録
+	NewRecord bool    // 最高記録を更新した（ゲームオーバー時に決まる）
+}
+
+// InitializeSurvival はサバイバル用のアリーナを初期化し、最初の波を出します
+func (g *Game) InitializeSurvival(characters []string) {
+	g.Mode = menu.ModeSurvival
+	g.Versus = nil
+	g.BossRush = nil
+	g.TimeAttack = nil
+	g.CurrentStage = 0
+	g.PlayerCharacters = characters
+	g.GameOver = false
+	g.Victory = false
+	g.StageTime = 0
+	g.Pickups = nil
+	g.ProgressRecorded = false
+	g.Score = 0
+	
+	g.Stage = stage.CreateArenaStage(WindowWidth, WindowHeight)
+	g.Rooms = nil
+	g.CurrentRoom = 0
+	g.Transition = nil
+	g.DoorHeld = nil
+	g.DeathTimer = 0
+	
+	g.Characters = []entity.PlayableCharacter{}
+	for i, id := range characters {
+		offset := (float64(i) - float64(len(characters)-1)/2) * 60
+		if c := entity.NewPlayableCharacter(id, pixel.V(g.Stage.Width/2+offset, 200)); c != nil {
+			g.Characters = append(g.Characters, c)
+		}
+	}
+	g.Brains = make([]*entity.HelperBrain, len(g.Characters))
+	g.Camera = NewCamera(pixel.V(g.Stage.Width/2, g.Stage.Height/2))
+	g.setFallPoints(pixel.V(g.Stage.Width/2, 200))
+	
+	g.Enemies = nil
+	g.Spawners = nil
+	g.WaddleDees = nil
+	g.WaddleDoos = nil
+	g.Boss = nil
+	g.Projectiles = nil
+	g.Items = nil
+	
+	g.LevelUps = nil
+	g.applyProgression()
+	
+	g.Survival = &SurvivalRun{}
+	if g.SaveData != nil {
+		g.Survival.Best = g.SaveData.SurvivalBestWave
+	}
+	g.startWave(1)
+}
+
+// updateSurvival は波の全滅と休憩、全員がやられた時のゲームオーバーを処理します
+func (g *Game) updateSurvival(dt float64) {
+	run := g.Survival
+	if run == nil {
+		return
+	}
+	
+	if g.allCharactersDefeated() {
+		g.GameOver = true
+		return
+	}
+	
+	// 休憩が終わったら次の波
+	if run.Break > 0 {
+		run.Break -= dt
+		if run.Break <= 0 {
+			run.Break = 0
+			g.startWave(run.Wave + 1)
+		}
+		return
+	}
+	
+	if g.waveCleared() {
+		g.Score += SurvivalWaveScore * run.Wave
+		run.Break = SurvivalBreakTime
+		g.dropHealItems(run.Wave%SurvivalBossEvery == 0)
+	}
+}
+
+// waveCleared は今の波の敵とボスをすべて倒したかを返します
+func (g *Game) waveCleared() bool {
+	for _, e := range g.Enemies {
+		if e.IsAlive {
+			return false
+		}
+	}
+	return g.Boss == nil || !g.Boss.IsAlive
+}
+
+// startWave は wave 番目の波を出します。波が進むほど敵は多く、強い段階の敵が混ざり、体力も増えます。
+// SurvivalBossEvery の倍数の波はボスと少しの取り巻きです
+func (g *Game) startWave(wave int) {
+	g.Survival.Wave = wave
+	g.Enemies = nil
+	g.Boss = nil
+	
+	healthScale := 1 + SurvivalHealthGrowth*float64(wave-1)
+	count := SurvivalBaseEnemies + wave/2
+	if count > SurvivalMaxEnemies {
+		count = SurvivalMaxEnemies
+	}
+	
+	// ボスは登録順に交代で出る
+	if bosses := entity.BossInfos(); wave%SurvivalBossEvery == 0 && len(bosses) > 0 {
+		round := wave / SurvivalBossEvery
+		boss := bosses[(round-1)%len(bosses)].New(pixel.V(g.Stage.Width-200, 200))
+		boss.MaxHealth = int(float64(boss.MaxHealth) * (1 + SurvivalBossGrowth*float64(round-1)))
+		boss.Health = boss.MaxHealth
+		g.Boss = boss
+		count = round
+	}
+	
+	maxTier := 1 + (wave-1)/SurvivalTierEvery
+	for i := 0; i < count; i++ {
+		e := g.newWaveEnemy(maxTier)
+		e.MaxHealth = int(float64(e.MaxHealth) * healthScale)
+		e.Health = e.MaxHealth
+		g.Enemies = append(g.Enemies, e)
+	}
+}
+
+// newWaveEnemy は登録済みの敵から maxTier 以下の段階の敵を1体選び、アリーナの上空に出します
+func (g *Game) newWaveEnemy(maxTier int) *entity.Enemy {
+	var candidates []entity.EnemyInfo
+	for _, info := range entity.EnemyInfos() {
+		if info.Tier <= maxTier {
+			candidates = append(candidates, info)
+		}
+	}
+	x := 80 + rng.Float64()*(g.Stage.Width-160)
+	pos := pixel.V(x, g.Stage.Height-60)
+	if len(candidates) == 0 {
+		return entity.NewEnemy(pos, entity.EnemyTypeWalker)
+	}
+	info := candidates[rng.Intn(len(candidates))]
+	e := entity.NewEnemyByID(info.ID, pos)
+	// 飛ぶ敵は届く高さに出す
+	if e.Flies() {
+		e.Position.Y = 250 + rng.Float64()*200
+		e.StartPosition = e.Position
+	}
+	return e
+}
+
+// dropHealItems は休憩中に回復アイテムを降らせます（ボスの波の後はマキシムトマト）
+func (g *Game) dropHealItems(afterBoss bool) {
+	for i := 0; i < SurvivalHealItems; i++ {
+		kind := entity.ItemFood
+		if afterBoss && i == 0 {
+			kind = entity.ItemMaxTomato
+		}
+		x := 80 + rng.Float64()*(g.Stage.Width-160)
+		g.Items = append(g.Items, entity.NewItem(pixel.V(x, g.Stage.Height-40), kind))
+	}
+}
+
+// recordSurvival はゲームオーバーになったサバイバルの到達した波をセーブデータに記録します。
+// 巻き戻しで何度も呼ばれる Step ではなく、updateProgress から1回だけ呼びます
+func (g *Game) recordSurvival() {
+	run := g.Survival
+	if run == nil || g.SaveData == nil {
+		return
+	}
+	run.NewRecord = g.SaveData.RecordSurvival(run.Wave)
+	g.writeSave()
+}
+
+// drawSurvivalHUD は今の波と最高記録、休憩中の案内を表示します
+func (g *Game) drawSurvivalHUD() {
+	run := g.Survival
+	
+	waveText := text.New(pixel.V(WindowWidth-130, WindowHeight-30), g.Atlas)
+	waveText.Color = colornames.White
+	fmt.Fprintf(waveText, "WAVE %d", run.Wave)
+	waveText.Draw(g.Window, pixel.IM.Scaled(waveText.Orig, 2))
+	
+	left := 0
+	for _, e := range g.Enemies {
+		if e.IsAlive {
+			left++
+		}
+	}
+	leftText := text.New(pixel.V(10, WindowHeight-125), g.Atlas)
+	leftText.Color = colornames.White
+	fmt.Fprintf(leftText, "Enemies: %d", left)
+	leftText.Draw(g.Window, pixel.IM.Scaled(leftText.Orig, 1.5))
+	
+	bestText := text.New(pixel.V(10, WindowHeight-145), g.Atlas)
+	bestText.Color = colornames.Lightgreen
+	fmt.Fprintf(bestText, "Best: Wave %d", run.Best)
+	bestText.Draw(g.Window, pixel.IM.Scaled(bestText.Orig, 1.5))
+	
+	if run.Break > 0 && !g.GameOver {
+		clearText := text.New(pixel.V(WindowWidth/2-150, WindowHeight/2+80), g.Atlas)
+		clearText.Color = colornames.Gold
+		fmt.Fprintf(clearText, "WAVE %d CLEAR!", run.Wave)
+		clearText.Draw(g.Window, pixel.IM.Scaled(clearText.Orig, 3))
+		
+		nextText := text.New(pixel.V(WindowWidth/2-90, WindowHeight/2+40), g.Atlas)
+		nextText.Color = colornames.White
+		if (run.Wave+1)%SurvivalBossEvery == 0 {
+			nextText.Color = colornames.Orangered
+			fmt.Fprintf(nextText, "BOSS WAVE in %d", int(run.Break+0.999))
+		} else {
+			fmt.Fprintf(nextText, "Next wave in %d", int(run.Break+0.999))
+		}
+		nextText.Draw(g.Window, pixel.IM.Scaled(nextText.Orig, 2))
+	}
+}
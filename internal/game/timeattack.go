@@ -0,0 +1,273 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+
+	"github.com/remmakoshino/kirby-inspired-go/internal/entity"
+	"github.com/remmakoshino/kirby-inspired-go/internal/menu"
+	"github.com/remmakoshino/kirby-inspired-go/internal/netcode"
+	"github.com/remmakoshino/kirby-inspired-go/internal/save"
+)
+
+const (
+	GhostSampleEvery = 2    // ゴーストの位置を記録するフレームの間隔
+	GhostRadius      = 20.0 // ゴーストの大きさ
+	SplitShowTime    = 3.0  // 中間ポイントのスプリットを表示する時間
+)
+
+// ゴーストの色（半透明）
+var (
+	ghostBody    = color.RGBA{R: 90, G: 100, B: 130, A: 120}
+	ghostOutline = color.RGBA{R: 150, G: 170, B: 210, A: 200}
+	ghostEyes    = color.RGBA{R: 20, G: 20, B: 50, A: 140}
+)
+
+// TimeAttackRun はタイムアタックの走りの状態です。
+// 時間は Step を呼んだ回数（固定フレーム）で数えるので、描画の速さによらず同じ操作なら同じタイムになります
+type TimeAttackRun struct {
+	Frames     int                    // スタートからのフレーム数
+	Splits     []int                  // 中間ポイントに触れた時のフレーム数（触れた順）
+	Trace      []save.GhostFrame      // この走りの1Pの位置（自己ベストならゴーストとして保存する）
+	Best       *save.TimeAttackRecord // 始めた時点の自己ベスト（なければ nil）
+	NewRecord  bool                   // 自己ベストを更新した（ゴール時に決まる）
+	SplitShown float64                // 最後のスプリットを表示する残り時間
+	
+	accumulator float64              // 固定フレームに満たない経過時間
+	pending     []entity.PlayerInput // 次のフレームに渡すプレイヤーごとの入力
+}
+
+// InitializeTimeAttack はステージをタイムアタックとして始め、自己ベストとそのゴーストを読み込みます
+func (g *Game) InitializeTimeAttack(stageNum int, characters []string) {
+	g.InitializeStage(stageNum, characters)
+	g.Mode = menu.ModeTimeAttack
+	g.TimeAttack = &TimeAttackRun{}
+	g.applyProgression() // 誰でも同じ条件で走れるようにレベル1の能力値に戻す
+	
+	if g.SaveData == nil {
+		return
+	}
+	best, ok := g.SaveData.TimeAttack[stageNum]
+	if !ok {
+		return
+	}
+	g.TimeAttack.Best = best
+	
+	// 自己ベストと同じタイムのゴーストだけを使う（別の記録のゴーストが残っていても出さない）
+	if g.Saves == nil {
+		return
+	}
+	if ghost, err := g.Saves.LoadGhost(g.SaveSlot, stageNum); err == nil && ghost.Frames == best.Frames {
+		g.Ghost = ghost
+	}
+}
+
+// stepTimeAttack は経過時間の分だけ固定フレーム（netcode.FixedStep）で Step を進めます。
+// 押した瞬間の入力はフレームが進むまで残すので、描画が速くても遅くても取りこぼしません
+func (g *Game) stepTimeAttack(dt float64, inputs []entity.PlayerInput) {
+	run := g.TimeAttack
+	if len(run.pending) != len(inputs) {
+		run.pending = make([]entity.PlayerInput, len(inputs))
+	}
+	for i, in := range inputs {
+		run.pending[i] = latchInput(run.pending[i], in)
+	}
+	
+	run.accumulator = math.Min(run.accumulator+dt, maxNetplayCatchUp)
+	for run.accumulator >= netcode.FixedStep {
+		run.accumulator -= netcode.FixedStep
+		g.Step(netcode.FixedStep, run.pending)
+		for i, in := range inputs {
+			run.pending[i] = releasePresses(in)
+		}
+	}
+}
+
+// tickTimeAttack はフレームを数え、ゴースト用に1Pの位置を記録します（Step から1フレームに1回呼ぶ）
+func (g *Game) tickTimeAttack(dt float64) {
+	run := g.TimeAttack
+	if run.SplitShown > 0 {
+		run.SplitShown -= dt
+	}
+	if run.Frames%GhostSampleEvery == 0 && len(g.Characters) > 0 {
+		pos := g.Characters[0].GetPosition()
+		run.Trace = append(run.Trace, save.GhostFrame{
+			Room: g.CurrentRoom,
+			X:    math.Round(pos.X*10) / 10,
+			Y:    math.Round(pos.Y*10) / 10,
+		})
+	}
+	run.Frames++
+}
+
+// recordSplit は中間ポイントに触れた時のタイムを記録します
+func (g *Game) recordSplit() {
+	run := g.TimeAttack
+	if run == nil {
+		return
+	}
+	run.Splits = append(run.Splits, run.Frames)
+	run.SplitShown = SplitShowTime
+}
+
+// bestSplit は自己ベストの i 番目のスプリットを返します（なければ false）
+func (run *TimeAttackRun) bestSplit(i int) (int, bool) {
+	if run.Best == nil || i >= len(run.Best.Splits) {
+		return 0, false
+	}
+	return run.Best.Splits[i], true
+}
+
+// recordTimeAttack はゴールしたタイムを自己ベストの表に記録し、更新したら走りをゴーストとして保存します。
+// 巻き戻しで何度も呼ばれる Step ではなく、updateProgress から1回だけ呼びます
+func (g *Game) recordTimeAttack() {
+	run := g.TimeAttack
+	if run == nil || g.SaveData == nil || len(g.PlayerCharacters) == 0 {
+		return
+	}
+	run.NewRecord = g.SaveData.RecordTimeAttack(g.CurrentStage, g.PlayerCharacters[0], run.Frames, run.Splits)
+	if run.NewRecord && g.Saves != nil {
+		ghost := &save.Ghost{
+			Stage:     g.CurrentStage,
+			Character: g.PlayerCharacters[0],
+			Frames:    run.Frames,
+			Every:     GhostSampleEvery,
+			Trace:     run.Trace,
+		}
+		if err := g.Saves.SaveGhost(g.SaveSlot, ghost); err != nil {
+			g.MenuManager.Notice = fmt.Sprintf("Ghost save failed: %v", err)
+		}
+	}
+	g.writeSave()
+}
+
+// drawGhost は自己ベストの走りの同じフレームの位置に、半透明のゴーストを描画します（同じ部屋にいる時だけ）
+func (g *Game) drawGhost() {
+	ghost := g.Ghost
+	frames := g.TimeAttack.Frames
+	i := frames / ghost.Every
+	if i >= len(ghost.Trace) {
+		return
+	}
+	cur := ghost.Trace[i]
+	if cur.Room != g.CurrentRoom {
+		return
+	}
+	
+	// 記録したコマの間はなめらかにつなぐ
+	pos := pixel.V(cur.X, cur.Y)
+	if i+1 < len(ghost.Trace) && ghost.Trace[i+1].Room == cur.Room {
+		next := ghost.Trace[i+1]
+		t := float64(frames%ghost.Every) / float64(ghost.Every)
+		pos = pos.Add(pixel.V(next.X, next.Y).Sub(pos).Scaled(t))
+	}
+	
+	imd := g.IMDraw
+	imd.Color = ghostBody
+	imd.Push(pos)
+	imd.Circle(GhostRadius, 0)
+	imd.Color = ghostOutline
+	imd.Push(pos)
+	imd.Circle(GhostRadius, 2)
+	imd.Color = ghostEyes
+	imd.Push(pos.Add(pixel.V(-6, 5)))
+	imd.Ellipse(pixel.V(2.5, 5), 0)
+	imd.Push(pos.Add(pixel.V(6, 5)))
+	imd.Ellipse(pixel.V(2.5, 5), 0)
+}
+
+// drawTimeAttackHUD はタイム、自己ベスト、中間ポイントのスプリットと自己ベストとの差を表示します
+func (g *Game) drawTimeAttackHUD() {
+	run := g.TimeAttack
+	
+	// ボスのHPバーと重ならないように、ボス戦ではその下に出す
+	top := WindowHeight - 40.0
+	if g.Boss != nil && g.Boss.IsAlive {
+		top -= 50
+	}
+	
+	timeText := text.New(pixel.V(WindowWidth/2-90, top), g.Atlas)
+	timeText.Color = colornames.White
+	fmt.Fprintf(timeText, "%s", formatRaceTime(run.Frames))
+	timeText.Draw(g.Window, pixel.IM.Scaled(timeText.Orig, 2.5))
+	
+	if run.Best != nil {
+		bestText := text.New(pixel.V(WindowWidth/2-60, top-25), g.Atlas)
+		bestText.Color = colornames.Lightgreen
+		fmt.Fprintf(bestText, "PB %s", formatRaceTime(run.Best.Frames))
+		bestText.Draw(g.Window, pixel.IM.Scaled(bestText.Orig, 1.5))
+	}
+	
+	if run.SplitShown <= 0 || len(run.Splits) == 0 {
+		return
+	}
+	i := len(run.Splits) - 1
+	splitText := text.New(pixel.V(WindowWidth/2-110, top-50), g.Atlas)
+	splitText.Color = colornames.White
+	fmt.Fprintf(splitText, "CP%d %s", i+1, formatRaceTime(run.Splits[i]))
+	if best, ok := run.bestSplit(i); ok {
+		splitText.Color = splitDeltaColor(run.Splits[i] - best)
+		fmt.Fprintf(splitText, "  %s", formatSplitDelta(run.Splits[i]-best))
+	}
+	splitText.Draw(g.Window, pixel.IM.Scaled(splitText.Orig, 1.5))
+}
+
+// drawTimeAttackResults はゴールした時のスプリットの表（自己ベストとの差つき）を表示します
+func (g *Game) drawTimeAttackResults() {
+	run := g.TimeAttack
+	
+	table := text.New(pixel.V(WindowWidth/2-140, WindowHeight/2-150), g.Atlas)
+	for i, split := range run.Splits {
+		table.Color = colornames.White
+		fmt.Fprintf(table, "CP%-3d %s", i+1, formatRaceTime(split))
+		if best, ok := run.bestSplit(i); ok {
+			table.Color = splitDeltaColor(split - best)
+			fmt.Fprintf(table, "  %s", formatSplitDelta(split-best))
+		}
+		fmt.Fprintf(table, "\n")
+	}
+	table.Color = colornames.Gold
+	fmt.Fprintf(table, "GOAL  %s", formatRaceTime(run.Frames))
+	if run.Best != nil {
+		table.Color = splitDeltaColor(run.Frames - run.Best.Frames)
+		fmt.Fprintf(table, "  %s", formatSplitDelta(run.Frames-run.Best.Frames))
+	}
+	if run.NewRecord {
+		table.Color = colornames.Gold
+		fmt.Fprintf(table, "  NEW RECORD!")
+	}
+	table.Draw(g.Window, pixel.IM.Scaled(table.Orig, 1.5))
+}
+
+// splitDeltaColor は自己ベストとの差の色を返します（速ければ緑、遅ければ赤）
+func splitDeltaColor(delta int) color.RGBA {
+	if delta < 0 {
+		return colornames.Lime
+	}
+	if delta > 0 {
+		return colornames.Tomato
+	}
+	return colornames.White
+}
+
+// formatRaceTime はタイムアタックのフレーム数を「分:秒.1/100秒」の文字列にします
+func formatRaceTime(frames int) string {
+	cs := int(save.FrameSeconds(frames) * 100)
+	return fmt.Sprintf("%d:%02d.%02d", cs/6000, cs/100%60, cs%100)
+}
+
+// formatSplitDelta は自己ベストとのフレーム数の差を「+秒.1/100秒」の文字列にします
+func formatSplitDelta(frames int) string {
+	sign := "+"
+	if frames < 0 {
+		sign = "-"
+		frames = -frames
+	}
+	cs := int(save.FrameSeconds(frames) * 100)
+	return fmt.Sprintf("%s%d.%02d", sign, cs/100, cs%100)
+}
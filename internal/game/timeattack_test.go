@@ -0,0 +1,187 @@
+package game
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/faiface/pixel"
+
+	"github.com/remmakoshino/kirby-inspired-go/internal/entity"
+	"github.com/remmakoshino/kirby-inspired-go/internal/menu"
+	"github.com/remmakoshino/kirby-inspired-go/internal/netcode"
+	"github.com/remmakoshino/kirby-inspired-go/internal/save"
+	"github.com/remmakoshino/kirby-inspired-go/internal/stage"
+)
+
+// timeAttackGame はステージ1のタイムアタックを始め、敵のいない平らなステージに置き換えたゲームを作ります
+func timeAttackGame(t *testing.T, store *save.Store, data *save.Data) *Game {
+	t.Helper()
+	g := NewHeadlessGame()
+	g.MenuManager = menu.NewMenuManager(nil)
+	g.Saves = store
+	g.SaveData = data
+	g.InitializeTimeAttack(1, []string{"Kirby"})
+	
+	g.Enemies = nil
+	g.WaddleDees = nil
+	g.WaddleDoos = nil
+	g.Spawners = nil
+	g.Stage = stage.NewStage(1200, 600)
+	
+	c := g.Characters[0]
+	c.SetPosition(pixel.V(100, c.GetRadius()))
+	return g
+}
+
+// runTimeAttack は描画1回に dt 秒かかるとして frames 回分を進めます。最初の描画でだけ first を押します
+func runTimeAttack(g *Game, dt float64, frames int, first entity.PlayerInput) {
+	for i := 0; i < frames; i++ {
+		in := entity.PlayerInput{}
+		if i == 0 {
+			in = first
+		}
+		g.stepTimeAttack(dt, []entity.PlayerInput{in})
+	}
+}
+
+func TestTimeAttackIgnoresRenderRate(t *testing.T) {
+	const step = netcode.FixedStep
+	jump := entity.PlayerInput{Jump: true}
+	
+	// 同じ1秒を、30fps（描画1回で2フレーム）と120fps（描画2回で1フレーム）で進める
+	slow := timeAttackGame(t, nil, nil)
+	runTimeAttack(slow, step*2, 30, jump)
+	fast := timeAttackGame(t, nil, nil)
+	runTimeAttack(fast, step/2, 120, jump)
+	still := timeAttackGame(t, nil, nil)
+	runTimeAttack(still, step/2, 120, entity.PlayerInput{})
+	
+	if slow.TimeAttack.Frames != 60 || fast.TimeAttack.Frames != 60 {
+		t.Fatalf("Frames = %d (30fps), %d (120fps), want 60", slow.TimeAttack.Frames, fast.TimeAttack.Frames)
+	}
+	if !reflect.DeepEqual(slow.TimeAttack.Trace, fast.TimeAttack.Trace) {
+		t.Errorf("the same inputs gave different runs at 30fps and 120fps")
+	}
+	
+	// フレームが進まない描画で押したジャンプも、次のフレームまで残して使う
+	if reflect.DeepEqual(fast.TimeAttack.Trace, still.TimeAttack.Trace) {
+		t.Errorf("a jump pressed between fixed frames was dropped")
+	}
+}
+
+func TestTimeAttackRecordsSplitsAtCheckpoints(t *testing.T) {
+	g := timeAttackGame(t, nil, nil)
+	checkpoints := []*stage.Checkpoint{
+		stage.NewCheckpoint(pixel.V(300, 0)),
+		stage.NewCheckpoint(pixel.V(600, 0)),
+	}
+	for _, cp := range checkpoints {
+		g.Stage.AddCheckpoint(cp)
+	}
+	
+	// 右へ走りながら、中間ポイントに触れたフレームを調べる
+	var touched []int
+	right := []entity.PlayerInput{{MoveRight: true}}
+	for i := 0; i < 300 && len(touched) < len(checkpoints); i++ {
+		g.stepTimeAttack(netcode.FixedStep, right)
+		if cp := checkpoints[len(touched)]; cp.Active {
+			touched = append(touched, g.TimeAttack.Frames)
+		}
+	}
+	for i := 0; i < 30; i++ {
+		g.stepTimeAttack(netcode.FixedStep, right)
+	}
+	
+	if len(touched) != len(checkpoints) {
+		t.Fatalf("touched %d of %d checkpoints", len(touched), len(checkpoints))
+	}
+	if !reflect.DeepEqual(g.TimeAttack.Splits, touched) {
+		t.Errorf("Splits = %v, want the frames the checkpoints were touched %v", g.TimeAttack.Splits, touched)
+	}
+}
+
+func TestTimeAttackSavesOnlyNewRecords(t *testing.T) {
+	tests := []struct {
+		name      string
+		frames    int
+		newRecord bool
+		wantBest  int
+	}{
+		{name: "slower", frames: 150, newRecord: false, wantBest: 100},
+		{name: "tie", frames: 100, newRecord: false, wantBest: 100},
+		{name: "faster", frames: 60, newRecord: true, wantBest: 60},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := save.NewStore(t.TempDir())
+			data := save.New()
+			data.RecordTimeAttack(1, "Kirby", 100, []int{50})
+			
+			g := timeAttackGame(t, store, data)
+			runTimeAttack(g, netcode.FixedStep, tt.frames, entity.PlayerInput{})
+			g.Victory = true
+			g.updateProgress(0)
+			
+			if g.TimeAttack.NewRecord != tt.newRecord {
+				t.Errorf("NewRecord = %v, want %v", g.TimeAttack.NewRecord, tt.newRecord)
+			}
+			saved, err := store.Load(0)
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if best := saved.TimeAttack[1]; best == nil || best.Frames != tt.wantBest {
+				t.Errorf("saved PB = %+v, want %d frames", best, tt.wantBest)
+			}
+			
+			ghost, err := store.LoadGhost(0, 1)
+			if !tt.newRecord {
+				if !errors.Is(err, save.ErrNoGhost) {
+					t.Errorf("LoadGhost = %v, %v, want no ghost without a new record", ghost, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadGhost: %v", err)
+			}
+			if ghost.Frames != tt.frames || len(ghost.Trace) != tt.frames/GhostSampleEvery {
+				t.Errorf("ghost has %d frames and %d samples, want %d and %d", ghost.Frames, len(ghost.Trace), tt.frames, tt.frames/GhostSampleEvery)
+			}
+		})
+	}
+}
+
+func TestTimeAttackIgnoresGhostOfOtherRecord(t *testing.T) {
+	tests := []struct {
+		name        string
+		ghostFrames int
+		wantGhost   bool
+	}{
+		{name: "matching", ghostFrames: 100, wantGhost: true},
+		{name: "stale", ghostFrames: 120, wantGhost: false},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := save.NewStore(t.TempDir())
+			data := save.New()
+			data.RecordTimeAttack(1, "Kirby", 100, nil)
+			ghost := &save.Ghost{
+				Stage:     1,
+				Character: "Kirby",
+				Frames:    tt.ghostFrames,
+				Every:     GhostSampleEvery,
+				Trace:     make([]save.GhostFrame, tt.ghostFrames/GhostSampleEvery),
+			}
+			if err := store.SaveGhost(0, ghost); err != nil {
+				t.Fatal(err)
+			}
+			
+			g := timeAttackGame(t, store, data)
+			if got := g.Ghost != nil; got != tt.wantGhost {
+				t.Errorf("ghost loaded = %v, want %v", got, tt.wantGhost)
+			}
+		})
+	}
+}
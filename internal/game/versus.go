@@ -0,0 +1,314 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+
+	"github.com/remmakoshino/kirby-inspired-go/internal/entity"
+	"github.com/remmakoshino/kirby-inspired-go/internal/menu"
+	"github.com/remmakoshino/kirby-inspired-go/internal/rng"
+	"github.com/remmakoshino/kirby-inspired-go/internal/stage"
+)
+
+const (
+	VersusRespawnDelay = 2.0 // 撃墜されてから復帰するまでの時間
+	VersusItemInterval = 8.0 // アイテムが降ってくる間隔
+	VersusMaxItems     = 3   // 同時に出現するアイテムの上限
+	VersusDraw         = -1  // 引き分けを表す勝者番号
+)
+
+// VersusMatch は対戦の進行状況を表します
+type VersusMatch struct {
+	Rule          menu.VersusRule
+	Stocks        []int     // 残りストック（ストック制）
+	KOs           []int     // 撃墜した数
+	Falls         []int     // 撃墜された数
+	LastHitBy     []int     // 最後に攻撃を当てたプレイヤー（NoOwner は自滅）
+	Downed        []bool    // 撃墜済みで復帰待ち
+	RespawnTimers []float64 // 復帰までの残り時間
+	TimeLeft      float64   // 残り時間（タイム制）
+	ItemTimer     float64   // 次のアイテムまでの時間
+	Finished      bool
+	Winner        int // 勝者の番号（引き分けは VersusDraw）
+}
+
+// NewVersusMatch は新しい対戦を作成します
+func NewVersusMatch(players int, rule menu.VersusRule, stocks, minutes int) *VersusMatch {
+	m := &VersusMatch{
+		Rule:          rule,
+		Stocks:        make([]int, players),
+		KOs:           make([]int, players),
+		Falls:         make([]int, players),
+		LastHitBy:     make([]int, players),
+		Downed:        make([]bool, players),
+		RespawnTimers: make([]float64, players),
+		TimeLeft:      float64(minutes) * 60,
+		ItemTimer:     VersusItemInterval,
+		Finished:      false,
+		Winner:        VersusDraw,
+	}
+	for i := range m.Stocks {
+		m.Stocks[i] = stocks
+		m.LastHitBy[i] = entity.NoOwner
+	}
+	return m
+}
+
+// InitializeVersus は対戦用のアリーナを初期化します
+func (g *Game) InitializeVersus(characters []string, rule menu.VersusRule, stocks, minutes int) {
+	g.Mode = menu.ModeVersus
+	g.Survival = nil
+	g.BossRush = nil
+	g.TimeAttack = nil
+	g.CurrentStage = 0
+	g.PlayerCharacters = characters
+	g.GameOver = false
+	g.Victory = false
+	
+	g.Stage = stage.CreateArenaStage(WindowWidth, WindowHeight)
+	g.Rooms = nil
+	g.CurrentRoom = 0
+	g.Transition = nil
+	g.DoorHeld = nil
+	g.DeathTimer = 0
+	
+	// 左右の端から向かい合って開始
+	g.Characters = []entity.PlayableCharacter{}
+	for i, id := range characters {
+		if c := entity.NewPlayableCharacter(id, g.versusSpawnPoint(i, len(characters))); c != nil {
+			g.Characters = append(g.Characters, c)
+		}
+	}
+	g.Brains = make([]*entity.HelperBrain, len(g.Characters))
+	g.Camera = NewCamera(pixel.V(g.Stage.Width/2, g.Stage.Height/2))
+	g.LevelUps = nil
+	g.applyProgression()
+	
+	// 敵はいない
+	g.Enemies = nil
+	g.Spawners = nil
+	g.WaddleDees = nil
+	g.WaddleDoos = nil
+	g.Boss = nil
+	g.Projectiles = nil
+	g.Items = nil
+	
+	g.Versus = NewVersusMatch(len(g.Characters), rule, stocks, minutes)
+}
+
+// versusSpawnPoint はプレイヤーの出現位置を返します
+func (g *Game) versusSpawnPoint(player, players int) pixel.Vec {
+	x := g.Stage.Width * float64(player+1) / float64(players+1)
+	return pixel.V(x, 250)
+}
+
+// friendlyFire はプレイヤー同士の攻撃が当たるかどうかを返します
+func (g *Game) friendlyFire() bool {
+	return g.Mode == menu.ModeVersus
+}
+
+// updateVersus は対戦のルール・撃墜・アイテム出現を処理します
+func (g *Game) updateVersus(dt float64) {
+	m := g.Versus
+	if m == nil || m.Finished {
+		return
+	}
+	
+	// プレイヤー同士の近接攻撃
+	g.checkVersusCombat()
+	
+	// 撃墜と復帰
+	g.updateVersusKOs(dt)
+	
+	// アイテムを定期的に降らせる
+	m.ItemTimer -= dt
+	if m.ItemTimer <= 0 {
+		m.ItemTimer = VersusItemInterval
+		if len(g.Items) < VersusMaxItems {
+			g.spawnVersusItem()
+		}
+	}
+	
+	// 決着判定
+	switch m.Rule {
+	case menu.RuleTime:
+		m.TimeLeft -= dt
+		if m.TimeLeft <= 0 {
+			m.TimeLeft = 0
+			g.finishVersus()
+		}
+	case menu.RuleStock:
+		remaining := 0
+		for _, stocks := range m.Stocks {
+			if stocks > 0 {
+				remaining++
+			}
+		}
+		if remaining <= 1 {
+			g.finishVersus()
+		}
+	}
+}
+
+// checkVersusCombat は攻撃中のキャラクターと他のプレイヤーの当たり判定を行います。
+// 被弾側の無敵時間によって同じ攻撃が何度も当たることはありません
+func (g *Game) checkVersusCombat() {
+	for i, attacker := range g.Characters {
+		if attacker.IsDefeated() || !attacker.IsAttacking() {
+			continue
+		}
+		for j, target := range g.Characters {
+			if i == j || target.IsDefeated() {
+				continue
+			}
+			distance := attacker.GetPosition().Sub(target.GetPosition()).Len()
+			if distance < attacker.GetAttackRange()+target.GetRadius() {
+				g.Versus.LastHitBy[j] = i
+				target.TakeDamage(attacker.GetAttackDamage())
+			}
+		}
+	}
+}
+
+// updateVersusKOs は撃墜されたプレイヤーを数え、復帰させます
+func (g *Game) updateVersusKOs(dt float64) {
+	m := g.Versus
+	for i, c := range g.Characters {
+		// 新たに撃墜された
+		if c.IsDefeated() && !m.Downed[i] {
+			m.Downed[i] = true
+			m.Falls[i]++
+			if by := m.LastHitBy[i]; by != entity.NoOwner && by != i {
+				m.KOs[by]++
+			}
+			m.LastHitBy[i] = entity.NoOwner
+			if m.Rule == menu.RuleStock {
+				m.Stocks[i]--
+			}
+			m.RespawnTimers[i] = VersusRespawnDelay
+			continue
+		}
+		
+		if !m.Downed[i] {
+			continue
+		}
+		
+		// ストックが残っていれば復帰
+		m.RespawnTimers[i] -= dt
+		if m.RespawnTimers[i] > 0 || (m.Rule == menu.RuleStock && m.Stocks[i] <= 0) {
+			continue
+		}
+		m.Downed[i] = false
+		c.Revive(c.GetMaxHealth())
+		c.SetPosition(g.versusSpawnPoint(i, len(g.Characters)))
+		c.SetVelocity(pixel.ZV)
+	}
+}
+
+// spawnVersusItem はアリーナの上空からアイテムを1つ降らせます
+func (g *Game) spawnVersusItem() {
+	kind := entity.ItemFood
+	if rng.Float64() < 0.2 {
+		kind = entity.ItemMaxTomato
+	}
+	x := 80 + rng.Float64()*(g.Stage.Width-160)
+	g.Items = append(g.Items, entity.NewItem(pixel.V(x, g.Stage.Height-40), kind))
+}
+
+// finishVersus は試合を終了し、勝者を決めます
+func (g *Game) finishVersus() {
+	m := g.Versus
+	m.Finished = true
+	m.Winner = VersusDraw
+	
+	best := 0
+	for i := range g.Characters {
+		// ストック制は残りストック、タイム制は撃墜数から撃墜された数を引いた値で比べる
+		score := m.KOs[i] - m.Falls[i]
+		if m.Rule == menu.RuleStock {
+			score = m.Stocks[i]
+		}
+		if i == 0 || score > best {
+			best = score
+			m.Winner = i
+		} else if score == best {
+			m.Winner = VersusDraw
+		}
+	}
+	
+	if m.Winner != VersusDraw {
+		g.Characters[m.Winner].Celebrate()
+	}
+}
+
+// drawVersusHUD は残りストック・撃墜数・残り時間を表示します
+func (g *Game) drawVersusHUD() {
+	m := g.Versus
+	
+	for i := range g.Characters {
+		hudX := 10.0
+		if i > 0 {
+			hudX = WindowWidth - 220.0
+		}
+		statusText := text.New(pixel.V(hudX, WindowHeight-130), g.Atlas)
+		statusText.Color = colornames.White
+		if m.Rule == menu.RuleStock {
+			fmt.Fprintf(statusText, "Stock: %d  KO: %d", m.Stocks[i], m.KOs[i])
+		} else {
+			fmt.Fprintf(statusText, "KO: %d  Fall: %d", m.KOs[i], m.Falls[i])
+		}
+		statusText.Draw(g.Window, pixel.IM.Scaled(statusText.Orig, 1.5))
+	}
+	
+	if m.Rule == menu.RuleTime {
+		seconds := int(m.TimeLeft + 0.999)
+		timerText := text.New(pixel.V(WindowWidth/2-40, WindowHeight-40), g.Atlas)
+		timerText.Color = colornames.Yellow
+		if m.TimeLeft < 10 {
+			timerText.Color = colornames.Red
+		}
+		fmt.Fprintf(timerText, "%d:%02d", seconds/60, seconds%60)
+		timerText.Draw(g.Window, pixel.IM.Scaled(timerText.Orig, 3))
+	}
+}
+
+// drawVersusResults は対戦の結果画面を描画します
+func (g *Game) drawVersusResults() {
+	m := g.Versus
+	
+	// 半透明の背景
+	g.IMDraw.Clear()
+	g.IMDraw.Color = color.RGBA{R: 20, G: 10, B: 40, A: 200}
+	g.IMDraw.Push(pixel.V(0, 0))
+	g.IMDraw.Push(pixel.V(WindowWidth, WindowHeight))
+	g.IMDraw.Rectangle(0)
+	g.IMDraw.Draw(g.Window)
+	
+	// 勝者
+	resultText := text.New(pixel.V(WindowWidth/2-180, WindowHeight/2+120), g.Atlas)
+	resultText.Color = colornames.Gold
+	if m.Winner == VersusDraw {
+		fmt.Fprintf(resultText, "DRAW!")
+	} else {
+		fmt.Fprintf(resultText, "%dP %s WINS!", m.Winner+1, g.Characters[m.Winner].Name())
+	}
+	resultText.Draw(g.Window, pixel.IM.Scaled(resultText.Orig, 3))
+	
+	// 各プレイヤーの成績
+	for i, c := range g.Characters {
+		rowText := text.New(pixel.V(WindowWidth/2-180, WindowHeight/2+30-float64(i)*40), g.Atlas)
+		rowText.Color = colornames.White
+		fmt.Fprintf(rowText, "%dP %-12s KO: %d  Fall: %d", i+1, c.Name(), m.KOs[i], m.Falls[i])
+		rowText.Draw(g.Window, pixel.IM.Scaled(rowText.Orig, 2))
+	}
+	
+	// 戻る案内
+	restartText := text.New(pixel.V(WindowWidth/2-140, WindowHeight/2-120), g.Atlas)
+	restartText.Color = colornames.Yellow
+	fmt.Fprintf(restartText, "Press R to Return to Menu")
+	restartText.Draw(g.Window, pixel.IM.Scaled(restartText.Orig, 2))
+}
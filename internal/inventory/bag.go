@@ -0,0 +1,176 @@
+package inventory
+
+import "errors"
+
+// MaxCoins は持てるコインの上限です
+const MaxCoins = 99999
+
+var (
+	ErrNotOwned     = errors.New("inventory: item not owned")
+	ErrNotEquipment = errors.New("inventory: item is not equipment")
+	ErrUnknownItem  = errors.New("inventory: unknown item")
+)
+
+// Bag は持ち物とキャラクターごとの装備、コインと覚えたコピー能力です。マップはセーブデータと同じ形で持ちます
+type Bag struct {
+	Items     map[string]int               // 持っているアイテムの数（装備中のものは数えない）
+	Equipment map[string]map[string]string // キャラクターごとの装備（キャラクターID → 枠 → アイテムID）
+	Coins     int                          // 持っているコイン
+	Abilities []string                     // 店で覚えたコピー能力（覚えた順）
+}
+
+// NewBag は空の持ち物を作成します
+func NewBag() *Bag {
+	return &Bag{
+		Items:     make(map[string]int),
+		Equipment: make(map[string]map[string]string),
+	}
+}
+
+// Clone は持ち物の複製を返します
+func (b *Bag) Clone() *Bag {
+	c := NewBag()
+	for id, n := range b.Items {
+		c.Items[id] = n
+	}
+	for character, slots := range b.Equipment {
+		c.Equipment[character] = make(map[string]string, len(slots))
+		for slot, id := range slots {
+			c.Equipment[character][slot] = id
+		}
+	}
+	c.Coins = b.Coins
+	c.Abilities = append([]string(nil), b.Abilities...)
+	return c
+}
+
+// Count は持っているアイテムの数を返します
+func (b *Bag) Count(id string) int {
+	return b.Items[id]
+}
+
+// Owns はアイテムを持っているか、誰かが装備しているかを返します
+func (b *Bag) Owns(id string) bool {
+	if b.Items[id] > 0 {
+		return true
+	}
+	for _, slots := range b.Equipment {
+		for _, equipped := range slots {
+			if equipped == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Add はアイテムを n 個加え、実際に加えた数を返します（消費アイテムは重ねて持てる数まで）
+func (b *Bag) Add(it Item, n int) int {
+	if n <= 0 {
+		return 0
+	}
+	if !it.IsEquipment() && b.Items[it.ID]+n > it.MaxStack {
+		n = it.MaxStack - b.Items[it.ID]
+		if n <= 0 {
+			return 0
+		}
+	}
+	b.Items[it.ID] += n
+	return n
+}
+
+// Remove はアイテムを1つ減らします。持っていなければ false を返します
+func (b *Bag) Remove(id string) bool {
+	if b.Items[id] <= 0 {
+		return false
+	}
+	b.Items[id]--
+	if b.Items[id] == 0 {
+		delete(b.Items, id)
+	}
+	return true
+}
+
+// Equipped はキャラクターが枠に装備しているアイテムのIDを返します（なければ空文字列）
+func (b *Bag) Equipped(character string, slot Slot) string {
+	return b.Equipment[character][string(slot)]
+}
+
+// Equip は持ち物からアイテムを装備します。同じ枠に装備していたものは持ち物に戻します
+func (b *Bag) Equip(c *Catalog, character, id string) error {
+	it, ok := c.Lookup(id)
+	if !ok {
+		return ErrUnknownItem
+	}
+	if !it.IsEquipment() {
+		return ErrNotEquipment
+	}
+	if !b.Remove(id) {
+		return ErrNotOwned
+	}
+	
+	b.Unequip(character, it.Slot)
+	if b.Equipment[character] == nil {
+		b.Equipment[character] = make(map[string]string)
+	}
+	b.Equipment[character][string(it.Slot)] = id
+	return nil
+}
+
+// Unequip は枠の装備を外して持ち物に戻します
+func (b *Bag) Unequip(character string, slot Slot) {
+	id := b.Equipped(character, slot)
+	if id == "" {
+		return
+	}
+	delete(b.Equipment[character], string(slot))
+	b.Items[id]++
+}
+
+// Effects はキャラクターが装備しているアイテムの効果の合計を返します
+func (b *Bag) Effects(c *Catalog, character string) Effects {
+	var sum Effects
+	for _, slot := range Slots {
+		if it, ok := c.Lookup(b.Equipped(character, slot)); ok {
+			sum = sum.Add(it.Effects)
+		}
+	}
+	return sum
+}
+
+// Earn はコインを n 枚加えます（持てる上限まで）
+func (b *Bag) Earn(n int) {
+	if n <= 0 {
+		return
+	}
+	b.Coins += n
+	if b.Coins > MaxCoins {
+		b.Coins = MaxCoins
+	}
+}
+
+// Spend はコインを n 枚払います。足りなければ払わずに false を返します
+func (b *Bag) Spend(n int) bool {
+	if n < 0 || b.Coins < n {
+		return false
+	}
+	b.Coins -= n
+	return true
+}
+
+// Learned はコピー能力を覚えているかを返します
+func (b *Bag) Learned(ability string) bool {
+	for _, a := range b.Abilities {
+		if a == ability {
+			return true
+		}
+	}
+	return false
+}
+
+// Learn はコピー能力を覚えます。覚えていれば何もしません
+func (b *Bag) Learn(ability string) {
+	if !b.Learned(ability) {
+		b.Abilities = append(b.Abilities, ability)
+	}
+}
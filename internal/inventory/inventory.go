@@ -0,0 +1,192 @@
+// Package inventory は装備品と消費アイテムの定義と、持ち物と装備・コインの管理、店の売り買いを扱います。
+// アイテムの定義は items.json、店の品揃えは shop.json に書かれていて、持ち物と装備はセーブデータに保存されます
+package inventory
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+)
+
+//go:embed items.json
+var defaultData []byte
+
+// Kind はアイテムの種類です
+type Kind string
+
+const (
+	KindEquipment  Kind = "equipment"  // 装備品（枠に1つだけ装備できる）
+	KindConsumable Kind = "consumable" // 消費アイテム（重ねて持ち、ポーズ画面で使う）
+)
+
+// Slot は装備の枠です
+type Slot string
+
+const (
+	SlotHat       Slot = "hat"       // 帽子（カービィの頭に描かれる）
+	SlotAccessory Slot = "accessory" // アクセサリー
+	SlotCharm     Slot = "charm"     // 武器のお守り
+)
+
+// Slots は装備の枠を表示順に並べたものです
+var Slots = []Slot{SlotHat, SlotAccessory, SlotCharm}
+
+// Effects は装備品の効果です。複数の装備の効果は足し合わせます
+type Effects struct {
+	MaxHealth   int     `json:"maxHealth"`   // 最大HPの増加
+	Attack      int     `json:"attack"`      // 攻撃力の増加
+	Defense     int     `json:"defense"`     // 防御力の増加
+	Speed       float64 `json:"speed"`       // 移動の速さの倍率の増加
+	Cooldown    float64 `json:"cooldown"`    // 能力のクールダウンが早く戻る割合（0.3 で 30% 速い）
+	JumpHeight  float64 `json:"jumpHeight"`  // ジャンプの高さの増加の割合（1 で2倍）
+	GuardChance float64 `json:"guardChance"` // 攻撃を受けた時に自動でガードする確率
+}
+
+// Add は2つの効果を足し合わせます（確率は1を超えない）
+func (e Effects) Add(o Effects) Effects {
+	sum := Effects{
+		MaxHealth:   e.MaxHealth + o.MaxHealth,
+		Attack:      e.Attack + o.Attack,
+		Defense:     e.Defense + o.Defense,
+		Speed:       e.Speed + o.Speed,
+		Cooldown:    e.Cooldown + o.Cooldown,
+		JumpHeight:  e.JumpHeight + o.JumpHeight,
+		GuardChance: e.GuardChance + o.GuardChance,
+	}
+	if sum.GuardChance > 1 {
+		sum.GuardChance = 1
+	}
+	return sum
+}
+
+// Item はアイテム1種類の定義です
+type Item struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Kind        Kind     `json:"kind"`
+	Color       [3]uint8 `json:"color"`
+	
+	// 装備品
+	Slot    Slot    `json:"slot"`
+	Effects Effects `json:"effects"`
+	Hat     string  `json:"hat"`  // 帽子の形（"cap", "wizard", "crown"）
+	Boss    string  `json:"boss"` // このボスを初めて倒すともらえる（ボスのID）
+	
+	// 消費アイテム
+	Heal     int     `json:"heal"`     // 回復量
+	FullHeal bool    `json:"fullHeal"` // 全回復
+	Candy    float64 `json:"candy"`    // 無敵の秒数
+	MaxStack int     `json:"maxStack"` // 重ねて持てる最大数
+	
+	// 倒した敵が落とす宝の抽選の重み（0なら落とさない）
+	DropWeight float64 `json:"dropWeight"`
+}
+
+// IsEquipment は装備品かを返します
+func (it Item) IsEquipment() bool {
+	return it.Kind == KindEquipment
+}
+
+// Catalog はアイテムの定義の一覧です
+type Catalog struct {
+	Items []Item `json:"items"`
+}
+
+// Parse はアイテムの定義を読み込み、内容を確かめます
+func Parse(data []byte) (*Catalog, error) {
+	var c Catalog
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	
+	seen := make(map[string]bool)
+	for _, it := range c.Items {
+		if it.ID == "" {
+			return nil, fmt.Errorf("inventory: item %q has no id", it.Name)
+		}
+		if seen[it.ID] {
+			return nil, fmt.Errorf("inventory: item %q appears twice", it.ID)
+		}
+		seen[it.ID] = true
+		
+		switch it.Kind {
+		case KindEquipment:
+			if !validSlot(it.Slot) {
+				return nil, fmt.Errorf("inventory: item %q has unknown slot %q", it.ID, it.Slot)
+			}
+		case KindConsumable:
+			if it.MaxStack <= 0 {
+				return nil, fmt.Errorf("inventory: item %q has no maxStack", it.ID)
+			}
+		default:
+			return nil, fmt.Errorf("inventory: item %q has unknown kind %q", it.ID, it.Kind)
+		}
+	}
+	return &c, nil
+}
+
+// validSlot は装備の枠が正しいかを返します
+func validSlot(slot Slot) bool {
+	for _, s := range Slots {
+		if s == slot {
+			return true
+		}
+	}
+	return false
+}
+
+// Default は組み込みのアイテムの定義を返します
+func Default() *Catalog {
+	c, err := Parse(defaultData)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Lookup はIDからアイテムの定義を探します
+func (c *Catalog) Lookup(id string) (Item, bool) {
+	for _, it := range c.Items {
+		if it.ID == id {
+			return it, true
+		}
+	}
+	return Item{}, false
+}
+
+// BossReward はボスを初めて倒した時にもらえるアイテムを返します（なければ false）
+func (c *Catalog) BossReward(boss string) (Item, bool) {
+	for _, it := range c.Items {
+		if it.Boss != "" && it.Boss == boss {
+			return it, true
+		}
+	}
+	return Item{}, false
+}
+
+// PickDrop は 0〜1 の乱数 r で、倒した敵が落とす宝を重みに応じて選びます
+func (c *Catalog) PickDrop(r float64) (Item, bool) {
+	total := 0.0
+	for _, it := range c.Items {
+		total += it.DropWeight
+	}
+	if total <= 0 {
+		return Item{}, false
+	}
+	
+	// 誤差で最後まで残った場合は最後の候補にする
+	r *= total
+	var last Item
+	for _, it := range c.Items {
+		if it.DropWeight <= 0 {
+			continue
+		}
+		if r < it.DropWeight {
+			return it, true
+		}
+		r -= it.DropWeight
+		last = it
+	}
+	return last, true
+}
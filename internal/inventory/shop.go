@@ -0,0 +1,142 @@
+package inventory
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+//go:embed shop.json
+var defaultShopData []byte
+
+var (
+	ErrNotEnoughCoins = errors.New("inventory: not enough coins")
+	ErrBagFull        = errors.New("inventory: bag is full")
+	ErrAlreadyLearned = errors.New("inventory: ability already learned")
+	ErrNotForSale     = errors.New("inventory: the shop doesn't buy this")
+)
+
+// Offer は店に並ぶ品物1つです。アイテムかコピー能力のどちらかを売ります
+type Offer struct {
+	Item        string `json:"item"`        // 売るアイテムのID
+	Ability     string `json:"ability"`     // 覚えられるコピー能力の種類（ポーズ画面からいつでも使える）
+	Name        string `json:"name"`        // コピー能力の表示名（アイテムは定義の名前を使う）
+	Description string `json:"description"` // コピー能力の説明
+	Price       int    `json:"price"`       // 基本の値段
+	Stages      int    `json:"stages"`      // 店に並ぶのに必要なクリアしたステージの数
+}
+
+// IsAbility はコピー能力の品物かを返します
+func (o Offer) IsAbility() bool {
+	return o.Ability != ""
+}
+
+// Shop は店の品揃えと値段の決まりです
+type Shop struct {
+	PriceGrowth float64 `json:"priceGrowth"` // クリアしたステージ1つごとに値段が上がる割合
+	SellRate    float64 `json:"sellRate"`    // 売る時は今の買う値段にこれを掛けた値段
+	Offers      []Offer `json:"offers"`
+}
+
+// ParseShop は店の品揃えを読み込み、アイテムが定義にあるかを確かめます
+func ParseShop(data []byte, c *Catalog) (*Shop, error) {
+	var s Shop
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	
+	for _, o := range s.Offers {
+		if (o.Item == "") == (o.Ability == "") {
+			return nil, fmt.Errorf("inventory: offer %q must sell exactly one item or ability", o.Item+o.Ability)
+		}
+		if o.Price <= 0 {
+			return nil, fmt.Errorf("inventory: offer %q has no price", o.Item+o.Ability)
+		}
+		if o.IsAbility() && o.Name == "" {
+			return nil, fmt.Errorf("inventory: ability offer %q has no name", o.Ability)
+		}
+		if !o.IsAbility() {
+			if _, ok := c.Lookup(o.Item); !ok {
+				return nil, fmt.Errorf("inventory: offer sells unknown item %q", o.Item)
+			}
+		}
+	}
+	return &s, nil
+}
+
+// DefaultShop は組み込みの店の品揃えを返します
+func DefaultShop(c *Catalog) *Shop {
+	s, err := ParseShop(defaultShopData, c)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Available はクリアしたステージの数が cleared の時に店に並ぶ品物を返します
+func (s *Shop) Available(cleared int) []Offer {
+	var offers []Offer
+	for _, o := range s.Offers {
+		if o.Stages <= cleared {
+			offers = append(offers, o)
+		}
+	}
+	return offers
+}
+
+// Price はクリアしたステージの数が cleared の時の品物の値段を返します
+func (s *Shop) Price(o Offer, cleared int) int {
+	return int(math.Round(float64(o.Price) * (1 + s.PriceGrowth*float64(cleared))))
+}
+
+// SellPrice はアイテムを売った時にもらえるコインを返します。店で扱っていないアイテムは売れません
+func (s *Shop) SellPrice(id string, cleared int) (int, bool) {
+	for _, o := range s.Offers {
+		if o.Item == id {
+			return int(float64(s.Price(o, cleared)) * s.SellRate), true
+		}
+	}
+	return 0, false
+}
+
+// Buy はコインを払って品物を持ち物に加えるか、コピー能力を覚えます。
+// 買えない時は何も変えずにエラーを返します
+func (s *Shop) Buy(b *Bag, c *Catalog, o Offer, cleared int) error {
+	price := s.Price(o, cleared)
+	if b.Coins < price {
+		return ErrNotEnoughCoins
+	}
+	if o.IsAbility() {
+		if b.Learned(o.Ability) {
+			return ErrAlreadyLearned
+		}
+		b.Spend(price)
+		b.Learn(o.Ability)
+		return nil
+	}
+	
+	it, ok := c.Lookup(o.Item)
+	if !ok {
+		return ErrUnknownItem
+	}
+	if b.Add(it, 1) == 0 {
+		return ErrBagFull
+	}
+	b.Spend(price)
+	return nil
+}
+
+// Sell は持ち物のアイテムを1つ売り、もらえたコインの数を返します（装備中のものは売れません）
+func (s *Shop) Sell(b *Bag, id string, cleared int) (int, error) {
+	price, ok := s.SellPrice(id, cleared)
+	if !ok {
+		return 0, ErrNotForSale
+	}
+	if !b.Remove(id) {
+		return 0, ErrNotOwned
+	}
+	b.Earn(price)
+	return price, nil
+}
@@ -3,6 +3,7 @@ package menu
 import (
 	"fmt"
 	"image/color"
+	"math"
 
 	"github.com/faiface/pixel"
 	"github.com/faiface/pixel/imdraw"
@@ -10,6 +11,10 @@ import (
 	"github.com/faiface/pixel/text"
 	"golang.org/x/image/colornames"
 	"golang.org/x/image/font/basicfont"
+	
+	"github.com/remmakoshino/kirby-inspired-go/internal/entity"
+	"github.com/remmakoshino/kirby-inspired-go/internal/save"
+	"github.com/remmakoshino/kirby-inspired-go/internal/worldmap"
 )
 
 // GameState はゲームの状態を表します
@@ -18,47 +23,136 @@ type GameState int
 const (
 	StateTitleScreen GameState = iota
 	StateCharacterSelect
-	StateStageSelect
+	StateWorldMap
 	StatePlaying
 	StateGameOver
 	StateStageComplete
+	StateModeSelect
+	StateVersusRules
+	StateSlotSelect
 )
 
-// PlayerCharacter はプレイ可能なキャラクター
-type PlayerCharacter int
+// titleEntries はタイトル画面の項目です
+var titleEntries = []string{"CONTINUE", "NEW GAME", "DELETE", "EXIT"}
+
+// タイトル画面の項目の番号
+const (
+	titleContinue = iota
+	titleNewGame
+	titleDelete
+	titleExit
+)
+
+// SlotAction はセーブスロットに対する操作です
+type SlotAction int
+
+const (
+	SlotContinue SlotAction = iota // 続きから遊ぶ
+	SlotNewGame                    // はじめから遊ぶ（スロットを上書き）
+	SlotDelete                     // スロットを削除
+)
+
+
+// GameMode はゲームモードを表します
+type GameMode int
 
 const (
-	CharacterKirby PlayerCharacter = iota
-	CharacterMetaKnight
+	ModeStory    GameMode = iota // ストーリー（1人または2人協力）
+	ModeVersus                   // 2人対戦
+	ModeSurvival                 // 次々に来る敵の波を耐え抜く（1人または2人協力）
+	ModeBossRush                 // すべてのボスと休憩をはさんで連戦する（1人または2人協力）
+	ModeTimeAttack               // クリアしたステージのタイムと自己ベストのゴーストを競う（1人）
 )
 
+// modeEntry はモード選択画面の項目です
+type modeEntry struct {
+	Mode        GameMode
+	Label       string
+	Description string
+}
+
+// modeEntries はモード選択画面に並べる項目の一覧です
+var modeEntries = []modeEntry{
+	{Mode: ModeStory, Label: "STORY", Description: "Adventure alone or with a friend"},
+	{Mode: ModeVersus, Label: "VERSUS", Description: "Two players battle in the arena"},
+	{Mode: ModeSurvival, Label: "SURVIVAL", Description: "Endless waves - how far can you go?"},
+	{Mode: ModeBossRush, Label: "THE ARENA", Description: "Fight every boss back-to-back"},
+	{Mode: ModeTimeAttack, Label: "TIME ATTACK", Description: "Race the clock and your best ghost"},
+}
+
+// VersusRule は対戦のルールを表します
+type VersusRule int
+
+const (
+	RuleStock VersusRule = iota // ストック制（残機がなくなったら負け）
+	RuleTime                    // タイム制（時間内の撃墜数で勝負）
+)
+
+const (
+	MaxVersusStocks  = 5
+	MaxVersusMinutes = 5
+)
+
+// PlayerCharacter はプレイ可能なキャラクター（entity.Characters() の登録順のインデックス）。
+// 0 は最初に登録されたキャラクターです
+type PlayerCharacter int
+
 // MenuManager はメニュー全体を管理します
 type MenuManager struct {
 	State              GameState
-	SelectedCharacter  PlayerCharacter
+	SelectedCharacter  PlayerCharacter   // 1Pのキャラクター
+	SelectedCharacters []PlayerCharacter // プレイヤー順のキャラクター（協力プレイ時は2人分）
+	CoopEnabled        bool              // 2人協力プレイ
+	SelectedMode       GameMode
 	SelectedStage      int
+	WorldMap           *worldmap.Map
+	
+	// 対戦ルール
+	VersusRule    VersusRule
+	VersusStocks  int
+	VersusMinutes int
+	
+	// セーブスロット（保存先が使えない場合は SaveSlots が nil）
+	SaveSlots     []save.SlotInfo
+	Save          *save.Data // 遊んでいるスロットのデータ（ステージの解放と記録を表示する）
+	SlotAction    SlotAction
+	SelectedSlot  int
+	SlotConfirmed bool   // スロットの操作が決まった（ゲーム側が処理して false に戻す）
+	Notice        string // タイトル・スロット画面に出すお知らせ（保存の失敗など）
 	Window             *pixelgl.Window
 	Atlas              *text.Atlas
 	IMDraw             *imdraw.IMDraw
 	
 	// メニュー選択
 	titleSelection     int
+	modeSelection      int
 	characterSelection int
-	stageSelection     int
+	selectingPlayer    int // キャラクターを選んでいるプレイヤー（0: 1P, 1: 2P）
+	mapLevel           int // ワールドマップで選んでいるレベル
+	mapNode            int // ワールドマップで選んでいる扉
+	slotSelection      int
+	confirming         bool // 上書き・削除の確認中
+	
+	// キャラクター選択画面のプレビュー（登録IDごとに一度だけ作る）
+	previews map[string]entity.PlayableCharacter
 }
 
 // NewMenuManager は新しいメニューマネージャーを作成します
 func NewMenuManager(win *pixelgl.Window) *MenuManager {
 	return &MenuManager{
 		State:              StateTitleScreen,
-		SelectedCharacter:  CharacterKirby,
+		SelectedCharacter:  0,
+		SelectedMode:       ModeStory,
 		SelectedStage:      1,
+		VersusRule:         RuleStock,
+		VersusStocks:       3,
+		VersusMinutes:      2,
 		Window:             win,
 		Atlas:              text.NewAtlas(basicfont.Face7x13, text.ASCII),
 		IMDraw:             imdraw.New(nil),
 		titleSelection:     0,
 		characterSelection: 0,
-		stageSelection:     0,
+		WorldMap:           worldmap.Default(),
 	}
 }
 
@@ -67,59 +161,193 @@ func (m *MenuManager) Update(dt float64) {
 	switch m.State {
 	case StateTitleScreen:
 		m.updateTitleScreen()
+	case StateModeSelect:
+		m.updateModeSelect()
 	case StateCharacterSelect:
 		m.updateCharacterSelect()
-	case StateStageSelect:
-		m.updateStageSelect()
+	case StateWorldMap:
+		m.updateWorldMap()
+	case StateVersusRules:
+		m.updateVersusRules()
+	case StateSlotSelect:
+		m.updateSlotSelect()
+	}
+}
+
+// SetSaveSlots はスロットの状態を設定します。
+// 続きから遊べるスロットがなければタイトルのカーソルを NEW GAME に合わせます
+func (m *MenuManager) SetSaveSlots(slots []save.SlotInfo) {
+	m.SaveSlots = slots
+	if m.titleSelection == titleContinue && !m.titleEnabled(titleContinue) {
+		m.titleSelection = titleNewGame
 	}
 }
 
+// ApplySettings はセーブデータの設定をメニューに反映します
+func (m *MenuManager) ApplySettings(s save.Settings) {
+	m.VersusRule = VersusRule(s.VersusRule)
+	if m.VersusRule != RuleStock && m.VersusRule != RuleTime {
+		m.VersusRule = RuleStock
+	}
+	m.VersusStocks = clampInt(s.VersusStocks, 1, MaxVersusStocks)
+	m.VersusMinutes = clampInt(s.VersusMinutes, 1, MaxVersusMinutes)
+}
+
+// Settings はセーブデータに保存するメニューの設定を返します
+func (m *MenuManager) Settings() save.Settings {
+	return save.Settings{
+		VersusRule:    int(m.VersusRule),
+		VersusStocks:  m.VersusStocks,
+		VersusMinutes: m.VersusMinutes,
+	}
+}
+
+// titleEnabled はタイトル画面の項目を選べるかを返します
+func (m *MenuManager) titleEnabled(entry int) bool {
+	switch entry {
+	case titleContinue:
+		for _, info := range m.SaveSlots {
+			if info.Data != nil {
+				return true
+			}
+		}
+		return false
+	case titleDelete:
+		for _, info := range m.SaveSlots {
+			if info.Data != nil || info.Err != nil {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
 // updateTitleScreen はタイトル画面の更新処理
 func (m *MenuManager) updateTitleScreen() {
-	// 上下キーで選択
+	// 上下キーで選択（選べない項目は飛ばす）
+	count := len(titleEntries)
+	step := 0
 	if m.Window.JustPressed(pixelgl.KeyUp) || m.Window.JustPressed(pixelgl.KeyW) {
-		m.titleSelection--
-		if m.titleSelection < 0 {
-			m.titleSelection = 1 // 0: Start, 1: Exit
-		}
+		step = -1
 	}
 	if m.Window.JustPressed(pixelgl.KeyDown) || m.Window.JustPressed(pixelgl.KeyS) {
-		m.titleSelection++
-		if m.titleSelection > 1 {
-			m.titleSelection = 0
+		step = 1
+	}
+	if step != 0 {
+		for i := 0; i < count; i++ {
+			m.titleSelection = (m.titleSelection + step + count) % count
+			if m.titleEnabled(m.titleSelection) {
+				break
+			}
 		}
 	}
 	
 	// Enterで決定
 	if m.Window.JustPressed(pixelgl.KeyEnter) || m.Window.JustPressed(pixelgl.KeySpace) {
-		if m.titleSelection == 0 {
-			m.State = StateCharacterSelect
-		} else {
+		if !m.titleEnabled(m.titleSelection) {
+			return
+		}
+		m.Notice = ""
+		switch m.titleSelection {
+		case titleContinue:
+			m.openSlotSelect(SlotContinue)
+		case titleNewGame:
+			// 保存先が使えなければセーブせずに遊ぶ
+			if m.SaveSlots == nil {
+				m.State = StateModeSelect
+				return
+			}
+			m.openSlotSelect(SlotNewGame)
+		case titleDelete:
+			m.openSlotSelect(SlotDelete)
+		case titleExit:
 			m.Window.SetClosed(true)
 		}
 	}
 }
 
-// updateCharacterSelect はキャラクター選択画面の更新処理
-func (m *MenuManager) updateCharacterSelect() {
-	// 左右キーで選択
-	if m.Window.JustPressed(pixelgl.KeyLeft) || m.Window.JustPressed(pixelgl.KeyA) {
-		m.characterSelection--
-		if m.characterSelection < 0 {
-			m.characterSelection = 1
+// openSlotSelect はスロット選択画面を開きます
+func (m *MenuManager) openSlotSelect(action SlotAction) {
+	m.SlotAction = action
+	m.confirming = false
+	m.State = StateSlotSelect
+}
+
+// updateSlotSelect はスロット選択画面の更新処理
+func (m *MenuManager) updateSlotSelect() {
+	// 上下キーで選択
+	count := len(m.SaveSlots)
+	if count == 0 {
+		m.State = StateTitleScreen
+		return
+	}
+	if m.Window.JustPressed(pixelgl.KeyUp) || m.Window.JustPressed(pixelgl.KeyW) {
+		m.slotSelection = (m.slotSelection - 1 + count) % count
+		m.confirming = false
+	}
+	if m.Window.JustPressed(pixelgl.KeyDown) || m.Window.JustPressed(pixelgl.KeyS) {
+		m.slotSelection = (m.slotSelection + 1) % count
+		m.confirming = false
+	}
+	m.slotSelection = clampInt(m.slotSelection, 0, count-1)
+	
+	// Enterで決定（上書きと削除はもう一度押して確定）
+	if m.Window.JustPressed(pixelgl.KeyEnter) || m.Window.JustPressed(pixelgl.KeySpace) {
+		info := m.SaveSlots[m.slotSelection]
+		used := info.Data != nil || info.Err != nil
+		switch m.SlotAction {
+		case SlotContinue:
+			if info.Data == nil {
+				return
+			}
+		case SlotNewGame:
+			if used && !m.confirming {
+				m.confirming = true
+				return
+			}
+		case SlotDelete:
+			if !used {
+				return
+			}
+			if !m.confirming {
+				m.confirming = true
+				return
+			}
 		}
+		m.confirming = false
+		m.SelectedSlot = m.slotSelection
+		m.SlotConfirmed = true
 	}
-	if m.Window.JustPressed(pixelgl.KeyRight) || m.Window.JustPressed(pixelgl.KeyD) {
-		m.characterSelection++
-		if m.characterSelection > 1 {
-			m.characterSelection = 0
+	
+	// ESCで戻る（確認中は確認を取り消す）
+	if m.Window.JustPressed(pixelgl.KeyEscape) {
+		if m.confirming {
+			m.confirming = false
+			return
 		}
+		m.State = StateTitleScreen
+	}
+}
+
+// updateModeSelect はモード選択画面の更新処理
+func (m *MenuManager) updateModeSelect() {
+	// 上下キーで選択
+	count := len(modeEntries)
+	if m.Window.JustPressed(pixelgl.KeyUp) || m.Window.JustPressed(pixelgl.KeyW) {
+		m.modeSelection = (m.modeSelection - 1 + count) % count
+	}
+	if m.Window.JustPressed(pixelgl.KeyDown) || m.Window.JustPressed(pixelgl.KeyS) {
+		m.modeSelection = (m.modeSelection + 1) % count
 	}
 	
 	// Enterで決定
 	if m.Window.JustPressed(pixelgl.KeyEnter) || m.Window.JustPressed(pixelgl.KeySpace) {
-		m.SelectedCharacter = PlayerCharacter(m.characterSelection)
-		m.State = StateStageSelect
+		m.SelectedMode = modeEntries[m.modeSelection].Mode
+		// 対戦は必ず2人で選ぶ（タイムアタックは1人、ほかはTabで協力プレイに切り替える）
+		m.CoopEnabled = m.SelectedMode == ModeVersus
+		m.selectingPlayer = 0
+		m.State = StateCharacterSelect
 	}
 	
 	// ESCで戻る
@@ -128,25 +356,36 @@ func (m *MenuManager) updateCharacterSelect() {
 	}
 }
 
-// updateStageSelect はステージ選択画面の更新処理
-func (m *MenuManager) updateStageSelect() {
-	// 左右キーで選択
-	if m.Window.JustPressed(pixelgl.KeyLeft) || m.Window.JustPressed(pixelgl.KeyA) {
-		m.stageSelection--
-		if m.stageSelection < 0 {
-			m.stageSelection = 1 // ステージ1, 2
+// updateVersusRules は対戦ルール設定画面の更新処理
+func (m *MenuManager) updateVersusRules() {
+	// 左右キーでルールを切り替え
+	if m.Window.JustPressed(pixelgl.KeyLeft) || m.Window.JustPressed(pixelgl.KeyA) ||
+		m.Window.JustPressed(pixelgl.KeyRight) || m.Window.JustPressed(pixelgl.KeyD) {
+		if m.VersusRule == RuleStock {
+			m.VersusRule = RuleTime
+		} else {
+			m.VersusRule = RuleStock
 		}
 	}
-	if m.Window.JustPressed(pixelgl.KeyRight) || m.Window.JustPressed(pixelgl.KeyD) {
-		m.stageSelection++
-		if m.stageSelection > 1 {
-			m.stageSelection = 0
+	
+	// 上下キーでストック数・制限時間を調整
+	delta := 0
+	if m.Window.JustPressed(pixelgl.KeyUp) || m.Window.JustPressed(pixelgl.KeyW) {
+		delta = 1
+	}
+	if m.Window.JustPressed(pixelgl.KeyDown) || m.Window.JustPressed(pixelgl.KeyS) {
+		delta = -1
+	}
+	if delta != 0 {
+		if m.VersusRule == RuleStock {
+			m.VersusStocks = clampInt(m.VersusStocks+delta, 1, MaxVersusStocks)
+		} else {
+			m.VersusMinutes = clampInt(m.VersusMinutes+delta, 1, MaxVersusMinutes)
 		}
 	}
 	
-	// Enterで決定
+	// Enterで試合開始
 	if m.Window.JustPressed(pixelgl.KeyEnter) || m.Window.JustPressed(pixelgl.KeySpace) {
-		m.SelectedStage = m.stageSelection + 1
 		m.State = StatePlaying
 	}
 	
@@ -156,6 +395,70 @@ func (m *MenuManager) updateStageSelect() {
 	}
 }
 
+// clampInt は整数を範囲内に収めます
+func clampInt(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
+// updateCharacterSelect はキャラクター選択画面の更新処理
+func (m *MenuManager) updateCharacterSelect() {
+	// 左右キーで選択（登録されているキャラクター数で折り返す）
+	count := len(entity.Characters())
+	if m.Window.JustPressed(pixelgl.KeyLeft) || m.Window.JustPressed(pixelgl.KeyA) {
+		m.characterSelection = (m.characterSelection - 1 + count) % count
+	}
+	if m.Window.JustPressed(pixelgl.KeyRight) || m.Window.JustPressed(pixelgl.KeyD) {
+		m.characterSelection = (m.characterSelection + 1) % count
+	}
+	
+	// Tabで2人協力プレイを切り替え（対戦とタイムアタック以外の1Pの選択中のみ）
+	if m.SelectedMode != ModeVersus && m.SelectedMode != ModeTimeAttack && m.selectingPlayer == 0 && m.Window.JustPressed(pixelgl.KeyTab) {
+		m.CoopEnabled = !m.CoopEnabled
+	}
+	
+	// Enterで決定
+	if m.Window.JustPressed(pixelgl.KeyEnter) || m.Window.JustPressed(pixelgl.KeySpace) {
+		choice := PlayerCharacter(m.characterSelection)
+		if m.selectingPlayer == 0 {
+			m.SelectedCharacter = choice
+			m.SelectedCharacters = []PlayerCharacter{choice}
+		} else {
+			m.SelectedCharacters = append(m.SelectedCharacters[:1], choice)
+		}
+		
+		// 協力プレイでは続けて2Pが選ぶ
+		if m.CoopEnabled && m.selectingPlayer == 0 {
+			m.selectingPlayer = 1
+			m.characterSelection = (m.characterSelection + 1) % count
+			return
+		}
+		m.selectingPlayer = 0
+		switch m.SelectedMode {
+		case ModeVersus:
+			m.State = StateVersusRules
+		case ModeSurvival, ModeBossRush:
+			m.State = StatePlaying
+		default:
+			m.OpenWorldMap()
+		}
+	}
+	
+	// ESCで戻る（2Pの選択中は1Pの選択に戻る）
+	if m.Window.JustPressed(pixelgl.KeyEscape) {
+		if m.selectingPlayer > 0 {
+			m.selectingPlayer = 0
+			return
+		}
+		m.State = StateModeSelect
+	}
+}
+
 // Draw はメニューを描画します
 func (m *MenuManager) Draw() {
 	m.Window.Clear(colornames.Black)
@@ -164,10 +467,12 @@ func (m *MenuManager) Draw() {
 	switch m.State {
 	case StateTitleScreen:
 		m.drawTitleScreen()
+	case StateModeSelect, StateVersusRules, StateSlotSelect:
+		m.drawPanelBackground()
 	case StateCharacterSelect:
 		m.drawCharacterSelect()
-	case StateStageSelect:
-		m.drawStageSelect()
+	case StateWorldMap:
+		m.drawWorldMap()
 	}
 	
 	// IMDrawを最後に描画（背景と図形）
@@ -177,10 +482,16 @@ func (m *MenuManager) Draw() {
 	switch m.State {
 	case StateTitleScreen:
 		m.drawTitleScreenText()
+	case StateModeSelect:
+		m.drawModeSelectText()
 	case StateCharacterSelect:
 		m.drawCharacterSelectText()
-	case StateStageSelect:
-		m.drawStageSelectText()
+	case StateWorldMap:
+		m.drawWorldMapText()
+	case StateVersusRules:
+		m.drawVersusRulesText()
+	case StateSlotSelect:
+		m.drawSlotSelectText()
 	}
 }
 
@@ -196,21 +507,16 @@ func (m *MenuManager) drawTitleScreen() {
 	m.IMDraw.Rectangle(0)
 	
 	// 選択カーソル
-	menuY := height / 2
-	if m.titleSelection == 0 {
-		m.IMDraw.Color = colornames.Yellow
-		m.IMDraw.Push(pixel.V(width/2-100, menuY+5))
-		m.IMDraw.Push(pixel.V(width/2-90, menuY+5))
-		m.IMDraw.Line(3)
-	}
-	
-	exitY := menuY - 60
-	if m.titleSelection == 1 {
-		m.IMDraw.Color = colornames.Yellow
-		m.IMDraw.Push(pixel.V(width/2-100, exitY+5))
-		m.IMDraw.Push(pixel.V(width/2-90, exitY+5))
-		m.IMDraw.Line(3)
-	}
+	cursorY := titleEntryY(height, m.titleSelection)
+	m.IMDraw.Color = colornames.Yellow
+	m.IMDraw.Push(pixel.V(width/2-100, cursorY+5))
+	m.IMDraw.Push(pixel.V(width/2-90, cursorY+5))
+	m.IMDraw.Line(3)
+}
+
+// titleEntryY はタイトル画面の項目のY座標を返します
+func titleEntryY(height float64, index int) float64 {
+	return height/2 + 20 - float64(index)*50
 }
 
 // drawTitleScreenText はタイトル画面のテキストを描画
@@ -236,29 +542,27 @@ func (m *MenuManager) drawTitleScreenText() {
 	fmt.Fprintf(welcomeText, "Press ENTER to start your adventure!")
 	welcomeText.Draw(m.Window, pixel.IM.Scaled(welcomeText.Orig, 1.8))
 	
-	// メニュー項目
-	menuY := height / 2
-	
-	// Start Game
-	startColor := colornames.White
-	if m.titleSelection == 0 {
-		startColor = colornames.Yellow
+	// メニュー項目（選べない項目は暗く表示）
+	for i, label := range titleEntries {
+		entryColor := colornames.White
+		if !m.titleEnabled(i) {
+			entryColor = colornames.Gray
+		} else if m.titleSelection == i {
+			entryColor = colornames.Yellow
+		}
+		entryText := text.New(pixel.V(width/2-70, titleEntryY(height, i)), m.Atlas)
+		entryText.Color = entryColor
+		fmt.Fprintf(entryText, "%s", label)
+		entryText.Draw(m.Window, pixel.IM.Scaled(entryText.Orig, 3))
 	}
-	startText := text.New(pixel.V(width/2-70, menuY), m.Atlas)
-	startText.Color = startColor
-	fmt.Fprintf(startText, "START GAME")
-	startText.Draw(m.Window, pixel.IM.Scaled(startText.Orig, 3))
 	
-	// Exit
-	exitY := menuY - 60
-	exitColor := colornames.White
-	if m.titleSelection == 1 {
-		exitColor = colornames.Yellow
+	// お知らせ
+	if m.Notice != "" {
+		noticeText := text.New(pixel.V(width/2-200, 100), m.Atlas)
+		noticeText.Color = colornames.Orangered
+		fmt.Fprintf(noticeText, "%s", m.Notice)
+		noticeText.Draw(m.Window, pixel.IM.Scaled(noticeText.Orig, 1.5))
 	}
-	exitText := text.New(pixel.V(width/2-70, exitY), m.Atlas)
-	exitText.Color = exitColor
-	fmt.Fprintf(exitText, "EXIT")
-	exitText.Draw(m.Window, pixel.IM.Scaled(exitText.Orig, 3))
 	
 	// 操作説明
 	instructionText := text.New(pixel.V(width/2-150, 50), m.Atlas)
@@ -267,245 +571,387 @@ func (m *MenuManager) drawTitleScreenText() {
 	instructionText.Draw(m.Window, pixel.IM.Scaled(instructionText.Orig, 1.5))
 }
 
-// drawCharacterSelect はキャラクター選択画面の図形を描画
-func (m *MenuManager) drawCharacterSelect() {
+// drawPanelBackground はモード選択・ルール設定画面の背景を描画
+func (m *MenuManager) drawPanelBackground() {
 	width := m.Window.Bounds().W()
 	height := m.Window.Bounds().H()
 	
-	// 背景
-	m.IMDraw.Color = color.RGBA{R: 50, G: 50, B: 80, A: 255}
+	m.IMDraw.Color = color.RGBA{R: 40, G: 40, B: 70, A: 255}
 	m.IMDraw.Push(pixel.V(0, 0))
 	m.IMDraw.Push(pixel.V(width, height))
 	m.IMDraw.Rectangle(0)
+}
+
+// drawModeSelectText はモード選択画面のテキストを描画
+func (m *MenuManager) drawModeSelectText() {
+	width := m.Window.Bounds().W()
+	height := m.Window.Bounds().H()
 	
-	// カービィ
-	kirbyX := width/2 - 200
-	kirbyY := height / 2
+	// タイトル
+	titleText := text.New(pixel.V(width/2-130, height-100), m.Atlas)
+	titleText.Color = colornames.Yellow
+	fmt.Fprintf(titleText, "SELECT MODE")
+	titleText.Draw(m.Window, pixel.IM.Scaled(titleText.Orig, 3))
 	
-	if m.characterSelection == 0 {
-		// 選択枠
-		m.IMDraw.Color = colornames.Yellow
-		m.IMDraw.Push(pixel.V(kirbyX-60, kirbyY-80))
-		m.IMDraw.Push(pixel.V(kirbyX+60, kirbyY+80))
-		m.IMDraw.Rectangle(5)
+	// モード一覧
+	for i, entry := range modeEntries {
+		y := height - 220 - float64(i)*90
+		
+		labelColor := colornames.White
+		prefix := "  "
+		if m.modeSelection == i {
+			labelColor = colornames.Yellow
+			prefix = "> "
+		}
+		labelText := text.New(pixel.V(width/2-200, y), m.Atlas)
+		labelText.Color = labelColor
+		fmt.Fprintf(labelText, "%s%s", prefix, entry.Label)
+		labelText.Draw(m.Window, pixel.IM.Scaled(labelText.Orig, 3))
+		
+		descText := text.New(pixel.V(width/2-160, y-30), m.Atlas)
+		descText.Color = colornames.Lightgray
+		fmt.Fprintf(descText, "%s", entry.Description)
+		if entry.Mode == ModeSurvival && m.Save != nil && m.Save.SurvivalBestWave > 0 {
+			fmt.Fprintf(descText, "  (Best: Wave %d)", m.Save.SurvivalBestWave)
+		}
+		descText.Draw(m.Window, pixel.IM.Scaled(descText.Orig, 1.5))
 	}
 	
-	// カービィのプレビュー（ピンクの丸）
-	m.IMDraw.Color = color.RGBA{R: 255, G: 182, B: 193, A: 255}
-	m.IMDraw.Push(pixel.V(kirbyX, kirbyY))
-	m.IMDraw.Circle(40, 0)
-	
-	// メタナイト
-	metaX := width/2 + 200
-	metaY := height / 2
+	// 操作説明
+	instructionText := text.New(pixel.V(width/2-200, 50), m.Atlas)
+	instructionText.Color = colornames.White
+	fmt.Fprintf(instructionText, "UP/DOWN: Select  ENTER: Confirm  ESC: Back")
+	instructionText.Draw(m.Window, pixel.IM.Scaled(instructionText.Orig, 1.5))
+}
+
+// drawSlotSelectText はスロット選択画面のテキストを描画
+func (m *MenuManager) drawSlotSelectText() {
+	width := m.Window.Bounds().W()
+	height := m.Window.Bounds().H()
 	
-	if m.characterSelection == 1 {
-		// 選択枠
-		m.IMDraw.Color = colornames.Yellow
-		m.IMDraw.Push(pixel.V(metaX-60, metaY-80))
-		m.IMDraw.Push(pixel.V(metaX+60, metaY+80))
-		m.IMDraw.Rectangle(5)
+	// タイトル
+	titles := map[SlotAction]string{
+		SlotContinue: "CONTINUE",
+		SlotNewGame:  "NEW GAME",
+		SlotDelete:   "DELETE",
 	}
+	titleText := text.New(pixel.V(width/2-200, height-100), m.Atlas)
+	titleText.Color = colornames.Yellow
+	fmt.Fprintf(titleText, "%s - SELECT SLOT", titles[m.SlotAction])
+	titleText.Draw(m.Window, pixel.IM.Scaled(titleText.Orig, 3))
 	
-	// メタナイトのプレビュー（紫の球体とマスク）
-	m.IMDraw.Color = color.RGBA{R: 100, G: 50, B: 150, A: 255}
-	m.IMDraw.Push(pixel.V(metaX, metaY))
-	m.IMDraw.Circle(40, 0)
+	// スロット一覧
+	for i, info := range m.SaveSlots {
+		y := height - 220 - float64(i)*110
+		
+		labelColor := colornames.White
+		prefix := "  "
+		if m.slotSelection == i {
+			labelColor = colornames.Yellow
+			prefix = "> "
+		}
+		labelText := text.New(pixel.V(width/2-260, y), m.Atlas)
+		labelText.Color = labelColor
+		fmt.Fprintf(labelText, "%sSLOT %d", prefix, info.Slot+1)
+		labelText.Draw(m.Window, pixel.IM.Scaled(labelText.Orig, 3))
+		
+		summaryText := text.New(pixel.V(width/2-220, y-35), m.Atlas)
+		summaryText.Color = colornames.Lightgray
+		switch {
+		case info.Err != nil:
+			summaryText.Color = colornames.Orangered
+			fmt.Fprintf(summaryText, "CORRUPTED DATA")
+		case info.Data == nil:
+			fmt.Fprintf(summaryText, "- EMPTY -")
+		default:
+			d := info.Data
+			items := 0
+			for _, n := range d.Items {
+				items += n
+			}
+			fmt.Fprintf(summaryText, "Cleared %d/%d  Items %d  Play %s",
+				d.ClearedCount(), m.WorldMap.StageCount(), items, formatDuration(d.PlayTime))
+		}
+		summaryText.Draw(m.Window, pixel.IM.Scaled(summaryText.Orig, 1.5))
+	}
 	
-	// マスク
-	m.IMDraw.Color = color.RGBA{R: 200, G: 200, B: 220, A: 255}
-	m.IMDraw.Push(pixel.V(metaX-30, metaY))
-	m.IMDraw.Push(pixel.V(metaX+30, metaY+10))
-	m.IMDraw.Rectangle(0)
+	// 上書き・削除の確認とお知らせ
+	noticeText := text.New(pixel.V(width/2-260, 110), m.Atlas)
+	noticeText.Color = colornames.Orangered
+	if m.confirming {
+		verb := "overwrite"
+		if m.SlotAction == SlotDelete {
+			verb = "delete"
+		}
+		fmt.Fprintf(noticeText, "Press ENTER again to %s SLOT %d", verb, m.slotSelection+1)
+	} else {
+		fmt.Fprintf(noticeText, "%s", m.Notice)
+	}
+	noticeText.Draw(m.Window, pixel.IM.Scaled(noticeText.Orig, 1.5))
 	
-	// 目
-	m.IMDraw.Color = colornames.Yellow
-	m.IMDraw.Push(pixel.V(metaX, metaY+5))
-	m.IMDraw.Circle(8, 0)
+	// 操作説明
+	instructionText := text.New(pixel.V(width/2-200, 50), m.Atlas)
+	instructionText.Color = colornames.White
+	fmt.Fprintf(instructionText, "UP/DOWN: Select  ENTER: Confirm  ESC: Back")
+	instructionText.Draw(m.Window, pixel.IM.Scaled(instructionText.Orig, 1.5))
 }
 
-// drawCharacterSelectText はキャラクター選択画面のテキストを描画
-func (m *MenuManager) drawCharacterSelectText() {
+// formatDuration は秒数を「時:分:秒」の文字列にします
+func formatDuration(seconds float64) string {
+	total := int(seconds)
+	return fmt.Sprintf("%d:%02d:%02d", total/3600, total/60%60, total%60)
+}
+
+// formatClearTime はクリア時間を「分:秒.1/10秒」の文字列にします
+func formatClearTime(seconds float64) string {
+	tenths := int(seconds * 10)
+	return fmt.Sprintf("%d:%02d.%d", tenths/600, tenths/10%60, tenths%10)
+}
+
+// formatRaceTime はタイムアタックのフレーム数を「分:秒.1/100秒」の文字列にします
+func formatRaceTime(frames int) string {
+	cs := int(save.FrameSeconds(frames) * 100)
+	return fmt.Sprintf("%d:%02d.%02d", cs/6000, cs/100%60, cs%100)
+}
+
+// drawVersusRulesText は対戦ルール設定画面のテキストを描画
+func (m *MenuManager) drawVersusRulesText() {
 	width := m.Window.Bounds().W()
 	height := m.Window.Bounds().H()
 	
 	// タイトル
-	titleText := text.New(pixel.V(width/2-160, height-80), m.Atlas)
-	titleText.Color = colornames.Yellow
-	fmt.Fprintf(titleText, "SELECT CHARACTER")
+	titleText := text.New(pixel.V(width/2-140, height-100), m.Atlas)
+	titleText.Color = colornames.Orangered
+	fmt.Fprintf(titleText, "VERSUS RULES")
 	titleText.Draw(m.Window, pixel.IM.Scaled(titleText.Orig, 3))
 	
-	// 説明文
-	descText := text.New(pixel.V(width/2-280, height-130), m.Atlas)
-	descText.Color = colornames.White
-	fmt.Fprintf(descText, "Choose your hero! Each character has unique abilities.")
+	// ルール
+	ruleText := text.New(pixel.V(width/2-160, height/2+60), m.Atlas)
+	ruleText.Color = colornames.Yellow
+	if m.VersusRule == RuleStock {
+		fmt.Fprintf(ruleText, "< STOCK >")
+	} else {
+		fmt.Fprintf(ruleText, "< TIME >")
+	}
+	ruleText.Draw(m.Window, pixel.IM.Scaled(ruleText.Orig, 3))
+	
+	// 設定値
+	valueText := text.New(pixel.V(width/2-160, height/2-20), m.Atlas)
+	valueText.Color = colornames.White
+	if m.VersusRule == RuleStock {
+		fmt.Fprintf(valueText, "Stocks: %d", m.VersusStocks)
+	} else {
+		fmt.Fprintf(valueText, "Time: %d min", m.VersusMinutes)
+	}
+	valueText.Draw(m.Window, pixel.IM.Scaled(valueText.Orig, 2.5))
+	
+	// 説明
+	descText := text.New(pixel.V(width/2-220, height/2-80), m.Atlas)
+	descText.Color = colornames.Lightgray
+	if m.VersusRule == RuleStock {
+		fmt.Fprintf(descText, "Last fighter with stocks left wins.")
+	} else {
+		fmt.Fprintf(descText, "Most KOs when time runs out wins.")
+	}
 	descText.Draw(m.Window, pixel.IM.Scaled(descText.Orig, 1.5))
 	
-	// カービィ
-	kirbyX := width/2 - 200
-	kirbyY := height / 2
-	
-	kirbyNameText := text.New(pixel.V(kirbyX-30, kirbyY-120), m.Atlas)
-	kirbyNameText.Color = colornames.White
-	fmt.Fprintf(kirbyNameText, "KIRBY")
-	kirbyNameText.Draw(m.Window, pixel.IM.Scaled(kirbyNameText.Orig, 2))
-	
-	// カービィの説明
-	kirbyDescText := text.New(pixel.V(kirbyX-80, kirbyY+70), m.Atlas)
-	kirbyDescText.Color = color.RGBA{R: 255, G: 192, B: 203, A: 255}
-	fmt.Fprintf(kirbyDescText, "Copy Ability")
-	kirbyDescText.Draw(m.Window, pixel.IM.Scaled(kirbyDescText.Orig, 1.3))
-	
-	kirbyDesc2Text := text.New(pixel.V(kirbyX-70, kirbyY+50), m.Atlas)
-	kirbyDesc2Text.Color = colornames.Lightgray
-	fmt.Fprintf(kirbyDesc2Text, "Versatile!")
-	kirbyDesc2Text.Draw(m.Window, pixel.IM.Scaled(kirbyDesc2Text.Orig, 1.2))
-	
-	// メタナイト
-	metaX := width/2 + 200
-	metaY := height / 2
-	
-	metaNameText := text.New(pixel.V(metaX-50, metaY-120), m.Atlas)
-	metaNameText.Color = colornames.White
-	fmt.Fprintf(metaNameText, "META KNIGHT")
-	metaNameText.Draw(m.Window, pixel.IM.Scaled(metaNameText.Orig, 2))
-	
-	// メタナイトの説明
-	metaDescText := text.New(pixel.V(metaX-80, metaY+70), m.Atlas)
-	metaDescText.Color = color.RGBA{R: 147, G: 112, B: 219, A: 255}
-	fmt.Fprintf(metaDescText, "Sword Master")
-	metaDescText.Draw(m.Window, pixel.IM.Scaled(metaDescText.Orig, 1.3))
-	
-	metaDesc2Text := text.New(pixel.V(metaX-60, metaY+50), m.Atlas)
-	metaDesc2Text.Color = colornames.Lightgray
-	fmt.Fprintf(metaDesc2Text, "Powerful!")
-	metaDesc2Text.Draw(m.Window, pixel.IM.Scaled(metaDesc2Text.Orig, 1.2))
-	
 	// 操作説明
-	instructionText := text.New(pixel.V(width/2-200, 50), m.Atlas)
+	instructionText := text.New(pixel.V(width/2-260, 50), m.Atlas)
 	instructionText.Color = colornames.White
-	fmt.Fprintf(instructionText, "LEFT/RIGHT: Select  ENTER: Confirm  ESC: Back")
+	fmt.Fprintf(instructionText, "LEFT/RIGHT: Rule  UP/DOWN: Value  ENTER: Fight!  ESC: Back")
 	instructionText.Draw(m.Window, pixel.IM.Scaled(instructionText.Orig, 1.5))
 }
 
-// drawStageSelect はステージ選択画面の図形を描画
-func (m *MenuManager) drawStageSelect() {
+// characterCardX はキャラクター選択カードの中心X座標を返します
+func (m *MenuManager) characterCardX(index, count int) float64 {
+	width := m.Window.Bounds().W()
+	spacing := math.Min(400, width/float64(count))
+	return width/2 + (float64(index)-float64(count-1)/2)*spacing
+}
+
+// drawCharacterSelect はキャラクター選択画面の図形を描画
+func (m *MenuManager) drawCharacterSelect() {
 	width := m.Window.Bounds().W()
 	height := m.Window.Bounds().H()
 	
 	// 背景
-	m.IMDraw.Color = color.RGBA{R: 30, G: 30, B: 50, A: 255}
+	m.IMDraw.Color = color.RGBA{R: 50, G: 50, B: 80, A: 255}
 	m.IMDraw.Push(pixel.V(0, 0))
 	m.IMDraw.Push(pixel.V(width, height))
 	m.IMDraw.Rectangle(0)
 	
-	// ステージ1
-	stage1X := width/2 - 200
-	stage1Y := height / 2
-	
-	if m.stageSelection == 0 {
-		m.IMDraw.Color = colornames.Yellow
-		m.IMDraw.Push(pixel.V(stage1X-80, stage1Y-60))
-		m.IMDraw.Push(pixel.V(stage1X+80, stage1Y+60))
-		m.IMDraw.Rectangle(5)
+	characters := entity.Characters()
+	for i, info := range characters {
+		cardX := m.characterCardX(i, len(characters))
+		cardY := height / 2
+		
+		// 2Pの選択中は1Pが選んだキャラクターに別色の枠を付ける
+		if m.selectingPlayer > 0 && len(m.SelectedCharacters) > 0 && int(m.SelectedCharacters[0]) == i {
+			m.IMDraw.Color = color.RGBA{R: 255, G: 105, B: 180, A: 255}
+			m.IMDraw.Push(pixel.V(cardX-68, cardY-88))
+			m.IMDraw.Push(pixel.V(cardX+68, cardY+88))
+			m.IMDraw.Rectangle(3)
+		}
+		
+		if m.characterSelection == i {
+			// 選択枠
+			m.IMDraw.Color = colornames.Yellow
+			m.IMDraw.Push(pixel.V(cardX-60, cardY-80))
+			m.IMDraw.Push(pixel.V(cardX+60, cardY+80))
+			m.IMDraw.Rectangle(5)
+		}
+		
+		// プレビュー（キャラクター自身の描画処理を使う）
+		if preview := m.characterPreview(info, pixel.V(cardX, cardY)); preview != nil {
+			preview.Draw(m.IMDraw)
+		}
 	}
-	
-	// ステージ1アイコン
-	m.IMDraw.Color = color.RGBA{R: 100, G: 200, B: 100, A: 255}
-	m.IMDraw.Push(pixel.V(stage1X-50, stage1Y-30))
-	m.IMDraw.Push(pixel.V(stage1X+50, stage1Y+30))
-	m.IMDraw.Rectangle(0)
-	
-	// ステージ2
-	stage2X := width/2 + 200
-	stage2Y := height / 2
-	
-	if m.stageSelection == 1 {
-		m.IMDraw.Color = colornames.Yellow
-		m.IMDraw.Push(pixel.V(stage2X-80, stage2Y-60))
-		m.IMDraw.Push(pixel.V(stage2X+80, stage2Y+60))
-		m.IMDraw.Rectangle(5)
+}
+
+// characterPreview はキャラクター選択画面のプレビューを pos に置いて返します。
+// 毎フレーム作り直さないように、登録IDごとに最初の1回だけ作って使い回します
+func (m *MenuManager) characterPreview(info entity.CharacterInfo, pos pixel.Vec) entity.PlayableCharacter {
+	if m.previews == nil {
+		m.previews = make(map[string]entity.PlayableCharacter)
 	}
-	
-	// ステージ2アイコン
-	m.IMDraw.Color = color.RGBA{R: 100, G: 100, B: 200, A: 255}
-	m.IMDraw.Push(pixel.V(stage2X-50, stage2Y-30))
-	m.IMDraw.Push(pixel.V(stage2X+50, stage2Y+30))
-	m.IMDraw.Rectangle(0)
+	preview, ok := m.previews[info.ID]
+	if !ok {
+		preview = info.New(pos)
+		m.previews[info.ID] = preview
+	}
+	if preview != nil {
+		preview.SetPosition(pos)
+	}
+	return preview
 }
 
-// drawStageSelectText はステージ選択画面のテキストを描画
-func (m *MenuManager) drawStageSelectText() {
+// characterLevel はセーブデータのキャラクターのレベルを返します。
+// スロットを選んでいない時と、レベル1の能力値で遊ぶ対戦・タイムアタックでは false です
+func (m *MenuManager) characterLevel(id string) (int, bool) {
+	if m.Save == nil || m.SelectedMode == ModeVersus || m.SelectedMode == ModeTimeAttack {
+		return 0, false
+	}
+	if p, ok := m.Save.Characters[id]; ok {
+		return p.Level, true
+	}
+	return 1, true
+}
+
+// drawCharacterSelectText はキャラクター選択画面のテキストを描画
+func (m *MenuManager) drawCharacterSelectText() {
 	width := m.Window.Bounds().W()
 	height := m.Window.Bounds().H()
 	
 	// タイトル
-	titleText := text.New(pixel.V(width/2-140, height-80), m.Atlas)
-	titleText.Color = colornames.Cyan
-	fmt.Fprintf(titleText, "SELECT STAGE")
+	titleX := width/2 - 160
+	if m.CoopEnabled {
+		titleX -= 40
+	}
+	titleText := text.New(pixel.V(titleX, height-80), m.Atlas)
+	titleText.Color = colornames.Yellow
+	if m.CoopEnabled {
+		fmt.Fprintf(titleText, "%dP SELECT CHARACTER", m.selectingPlayer+1)
+	} else {
+		fmt.Fprintf(titleText, "SELECT CHARACTER")
+	}
 	titleText.Draw(m.Window, pixel.IM.Scaled(titleText.Orig, 3))
 	
 	// 説明文
-	descText := text.New(pixel.V(width/2-220, height-130), m.Atlas)
+	descText := text.New(pixel.V(width/2-280, height-130), m.Atlas)
 	descText.Color = colornames.White
-	fmt.Fprintf(descText, "Pick your battlefield and face the boss!")
+	fmt.Fprintf(descText, "Choose your hero! Each character has unique abilities.")
 	descText.Draw(m.Window, pixel.IM.Scaled(descText.Orig, 1.5))
 	
-	// ステージ1
-	stage1X := width/2 - 200
-	stage1Y := height / 2
-	
-	stage1Text := text.New(pixel.V(stage1X-40, stage1Y-80), m.Atlas)
-	stage1Text.Color = colornames.White
-	fmt.Fprintf(stage1Text, "STAGE 1")
-	stage1Text.Draw(m.Window, pixel.IM.Scaled(stage1Text.Orig, 2))
-	
-	// ステージ1の説明
-	stage1DescText := text.New(pixel.V(stage1X-60, stage1Y+50), m.Atlas)
-	stage1DescText.Color = colornames.Lightgreen
-	fmt.Fprintf(stage1DescText, "Dedede Castle")
-	stage1DescText.Draw(m.Window, pixel.IM.Scaled(stage1DescText.Orig, 1.3))
-	
-	bossText1 := text.New(pixel.V(stage1X-60, stage1Y-110), m.Atlas)
-	bossText1.Color = colornames.Red
-	fmt.Fprintf(bossText1, "Boss: Dedede")
-	bossText1.Draw(m.Window, pixel.IM.Scaled(bossText1.Orig, 1.5))
-	
-	// ステージ2
-	stage2X := width/2 + 200
-	stage2Y := height / 2
-	
-	stage2Text := text.New(pixel.V(stage2X-40, stage2Y-80), m.Atlas)
-	stage2Text.Color = colornames.White
-	fmt.Fprintf(stage2Text, "STAGE 2")
-	stage2Text.Draw(m.Window, pixel.IM.Scaled(stage2Text.Orig, 2))
-	
-	// ステージ2の説明
-	stage2DescText := text.New(pixel.V(stage2X-70, stage2Y+50), m.Atlas)
-	stage2DescText.Color = color.RGBA{R: 147, G: 112, B: 219, A: 255}
-	fmt.Fprintf(stage2DescText, "Halberd Warship")
-	stage2DescText.Draw(m.Window, pixel.IM.Scaled(stage2DescText.Orig, 1.3))
-	
-	bossText2 := text.New(pixel.V(stage2X-70, stage2Y-110), m.Atlas)
-	bossText2.Color = colornames.Purple
-	fmt.Fprintf(bossText2, "Boss: Meta Knight")
-	bossText2.Draw(m.Window, pixel.IM.Scaled(bossText2.Orig, 1.5))
+	// 協力プレイ表示
+	coopText := text.New(pixel.V(width/2-100, height-160), m.Atlas)
+	coopText.Color = colornames.Lightgray
+	if m.SelectedMode == ModeVersus {
+		coopText.Color = colornames.Orangered
+		fmt.Fprintf(coopText, "2P VERSUS")
+	} else if m.CoopEnabled {
+		coopText.Color = colornames.Lightgreen
+		fmt.Fprintf(coopText, "2P CO-OP: ON")
+	} else {
+		fmt.Fprintf(coopText, "2P CO-OP: OFF")
+	}
+	coopText.Draw(m.Window, pixel.IM.Scaled(coopText.Orig, 1.5))
+	
+	characters := entity.Characters()
+	for i, info := range characters {
+		cardX := m.characterCardX(i, len(characters))
+		cardY := height / 2
+		
+		// 名前（7px幅のフォントを2倍で描くのでカード中央に寄せる）
+		nameText := text.New(pixel.V(cardX-float64(len(info.DisplayName))*7, cardY-120), m.Atlas)
+		nameText.Color = colornames.White
+		fmt.Fprintf(nameText, "%s", info.DisplayName)
+		nameText.Draw(m.Window, pixel.IM.Scaled(nameText.Orig, 2))
+		
+		// レベルと能力値（レベルを使うモードだけ）
+		if level, ok := m.characterLevel(info.ID); ok {
+			stats := entity.StatsFor(info.ID, level)
+			levelText := text.New(pixel.V(cardX-75, cardY-145), m.Atlas)
+			levelText.Color = colornames.Lightskyblue
+			fmt.Fprintf(levelText, "Lv%d HP%d ATK%d DEF%d", stats.Level, stats.MaxHealth, stats.Attack, stats.Defense)
+			levelText.Draw(m.Window, pixel.IM.Scaled(levelText.Orig, 1.1))
+		}
+		
+		// 特徴
+		taglineText := text.New(pixel.V(cardX-80, cardY+70), m.Atlas)
+		taglineText.Color = info.Color
+		fmt.Fprintf(taglineText, "%s", info.Tagline)
+		taglineText.Draw(m.Window, pixel.IM.Scaled(taglineText.Orig, 1.3))
+		
+		descText := text.New(pixel.V(cardX-70, cardY+50), m.Atlas)
+		descText.Color = colornames.Lightgray
+		fmt.Fprintf(descText, "%s", info.Description)
+		descText.Draw(m.Window, pixel.IM.Scaled(descText.Orig, 1.2))
+	}
 	
 	// 操作説明
 	instructionText := text.New(pixel.V(width/2-200, 50), m.Atlas)
 	instructionText.Color = colornames.White
-	fmt.Fprintf(instructionText, "LEFT/RIGHT: Select  ENTER: Confirm  ESC: Back")
+	if m.SelectedMode == ModeVersus || m.SelectedMode == ModeTimeAttack {
+		fmt.Fprintf(instructionText, "LEFT/RIGHT: Select  ENTER: Confirm  ESC: Back")
+	} else {
+		fmt.Fprintf(instructionText, "LEFT/RIGHT: Select  ENTER: Confirm  TAB: 2P Co-op  ESC: Back")
+	}
 	instructionText.Draw(m.Window, pixel.IM.Scaled(instructionText.Orig, 1.5))
 }
 
+// characterInfo は選択したキャラクターの登録情報を返します
+func characterInfo(pc PlayerCharacter) (entity.CharacterInfo, bool) {
+	characters := entity.Characters()
+	index := int(pc)
+	if index < 0 || index >= len(characters) {
+		return entity.CharacterInfo{}, false
+	}
+	return characters[index], true
+}
+
+// SelectedCharacterIDs はプレイヤー順に選択中キャラクターの生成用IDを返します
+func (m *MenuManager) SelectedCharacterIDs() []string {
+	selected := m.SelectedCharacters
+	if len(selected) == 0 {
+		selected = []PlayerCharacter{m.SelectedCharacter}
+	}
+	
+	ids := make([]string, 0, len(selected))
+	for _, pc := range selected {
+		if info, ok := characterInfo(pc); ok {
+			ids = append(ids, info.ID)
+		}
+	}
+	if len(ids) == 0 {
+		ids = append(ids, "Kirby")
+	}
+	return ids
+}
+
 // GetCharacterName はキャラクター名を返します
 func (m *MenuManager) GetCharacterName() string {
-	switch m.SelectedCharacter {
-	case CharacterKirby:
-		return "Kirby"
-	case CharacterMetaKnight:
-		return "Meta Knight"
-	default:
-		return "Unknown"
+	if info, ok := characterInfo(m.SelectedCharacter); ok {
+		return info.DisplayName
 	}
+	return "Unknown"
 }
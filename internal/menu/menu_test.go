@@ -0,0 +1,40 @@
+package menu
+
+import (
+	"testing"
+
+	"github.com/faiface/pixel"
+
+	"github.com/remmakoshino/kirby-inspired-go/internal/entity"
+)
+
+func TestCharacterPreviewsAreCached(t *testing.T) {
+	m := NewMenuManager(nil)
+	for _, info := range entity.Characters() {
+		first := m.characterPreview(info, pixel.V(100, 200))
+		if first == nil {
+			t.Fatalf("%s: no preview", info.ID)
+		}
+		second := m.characterPreview(info, pixel.V(300, 250))
+		if second != first {
+			t.Errorf("%s: preview was rebuilt instead of reused", info.ID)
+		}
+		if pos := second.GetPosition(); pos != pixel.V(300, 250) {
+			t.Errorf("%s: preview at %v, want it moved to the card", info.ID, pos)
+		}
+	}
+	if len(m.previews) != len(entity.Characters()) {
+		t.Errorf("cached %d previews, want one per registered character (%d)", len(m.previews), len(entity.Characters()))
+	}
+}
+
+func TestSelectedCharacterIDsFollowRegistry(t *testing.T) {
+	m := NewMenuManager(nil)
+	for i, info := range entity.Characters() {
+		m.SelectedCharacter = PlayerCharacter(i)
+		m.SelectedCharacters = nil
+		if ids := m.SelectedCharacterIDs(); len(ids) != 1 || ids[0] != info.ID {
+			t.Errorf("selection %d = %v, want [%s]", i, ids, info.ID)
+		}
+	}
+}
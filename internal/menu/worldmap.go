@@ -0,0 +1,360 @@
+package menu
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelgl"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+
+	"github.com/remmakoshino/kirby-inspired-go/internal/save"
+	"github.com/remmakoshino/kirby-inspired-go/internal/worldmap"
+)
+
+// ワールドマップの扉の大きさ
+const (
+	doorWidth      = 40.0
+	doorHeight     = 56.0
+	bossDoorWidth  = 60.0
+	bossDoorHeight = 76.0
+)
+
+// lockedColor は開いていない扉や道の色です
+var lockedColor = color.RGBA{R: 90, G: 90, B: 90, A: 255}
+
+// progress はワールドマップに渡すクリア状況を返します（セーブデータがなければ nil ですべて開く）
+func (m *MenuManager) progress() worldmap.Progress {
+	if m.Save == nil {
+		return nil
+	}
+	return m.Save
+}
+
+// stageCleared はステージをクリアしたかを返します
+func (m *MenuManager) stageCleared(stage int) bool {
+	return m.Save != nil && m.Save.Cleared(stage)
+}
+
+// saveRecord はセーブデータにあるステージの記録を返します
+func (m *MenuManager) saveRecord(stage int) (*save.StageRecord, bool) {
+	if m.Save == nil {
+		return nil, false
+	}
+	r, ok := m.Save.Stages[stage]
+	return r, ok
+}
+
+// OpenWorldMap はワールドマップを開き、次に挑戦する扉にカーソルを合わせます
+func (m *MenuManager) OpenWorldMap() {
+	m.mapLevel, m.mapNode = m.WorldMap.NextNode(m.progress())
+	m.State = StateWorldMap
+}
+
+// updateWorldMap はワールドマップの更新処理
+func (m *MenuManager) updateWorldMap() {
+	wm := m.WorldMap
+	p := m.progress()
+	nodes := wm.Nodes(m.mapLevel)
+	
+	// 左右キーで道に沿って移動（開いていない扉の先には進めない）
+	if m.Window.JustPressed(pixelgl.KeyLeft) || m.Window.JustPressed(pixelgl.KeyA) {
+		if m.mapNode > 0 {
+			m.mapNode--
+		}
+	}
+	if m.Window.JustPressed(pixelgl.KeyRight) || m.Window.JustPressed(pixelgl.KeyD) {
+		if m.mapNode+1 < len(nodes) && wm.NodeUnlocked(m.mapLevel, m.mapNode+1, p) {
+			m.mapNode++
+		}
+	}
+	
+	// 上下キーでレベルを移動
+	level := m.mapLevel
+	if m.Window.JustPressed(pixelgl.KeyUp) || m.Window.JustPressed(pixelgl.KeyW) {
+		level--
+	}
+	if m.Window.JustPressed(pixelgl.KeyDown) || m.Window.JustPressed(pixelgl.KeyS) {
+		level++
+	}
+	if level != m.mapLevel && level >= 0 && level < len(wm.Levels) && wm.LevelUnlocked(level, p) {
+		m.mapLevel = level
+		m.mapNode = clampInt(m.mapNode, 0, len(wm.Nodes(level))-1)
+		for m.mapNode > 0 && !wm.NodeUnlocked(level, m.mapNode, p) {
+			m.mapNode--
+		}
+	}
+	
+	// Enterで扉に入る（タイムアタックはクリアしたステージだけ）
+	if m.Window.JustPressed(pixelgl.KeyEnter) || m.Window.JustPressed(pixelgl.KeySpace) {
+		if m.timeAttackOpen(m.mapLevel, m.mapNode) {
+			m.SelectedStage = wm.Nodes(m.mapLevel)[m.mapNode].Stage
+			m.State = StatePlaying
+		}
+	}
+	
+	// ESCで戻る
+	if m.Window.JustPressed(pixelgl.KeyEscape) {
+		m.State = StateCharacterSelect
+	}
+}
+
+// timeAttackOpen は扉に入れるかを返します。タイムアタックではストーリーでクリアしたステージだけ遊べます
+// （セーブデータがなければすべて遊べる）
+func (m *MenuManager) timeAttackOpen(level, index int) bool {
+	if !m.WorldMap.NodeUnlocked(level, index, m.progress()) {
+		return false
+	}
+	if m.SelectedMode != ModeTimeAttack || m.Save == nil {
+		return true
+	}
+	return m.stageCleared(m.WorldMap.Nodes(level)[index].Stage)
+}
+
+// levelColor はレベルの色を返します
+func levelColor(level worldmap.Level) color.RGBA {
+	return color.RGBA{R: level.Color[0], G: level.Color[1], B: level.Color[2], A: 255}
+}
+
+// doorSize は扉の大きさを返します
+func (m *MenuManager) doorSize(level, index int) (float64, float64) {
+	if m.WorldMap.IsBoss(level, index) {
+		return bossDoorWidth, bossDoorHeight
+	}
+	return doorWidth, doorHeight
+}
+
+// drawWorldMap はワールドマップの図形を描画
+func (m *MenuManager) drawWorldMap() {
+	width := m.Window.Bounds().W()
+	height := m.Window.Bounds().H()
+	wm := m.WorldMap
+	p := m.progress()
+	
+	// 背景
+	m.IMDraw.Color = color.RGBA{R: 30, G: 40, B: 70, A: 255}
+	m.IMDraw.Push(pixel.V(0, 0))
+	m.IMDraw.Push(pixel.V(width, height))
+	m.IMDraw.Rectangle(0)
+	
+	for li, level := range wm.Levels {
+		nodes := wm.Nodes(li)
+		unlocked := wm.LevelUnlocked(li, p)
+		
+		// レベルの島
+		minY, maxY := math.Inf(1), math.Inf(-1)
+		for _, node := range nodes {
+			minY = math.Min(minY, node.Y)
+			maxY = math.Max(maxY, node.Y)
+		}
+		m.IMDraw.Color = lockedColor
+		if unlocked {
+			c := levelColor(level)
+			m.IMDraw.Color = color.RGBA{R: c.R / 2, G: c.G / 2, B: c.B / 2, A: 255}
+		}
+		m.IMDraw.Push(pixel.V(80, minY-70))
+		m.IMDraw.Push(pixel.V(width-80, maxY+60))
+		m.IMDraw.Rectangle(0)
+		
+		// 扉をつなぐ道
+		for i := 1; i < len(nodes); i++ {
+			m.IMDraw.Color = lockedColor
+			if wm.NodeUnlocked(li, i, p) {
+				m.IMDraw.Color = colornames.Wheat
+			}
+			m.IMDraw.Push(pixel.V(nodes[i-1].X, nodes[i-1].Y-20))
+			m.IMDraw.Push(pixel.V(nodes[i].X, nodes[i].Y-20))
+			m.IMDraw.Line(6)
+		}
+		
+		// 扉
+		for i, node := range nodes {
+			m.drawDoor(li, i, node)
+		}
+	}
+	
+	// 選んでいる扉の前にキャラクターを立たせる
+	node := wm.Nodes(m.mapLevel)[m.mapNode]
+	w, h := m.doorSize(m.mapLevel, m.mapNode)
+	if info, ok := characterInfo(m.SelectedCharacter); ok {
+		if preview := info.New(pixel.V(node.X+w/2+24, node.Y-h/2+20)); preview != nil {
+			preview.Draw(m.IMDraw)
+		}
+	}
+}
+
+// drawDoor は扉を1つ描画します（開いていない扉には錠前、クリアした扉には星）
+func (m *MenuManager) drawDoor(level, index int, node worldmap.Node) {
+	p := m.progress()
+	w, h := m.doorSize(level, index)
+	unlocked := m.WorldMap.NodeUnlocked(level, index, p)
+	lo := pixel.V(node.X-w/2, node.Y-h/2)
+	hi := pixel.V(node.X+w/2, node.Y+h/2)
+	
+	// 選択枠
+	if level == m.mapLevel && index == m.mapNode {
+		m.IMDraw.Color = colornames.Yellow
+		m.IMDraw.Push(lo.Sub(pixel.V(6, 6)), hi.Add(pixel.V(6, 6)))
+		m.IMDraw.Rectangle(4)
+	}
+	
+	// 扉（ボスの扉は赤い枠）
+	m.IMDraw.Color = lockedColor
+	if unlocked {
+		m.IMDraw.Color = color.RGBA{R: 140, G: 90, B: 50, A: 255}
+	}
+	m.IMDraw.Push(lo, hi)
+	m.IMDraw.Rectangle(0)
+	if m.WorldMap.IsBoss(level, index) {
+		m.IMDraw.Color = colornames.Crimson
+		m.IMDraw.Push(lo, hi)
+		m.IMDraw.Rectangle(4)
+	}
+	
+	if unlocked {
+		// ドアノブ
+		m.IMDraw.Color = colornames.Gold
+		m.IMDraw.Push(pixel.V(node.X+w/4, node.Y))
+		m.IMDraw.Circle(3, 0)
+	} else {
+		// 錠前
+		m.IMDraw.Color = colornames.Darkgoldenrod
+		m.IMDraw.Push(pixel.V(node.X, node.Y+6))
+		m.IMDraw.Circle(7, 3)
+		m.IMDraw.Push(pixel.V(node.X-9, node.Y-12), pixel.V(node.X+9, node.Y+4))
+		m.IMDraw.Rectangle(0)
+	}
+	
+	// クリアの星
+	if m.stageCleared(node.Stage) {
+		m.drawStar(pixel.V(node.X, hi.Y+16), 12)
+	}
+}
+
+// drawStar は星を描画します
+func (m *MenuManager) drawStar(center pixel.Vec, radius float64) {
+	m.IMDraw.Color = colornames.Yellow
+	for i := 0; i < 10; i++ {
+		r := radius
+		if i%2 == 1 {
+			r = radius * 0.45
+		}
+		angle := math.Pi/2 + float64(i)*math.Pi/5
+		m.IMDraw.Push(center.Add(pixel.V(math.Cos(angle)*r, math.Sin(angle)*r)))
+	}
+	m.IMDraw.Polygon(0)
+}
+
+// drawWorldMapText はワールドマップのテキストを描画
+func (m *MenuManager) drawWorldMapText() {
+	width := m.Window.Bounds().W()
+	height := m.Window.Bounds().H()
+	wm := m.WorldMap
+	p := m.progress()
+	
+	// タイトル
+	titleText := text.New(pixel.V(width/2-110, height-70), m.Atlas)
+	titleText.Color = colornames.Yellow
+	if m.SelectedMode == ModeTimeAttack {
+		fmt.Fprintf(titleText, "TIME ATTACK")
+	} else {
+		fmt.Fprintf(titleText, "WORLD MAP")
+	}
+	titleText.Draw(m.Window, pixel.IM.Scaled(titleText.Orig, 3))
+	
+	for li, level := range wm.Levels {
+		nodes := wm.Nodes(li)
+		maxY := math.Inf(-1)
+		for _, node := range nodes {
+			maxY = math.Max(maxY, node.Y)
+		}
+		
+		// レベル名（すべてクリアしたら COMPLETE）
+		nameText := text.New(pixel.V(100, maxY+70), m.Atlas)
+		nameText.Color = levelColor(level)
+		fmt.Fprintf(nameText, "%s", level.Name)
+		if !wm.LevelUnlocked(li, p) {
+			nameText.Color = colornames.Gray
+			fmt.Fprintf(nameText, "  (LOCKED)")
+		} else if m.Save != nil && wm.LevelComplete(li, p) {
+			nameText.Color = colornames.Gold
+			fmt.Fprintf(nameText, "  COMPLETE!")
+		}
+		nameText.Draw(m.Window, pixel.IM.Scaled(nameText.Orig, 2))
+		
+		// 扉の名前
+		for i, node := range nodes {
+			_, h := m.doorSize(li, i)
+			nodeText := text.New(pixel.V(node.X-float64(len(node.Name))*7*1.2/2, node.Y-h/2-22), m.Atlas)
+			nodeText.Color = colornames.White
+			if !wm.NodeUnlocked(li, i, p) {
+				nodeText.Color = colornames.Gray
+			}
+			fmt.Fprintf(nodeText, "%s", node.Name)
+			nodeText.Draw(m.Window, pixel.IM.Scaled(nodeText.Orig, 1.2))
+		}
+	}
+	
+	// 選んでいる扉の情報
+	node := wm.Nodes(m.mapLevel)[m.mapNode]
+	infoText := text.New(pixel.V(width/2-300, 130), m.Atlas)
+	infoText.Color = colornames.White
+	fmt.Fprintf(infoText, "%s", node.Name)
+	infoText.Draw(m.Window, pixel.IM.Scaled(infoText.Orig, 2.5))
+	
+	detailText := text.New(pixel.V(width/2-300, 95), m.Atlas)
+	detailText.Color = colornames.Lightgray
+	switch r, ok := m.saveRecord(node.Stage); {
+	case !wm.NodeUnlocked(m.mapLevel, m.mapNode, p) && wm.IsBoss(m.mapLevel, m.mapNode):
+		detailText.Color = colornames.Orangered
+		fmt.Fprintf(detailText, "Clear every stage in %s to open the boss gate", wm.Levels[m.mapLevel].Name)
+	case !wm.NodeUnlocked(m.mapLevel, m.mapNode, p):
+		detailText.Color = colornames.Gray
+		fmt.Fprintf(detailText, "LOCKED")
+	case m.SelectedMode == ModeTimeAttack:
+		m.writeTimeAttackDetail(detailText, node.Stage)
+	case ok && r.Cleared:
+		detailText.Color = colornames.Gold
+		fmt.Fprintf(detailText, "CLEAR  Best %d  Time %s", r.BestScore, formatClearTime(r.BestTime))
+	case wm.IsBoss(m.mapLevel, m.mapNode):
+		detailText.Color = colornames.Orangered
+		fmt.Fprintf(detailText, "BOSS GATE")
+	default:
+		fmt.Fprintf(detailText, "Not cleared yet")
+	}
+	detailText.Draw(m.Window, pixel.IM.Scaled(detailText.Orig, 1.5))
+	
+	// 操作説明
+	instructionText := text.New(pixel.V(width/2-260, 40), m.Atlas)
+	instructionText.Color = colornames.White
+	fmt.Fprintf(instructionText, "ARROWS: Move  ENTER: Enter Stage  ESC: Back")
+	instructionText.Draw(m.Window, pixel.IM.Scaled(instructionText.Orig, 1.5))
+}
+
+// writeTimeAttackDetail はタイムアタックで選んでいる扉の自己ベストとスプリットを書きます
+func (m *MenuManager) writeTimeAttackDetail(detailText *text.Text, stage int) {
+	if m.Save == nil {
+		fmt.Fprintf(detailText, "No save slot - records are not kept")
+		return
+	}
+	if !m.stageCleared(stage) {
+		detailText.Color = colornames.Gray
+		fmt.Fprintf(detailText, "Clear this stage in STORY to race it")
+		return
+	}
+	r, ok := m.Save.TimeAttack[stage]
+	if !ok {
+		fmt.Fprintf(detailText, "No record yet")
+		return
+	}
+	detailText.Color = colornames.Gold
+	fmt.Fprintf(detailText, "BEST %s  (%s)", formatRaceTime(r.Frames), r.Character)
+	for i, split := range r.Splits {
+		if i%4 == 0 {
+			fmt.Fprintf(detailText, "\n")
+		}
+		fmt.Fprintf(detailText, "CP%d %s  ", i+1, formatRaceTime(split))
+	}
+}
@@ -0,0 +1,80 @@
+// Package netcode は2人プレイをネットワーク越しに同期するロールバック方式のネットコードです。
+//
+// 各マシンは自分の入力だけを送り合い、相手の入力が届いていないフレームは
+// 直前の入力が続くと予測して先に進みます。予測が外れた入力が届いたら、
+// そのフレームの状態まで巻き戻して（ロールバック）正しい入力で再シミュレートします。
+// 確定したフレームの状態のチェックサムを交換し、ずれ（デシンク）を検出します。
+package netcode
+
+import "github.com/remmakoshino/kirby-inspired-go/internal/entity"
+
+// Input は1フレーム分のプレイヤー入力をビット列に詰めたものです
+type Input uint16
+
+const (
+	InputLeft Input = 1 << iota
+	InputRight
+	InputUp
+	InputDown
+	InputJump
+	InputAttack
+	InputUseAbility
+	InputGuard
+	InputRun
+	InputEvade
+	InputCreateHelper
+)
+
+// 能力の切り替え番号（0〜3）は上位の2ビットに入れる
+const (
+	switchAbilityShift = 14
+	switchAbilityMask  = 0x3
+)
+
+// PackInput はプレイヤー入力をビット列に変換します
+func PackInput(in entity.PlayerInput) Input {
+	var bits Input
+	flags := []struct {
+		on  bool
+		bit Input
+	}{
+		{in.MoveLeft, InputLeft},
+		{in.MoveRight, InputRight},
+		{in.Up, InputUp},
+		{in.Down, InputDown},
+		{in.Jump, InputJump},
+		{in.Attack, InputAttack},
+		{in.UseAbility, InputUseAbility},
+		{in.Guard, InputGuard},
+		{in.Run, InputRun},
+		{in.Evade, InputEvade},
+		{in.CreateHelper, InputCreateHelper},
+	}
+	for _, f := range flags {
+		if f.on {
+			bits |= f.bit
+		}
+	}
+	if in.SwitchAbility > 0 && in.SwitchAbility <= switchAbilityMask {
+		bits |= Input(in.SwitchAbility) << switchAbilityShift
+	}
+	return bits
+}
+
+// Unpack はビット列をプレイヤー入力に戻します
+func (bits Input) Unpack() entity.PlayerInput {
+	return entity.PlayerInput{
+		MoveLeft:      bits&InputLeft != 0,
+		MoveRight:     bits&InputRight != 0,
+		Up:            bits&InputUp != 0,
+		Down:          bits&InputDown != 0,
+		Jump:          bits&InputJump != 0,
+		Attack:        bits&InputAttack != 0,
+		UseAbility:    bits&InputUseAbility != 0,
+		Guard:         bits&InputGuard != 0,
+		Run:           bits&InputRun != 0,
+		Evade:         bits&InputEvade != 0,
+		CreateHelper:  bits&InputCreateHelper != 0,
+		SwitchAbility: int(bits>>switchAbilityShift) & switchAbilityMask,
+	}
+}
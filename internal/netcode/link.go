@@ -0,0 +1,107 @@
+package netcode
+
+import (
+	"sort"
+	"time"
+
+	"github.com/remmakoshino/kirby-inspired-go/internal/rng"
+)
+
+// LinkConditions は疑似的に再現する回線の状態です
+type LinkConditions struct {
+	Latency time.Duration // 片道の遅延
+	Jitter  time.Duration // 遅延のゆらぎ（±Jitter の範囲でばらつく。順番の入れ替わりも起きる）
+	Loss    float64       // パケットが失われる確率（0〜1）
+	Seed    int64         // 損失とゆらぎの乱数シード
+}
+
+// delayedPacket は送信を遅らせているパケットです
+type delayedPacket struct {
+	sendAt time.Time
+	data   []byte
+}
+
+// LossyTransport は別のトランスポートを包み、遅延・ゆらぎ・パケットロスを加えます。
+// ループバックでネット対戦を検証するためのもので、同じゴルーチンから呼び出してください
+type LossyTransport struct {
+	Inner      Transport
+	Conditions LinkConditions
+	Clock      func() time.Time // 現在時刻（nil なら time.Now。テストで時間を決まった速さで進めるため）
+	
+	// 送信待ちのパケット（送信時刻順）
+	queue  []delayedPacket
+	random *rng.Source
+	
+	// 統計
+	Sent    int
+	Dropped int
+}
+
+// NewLossyTransport は回線の状態を再現するトランスポートを作成します
+func NewLossyTransport(inner Transport, conditions LinkConditions) *LossyTransport {
+	return &LossyTransport{
+		Inner:      inner,
+		Conditions: conditions,
+		random:     rng.New(conditions.Seed),
+	}
+}
+
+// Send はパケットを失わせるか、遅延をつけて送信待ちにします
+func (t *LossyTransport) Send(data []byte) error {
+	if t.random.Float64() < t.Conditions.Loss {
+		t.Dropped++
+		return t.flush()
+	}
+	
+	delay := t.Conditions.Latency
+	if t.Conditions.Jitter > 0 {
+		delay += time.Duration((t.random.Float64()*2 - 1) * float64(t.Conditions.Jitter))
+	}
+	if delay < 0 {
+		delay = 0
+	}
+	
+	t.queue = append(t.queue, delayedPacket{sendAt: t.now().Add(delay), data: data})
+	sort.SliceStable(t.queue, func(i, j int) bool {
+		return t.queue[i].sendAt.Before(t.queue[j].sendAt)
+	})
+	return t.flush()
+}
+
+// Receive は送信時刻になったパケットを送り出してから、届いたパケットを返します
+func (t *LossyTransport) Receive() [][]byte {
+	t.flush()
+	return t.Inner.Receive()
+}
+
+// now は現在時刻を返します
+func (t *LossyTransport) now() time.Time {
+	if t.Clock != nil {
+		return t.Clock()
+	}
+	return time.Now()
+}
+
+// flush は送信時刻になったパケットを実際に送信します
+func (t *LossyTransport) flush() error {
+	now := t.now()
+	sent := 0
+	for _, p := range t.queue {
+		if p.sendAt.After(now) {
+			break
+		}
+		if err := t.Inner.Send(p.data); err != nil {
+			t.queue = t.queue[sent:]
+			return err
+		}
+		t.Sent++
+		sent++
+	}
+	t.queue = t.queue[sent:]
+	return nil
+}
+
+// Close は内側のトランスポートを閉じます
+func (t *LossyTransport) Close() error {
+	return t.Inner.Close()
+}
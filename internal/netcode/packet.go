@@ -0,0 +1,79 @@
+package netcode
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// packetMagic はこのゲームのパケットであることを示す先頭バイトです
+const packetMagic = 0x4B
+
+// packetHeaderSize は入力列より前の固定長部分のバイト数です
+const packetHeaderSize = 26
+
+// maxInputsPerPacket は1パケットに詰める入力の最大数です
+const maxInputsPerPacket = 64
+
+// noFrame はフレーム番号がないことを表します
+const noFrame = -1
+
+// ErrBadPacket は壊れた・他のアプリのパケットを受け取った時のエラーです
+var ErrBadPacket = errors.New("netcode: malformed packet")
+
+// packet は相手に送る1回分のデータです。届いていない可能性のある入力を毎回まとめて再送します
+type packet struct {
+	Frame         int     // 送信側が次にシミュレートするフレーム
+	Ack           int     // 送信側が連続して受け取った相手の最新フレーム
+	Advantage     int     // 送信側から見た相手とのフレーム差
+	ChecksumFrame int     // Checksum が対応するフレーム（なければ noFrame）
+	Checksum      uint32  // 確定したフレームの状態のチェックサム
+	StartFrame    int     // Inputs[0] のフレーム
+	Inputs        []Input // 送信側の入力（StartFrame から連続）
+}
+
+// encodePacket はパケットをバイト列にします
+func encodePacket(p packet) []byte {
+	count := len(p.Inputs)
+	if count > maxInputsPerPacket {
+		count = maxInputsPerPacket
+	}
+	
+	buf := make([]byte, packetHeaderSize+count*2)
+	buf[0] = packetMagic
+	binary.BigEndian.PutUint32(buf[1:], uint32(int32(p.Frame)))
+	binary.BigEndian.PutUint32(buf[5:], uint32(int32(p.Ack)))
+	binary.BigEndian.PutUint32(buf[9:], uint32(int32(p.Advantage)))
+	binary.BigEndian.PutUint32(buf[13:], uint32(int32(p.ChecksumFrame)))
+	binary.BigEndian.PutUint32(buf[17:], p.Checksum)
+	binary.BigEndian.PutUint32(buf[21:], uint32(int32(p.StartFrame)))
+	buf[25] = byte(count)
+	for i := 0; i < count; i++ {
+		binary.BigEndian.PutUint16(buf[packetHeaderSize+i*2:], uint16(p.Inputs[i]))
+	}
+	return buf
+}
+
+// decodePacket はバイト列からパケットを読み取ります
+func decodePacket(buf []byte) (packet, error) {
+	if len(buf) < packetHeaderSize || buf[0] != packetMagic {
+		return packet{}, ErrBadPacket
+	}
+	count := int(buf[25])
+	if len(buf) != packetHeaderSize+count*2 {
+		return packet{}, ErrBadPacket
+	}
+	
+	p := packet{
+		Frame:         int(int32(binary.BigEndian.Uint32(buf[1:]))),
+		Ack:           int(int32(binary.BigEndian.Uint32(buf[5:]))),
+		Advantage:     int(int32(binary.BigEndian.Uint32(buf[9:]))),
+		ChecksumFrame: int(int32(binary.BigEndian.Uint32(buf[13:]))),
+		Checksum:      binary.BigEndian.Uint32(buf[17:]),
+		StartFrame:    int(int32(binary.BigEndian.Uint32(buf[21:]))),
+		Inputs:        make([]Input, count),
+	}
+	for i := range p.Inputs {
+		p.Inputs[i] = Input(binary.BigEndian.Uint16(buf[packetHeaderSize+i*2:]))
+	}
+	return p, nil
+}
@@ -0,0 +1,72 @@
+package netcode
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPacketRoundTrip(t *testing.T) {
+	full := make([]Input, maxInputsPerPacket)
+	for i := range full {
+		full[i] = Input(i*1031) | InputCreateHelper
+	}
+	
+	tests := []struct {
+		name string
+		p    packet
+	}{
+		{name: "no inputs", p: packet{
+			Frame:         0,
+			Ack:           noFrame,
+			Advantage:     -3,
+			ChecksumFrame: noFrame,
+			StartFrame:    2,
+			Inputs:        []Input{},
+		}},
+		{name: "full", p: packet{
+			Frame:         100000,
+			Ack:           99990,
+			Advantage:     4,
+			ChecksumFrame: 99980,
+			Checksum:      0xdeadbeef,
+			StartFrame:    99970,
+			Inputs:        full,
+		}},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodePacket(encodePacket(tt.p))
+			if err != nil {
+				t.Fatalf("decodePacket: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.p) {
+				t.Errorf("decoded %+v, want %+v", got, tt.p)
+			}
+		})
+	}
+}
+
+func TestDecodePacketRejectsBadData(t *testing.T) {
+	good := encodePacket(packet{Inputs: []Input{InputJump, InputAttack}})
+	wrongMagic := append([]byte(nil), good...)
+	wrongMagic[0]++
+	
+	tests := []struct {
+		name string
+		buf  []byte
+	}{
+		{name: "empty", buf: nil},
+		{name: "wrong magic", buf: wrongMagic},
+		{name: "truncated", buf: good[:len(good)-1]},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodePacket(tt.buf); !errors.Is(err, ErrBadPacket) {
+				t.Errorf("decodePacket = %v, want ErrBadPacket", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,389 @@
+package netcode
+
+import (
+	"errors"
+	"fmt"
+	"hash/crc32"
+)
+
+// Players はセッションに参加するプレイヤー数です
+const Players = 2
+
+// FixedStep はシミュレーション1フレームの長さ（秒）です。両方のマシンで同じ値を使います
+const FixedStep = 1.0 / 60
+
+const (
+	DefaultInputDelay  = 2  // 入力遅延の既定フレーム数
+	DefaultMaxRollback = 8  // 相手の入力を待たずに先行できる既定フレーム数
+	TimeSyncInterval   = 30 // 相手とのフレーム差を調整する間隔
+	historyFrames      = 120
+)
+
+// Simulation はロールバックできるゲームの世界です。
+// 同じ状態に同じ入力を与えれば必ず同じ結果になる（決定的である）必要があります
+type Simulation interface {
+	// Step はプレイヤー順の入力で1フレーム（FixedStep）進めます
+	Step(inputs []Input)
+	// SaveState は現在の状態をバイト列にします。同じ状態からは同じバイト列を返すこと
+	SaveState() ([]byte, error)
+	// LoadState は SaveState で保存した状態に戻します
+	LoadState(state []byte) error
+}
+
+// Config はセッションの設定です。InputDelay は両方のマシンで同じ値にします
+type Config struct {
+	LocalPlayer int // このマシンで操作するプレイヤー番号（0 または 1）
+	InputDelay  int // 入力を何フレーム遅らせて反映するか
+	MaxRollback int // 相手の入力を待たずに予測で先行できる最大フレーム数
+}
+
+// Stats はセッションの統計です
+type Stats struct {
+	Frames            int // 進めたフレーム数
+	Rollbacks         int // 巻き戻した回数
+	ResimulatedFrames int // 巻き戻しで再シミュレートしたフレーム数
+	Stalls            int // 相手の入力待ちで止まったフレーム数
+	SyncWaits         int // 相手とのフレーム差を縮めるために待ったフレーム数
+	PacketsSent       int
+	PacketsReceived   int
+	BadPackets        int
+}
+
+// ErrDesync は両方のマシンの状態がずれたことを表します
+var ErrDesync = errors.New("netcode: desync detected")
+
+// Session は2人のロールバックネット対戦を管理します
+type Session struct {
+	Config    Config
+	Stats     Stats
+	sim       Simulation
+	transport Transport
+	
+	frame int // 次にシミュレートするフレーム
+	
+	// 入力の履歴（フレーム番号 → 入力）
+	local     map[int]Input // 自分の入力
+	remote    map[int]Input // 届いた相手の入力
+	predicted map[int]Input // 相手の入力が届く前に予測で使った入力
+	
+	lastRemote      int // 連続して届いている相手の最新フレーム
+	remoteAck       int // 相手が連続して受け取った自分の最新フレーム
+	remoteFrame     int // 相手が最後に報告したフレーム
+	remoteAdvantage int // 相手から見たフレーム差
+	rollbackFrom    int // 巻き戻しが必要な最初のフレーム（不要なら noFrame）
+	
+	// 時間同期
+	lastSyncFrame int
+	syncWait      int
+	
+	// 状態とチェックサム
+	states          map[int][]byte // フレーム開始時の状態
+	checksums       map[int]uint32 // 入力が確定したフレームのチェックサム
+	remoteChecksums map[int]uint32 // 相手から届いたチェックサム
+	lastChecksum    int            // チェックサムを計算した最新フレーム
+	desyncFrame     int
+}
+
+// NewSession は新しいセッションを作成します。sim はフレーム0の状態にしておきます
+func NewSession(sim Simulation, transport Transport, cfg Config) (*Session, error) {
+	if cfg.LocalPlayer < 0 || cfg.LocalPlayer >= Players {
+		return nil, fmt.Errorf("netcode: invalid local player %d", cfg.LocalPlayer)
+	}
+	if cfg.InputDelay < 0 {
+		cfg.InputDelay = DefaultInputDelay
+	}
+	if cfg.MaxRollback <= 0 {
+		cfg.MaxRollback = DefaultMaxRollback
+	}
+	
+	s := &Session{
+		Config:          cfg,
+		sim:             sim,
+		transport:       transport,
+		local:           make(map[int]Input),
+		remote:          make(map[int]Input),
+		predicted:       make(map[int]Input),
+		lastRemote:      cfg.InputDelay - 1,
+		remoteAck:       cfg.InputDelay - 1,
+		rollbackFrom:    noFrame,
+		states:          make(map[int][]byte),
+		checksums:       make(map[int]uint32),
+		remoteChecksums: make(map[int]uint32),
+		lastChecksum:    noFrame,
+		desyncFrame:     noFrame,
+	}
+	return s, nil
+}
+
+// Frame は次にシミュレートするフレーム番号を返します
+func (s *Session) Frame() int {
+	return s.frame
+}
+
+// ConfirmedFrame は両方の入力が確定している最新フレームを返します
+func (s *Session) ConfirmedFrame() int {
+	return s.lastRemote
+}
+
+// Desynced は状態のずれを検出したかと、そのフレームを返します
+func (s *Session) Desynced() (int, bool) {
+	return s.desyncFrame, s.desyncFrame != noFrame
+}
+
+// Checksum は入力が確定したフレームの状態のチェックサムを返します
+func (s *Session) Checksum(frame int) (uint32, bool) {
+	sum, ok := s.checksums[frame]
+	return sum, ok
+}
+
+// AdvanceFrame は自分の入力を登録して1フレーム進めます。
+// 相手の入力を待つ必要がある場合は進めずに false を返します
+func (s *Session) AdvanceFrame(localInput Input) (bool, error) {
+	s.poll()
+	
+	// 予測が外れていたら巻き戻して再シミュレート
+	if s.rollbackFrom != noFrame {
+		if err := s.rollback(s.rollbackFrom); err != nil {
+			return false, err
+		}
+	}
+	
+	s.updateChecksums()
+	if _, desynced := s.Desynced(); desynced {
+		return false, ErrDesync
+	}
+	
+	// 相手より進みすぎたら待つ
+	if s.frame-s.lastRemote > s.Config.MaxRollback {
+		s.Stats.Stalls++
+		return false, s.send()
+	}
+	if s.waitForSync() {
+		s.Stats.SyncWaits++
+		return false, s.send()
+	}
+	
+	// 自分の入力は入力遅延の分だけ先のフレームに登録する
+	s.local[s.frame+s.Config.InputDelay] = localInput
+	if err := s.step(); err != nil {
+		return false, err
+	}
+	s.Stats.Frames++
+	
+	s.prune()
+	return true, s.send()
+}
+
+// Close は通信を終了します
+func (s *Session) Close() error {
+	return s.transport.Close()
+}
+
+// step は現在のフレームの状態を保存してから1フレーム進めます
+func (s *Session) step() error {
+	state, err := s.sim.SaveState()
+	if err != nil {
+		return err
+	}
+	s.states[s.frame] = state
+	s.sim.Step(s.inputsFor(s.frame))
+	s.frame++
+	return nil
+}
+
+// inputsFor はフレームの入力をプレイヤー順に返します。
+// 相手の入力がまだ届いていなければ、最後に届いた入力が続くと予測します
+func (s *Session) inputsFor(frame int) []Input {
+	inputs := make([]Input, Players)
+	remotePlayer := 1 - s.Config.LocalPlayer
+	
+	inputs[s.Config.LocalPlayer] = s.local[frame]
+	if frame <= s.lastRemote {
+		inputs[remotePlayer] = s.remote[frame]
+	} else {
+		guess := s.remote[s.lastRemote]
+		s.predicted[frame] = guess
+		inputs[remotePlayer] = guess
+	}
+	return inputs
+}
+
+// poll は届いたパケットを処理し、予測が外れていれば巻き戻し位置を記録します
+func (s *Session) poll() {
+	for _, data := range s.transport.Receive() {
+		p, err := decodePacket(data)
+		if err != nil {
+			s.Stats.BadPackets++
+			continue
+		}
+		s.Stats.PacketsReceived++
+		
+		if p.Frame > s.remoteFrame {
+			s.remoteFrame = p.Frame
+			s.remoteAdvantage = p.Advantage
+		}
+		if p.Ack > s.remoteAck {
+			s.remoteAck = p.Ack
+		}
+		if p.ChecksumFrame != noFrame {
+			s.remoteChecksums[p.ChecksumFrame] = p.Checksum
+		}
+		for i, in := range p.Inputs {
+			if f := p.StartFrame + i; f > s.lastRemote {
+				s.remote[f] = in
+			}
+		}
+	}
+	
+	// 連続して届いた分だけ確定させる
+	for {
+		f := s.lastRemote + 1
+		in, ok := s.remote[f]
+		if !ok {
+			break
+		}
+		if guess, wasPredicted := s.predicted[f]; wasPredicted && f < s.frame && guess != in {
+			if s.rollbackFrom == noFrame || f < s.rollbackFrom {
+				s.rollbackFrom = f
+			}
+		}
+		s.lastRemote = f
+	}
+}
+
+// rollback は from の状態に戻して現在のフレームまで再シミュレートします
+func (s *Session) rollback(from int) error {
+	s.rollbackFrom = noFrame
+	
+	state, ok := s.states[from]
+	if !ok {
+		return fmt.Errorf("netcode: no saved state for frame %d", from)
+	}
+	if err := s.sim.LoadState(state); err != nil {
+		return err
+	}
+	
+	target := s.frame
+	s.frame = from
+	for s.frame < target {
+		if err := s.step(); err != nil {
+			return err
+		}
+	}
+	
+	s.Stats.Rollbacks++
+	s.Stats.ResimulatedFrames += target - from
+	return nil
+}
+
+// updateChecksums は入力が確定したフレームのチェックサムを計算し、相手の値と比べます。
+// フレーム f の開始時の状態は、f より前の入力がすべて確定していれば両者で一致するはずです
+func (s *Session) updateChecksums() {
+	confirmed := s.lastRemote + 1
+	if confirmed > s.frame-1 {
+		confirmed = s.frame - 1
+	}
+	for f := s.lastChecksum + 1; f <= confirmed; f++ {
+		if state, ok := s.states[f]; ok {
+			s.checksums[f] = crc32.ChecksumIEEE(state)
+		}
+		s.lastChecksum = f
+	}
+	
+	for f, theirs := range s.remoteChecksums {
+		ours, ok := s.checksums[f]
+		if !ok {
+			continue
+		}
+		if ours != theirs && (s.desyncFrame == noFrame || f < s.desyncFrame) {
+			s.desyncFrame = f
+		}
+		delete(s.remoteChecksums, f)
+	}
+}
+
+// waitForSync は相手より先行しすぎていれば数フレーム待つかどうかを返します。
+// 双方が見たフレーム差の差を半分にすると、通信の遅延を打ち消した実際の差になります
+func (s *Session) waitForSync() bool {
+	if s.syncWait > 0 {
+		s.syncWait--
+		return true
+	}
+	if s.frame-s.lastSyncFrame < TimeSyncInterval {
+		return false
+	}
+	s.lastSyncFrame = s.frame
+	
+	advantage := s.frame - s.remoteFrame
+	if ahead := (advantage - s.remoteAdvantage) / 2; ahead >= 1 {
+		if ahead > s.Config.MaxRollback/2 {
+			ahead = s.Config.MaxRollback / 2
+		}
+		s.syncWait = ahead - 1
+		return true
+	}
+	return false
+}
+
+// send は相手がまだ受け取っていない自分の入力と、最新のチェックサムを送ります
+func (s *Session) send() error {
+	newest := s.frame - 1 + s.Config.InputDelay
+	start := s.remoteAck + 1
+	if newest-start+1 > maxInputsPerPacket {
+		start = newest - maxInputsPerPacket + 1
+	}
+	
+	p := packet{
+		Frame:         s.frame,
+		Ack:           s.lastRemote,
+		Advantage:     s.frame - s.remoteFrame,
+		ChecksumFrame: noFrame,
+		StartFrame:    start,
+	}
+	for f := start; f <= newest; f++ {
+		p.Inputs = append(p.Inputs, s.local[f])
+	}
+	if sum, ok := s.checksums[s.lastChecksum]; ok {
+		p.ChecksumFrame = s.lastChecksum
+		p.Checksum = sum
+	}
+	
+	s.Stats.PacketsSent++
+	return s.transport.Send(encodePacket(p))
+}
+
+// prune は巻き戻しにも再送にも使わなくなった古い履歴を捨てます
+func (s *Session) prune() {
+	oldest := s.frame - historyFrames
+	for f := range s.states {
+		// 状態は未確定のフレームの巻き戻しとチェックサム計算に使う
+		if f < oldest || f <= s.lastChecksum && f < s.lastRemote {
+			delete(s.states, f)
+		}
+	}
+	for f := range s.local {
+		if f < oldest && f <= s.remoteAck {
+			delete(s.local, f)
+		}
+	}
+	for f := range s.remote {
+		if f < oldest && f < s.lastRemote {
+			delete(s.remote, f)
+		}
+	}
+	for f := range s.predicted {
+		if f <= s.lastRemote {
+			delete(s.predicted, f)
+		}
+	}
+	for f := range s.checksums {
+		if f < oldest {
+			delete(s.checksums, f)
+		}
+	}
+	for f := range s.remoteChecksums {
+		if f < oldest {
+			delete(s.remoteChecksums, f)
+		}
+	}
+}
@@ -0,0 +1,305 @@
+package netcode
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/remmakoshino/kirby-inspired-go/internal/rng"
+)
+
+// frameDuration は1フレームの実時間です
+const frameDuration = time.Second / 60
+
+// fakeSim は入力を畳み込むだけの決定的なシミュレーションです
+type fakeSim struct {
+	frame int32
+	pos   [Players]int32
+	hash  uint32
+	
+	desyncAt int32 // このフレームを進めた時だけ状態をずらす（ずらさないなら -1）
+}
+
+func newFakeSim() *fakeSim {
+	return &fakeSim{desyncAt: -1}
+}
+
+func (s *fakeSim) Step(inputs []Input) {
+	for p, in := range inputs {
+		if in&InputLeft != 0 {
+			s.pos[p]--
+		}
+		if in&InputRight != 0 {
+			s.pos[p]++
+		}
+		s.hash = s.hash*16777619 ^ uint32(in)<<uint(p)
+	}
+	if s.frame == s.desyncAt {
+		s.hash ^= 0xdead
+	}
+	s.frame++
+}
+
+func (s *fakeSim) SaveState() ([]byte, error) {
+	buf := make([]byte, 16)
+	binary.BigEndian.PutUint32(buf[0:], uint32(s.frame))
+	binary.BigEndian.PutUint32(buf[4:], uint32(s.pos[0]))
+	binary.BigEndian.PutUint32(buf[8:], uint32(s.pos[1]))
+	binary.BigEndian.PutUint32(buf[12:], s.hash)
+	return buf, nil
+}
+
+func (s *fakeSim) LoadState(buf []byte) error {
+	if len(buf) != 16 {
+		return errors.New("fakeSim: bad state")
+	}
+	s.frame = int32(binary.BigEndian.Uint32(buf[0:]))
+	s.pos[0] = int32(binary.BigEndian.Uint32(buf[4:]))
+	s.pos[1] = int32(binary.BigEndian.Uint32(buf[8:]))
+	s.hash = binary.BigEndian.Uint32(buf[12:])
+	return nil
+}
+
+// memTransport はメモリ上で相手に直接渡すトランスポートです
+type memTransport struct {
+	peer  *memTransport
+	inbox [][]byte
+}
+
+// memPipe はつながった2つのトランスポートを作ります
+func memPipe() (*memTransport, *memTransport) {
+	a, b := &memTransport{}, &memTransport{}
+	a.peer, b.peer = b, a
+	return a, b
+}
+
+func (t *memTransport) Send(data []byte) error {
+	t.peer.inbox = append(t.peer.inbox, append([]byte(nil), data...))
+	return nil
+}
+
+func (t *memTransport) Receive() [][]byte {
+	received := t.inbox
+	t.inbox = nil
+	return received
+}
+
+func (t *memTransport) Close() error {
+	return nil
+}
+
+// testClock はテストで1フレームずつ進める時計です
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+// scriptedInput はプレイヤーがそのフレームに押す入力です。6フレームごとに変わるので予測が外れます
+func scriptedInput(player, frame int) Input {
+	r := rng.New(int64(player)<<32 | int64(frame/6))
+	return Input(r.Intn(1 << 11))
+}
+
+// testPeer はセッション1つと、確定したチェックサムの記録です
+type testPeer struct {
+	sim       *fakeSim
+	session   *Session
+	link      *LossyTransport
+	checksums map[int]uint32
+}
+
+// tick は台本どおりの入力で1回 AdvanceFrame を呼び、確定したチェックサムを記録します
+func (p *testPeer) tick() error {
+	frame := p.session.Frame()
+	_, err := p.session.AdvanceFrame(scriptedInput(p.session.Config.LocalPlayer, frame))
+	for f := len(p.checksums); ; f++ {
+		sum, ok := p.session.Checksum(f)
+		if !ok {
+			break
+		}
+		p.checksums[f] = sum
+	}
+	return err
+}
+
+// testMatch は同じ回線でつながった2人分のセッションです
+type testMatch struct {
+	clock *testClock
+	peers [Players]*testPeer
+}
+
+func newTestMatch(t *testing.T, conditions LinkConditions, cfg Config) *testMatch {
+	t.Helper()
+	m := &testMatch{clock: &testClock{now: time.Unix(0, 0)}}
+	a, b := memPipe()
+	for i, inner := range []*memTransport{a, b} {
+		link := NewLossyTransport(inner, conditions)
+		link.Clock = m.clock.Now
+		conditions.Seed++
+		
+		cfg.LocalPlayer = i
+		sim := newFakeSim()
+		session, err := NewSession(sim, link, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m.peers[i] = &testPeer{sim: sim, session: session, link: link, checksums: make(map[int]uint32)}
+	}
+	return m
+}
+
+// run は両者が frames フレームまで進むか、どちらかがエラーを返すまで時計を進めます
+func (m *testMatch) run(t *testing.T, frames int) error {
+	t.Helper()
+	for tick := 0; tick < frames*10; tick++ {
+		if m.peers[0].session.Frame() >= frames && m.peers[1].session.Frame() >= frames {
+			return nil
+		}
+		m.clock.now = m.clock.now.Add(frameDuration)
+		for _, p := range m.peers {
+			if err := p.tick(); err != nil {
+				return err
+			}
+		}
+	}
+	t.Fatalf("stuck at frames %d and %d, want %d", m.peers[0].session.Frame(), m.peers[1].session.Frame(), frames)
+	return nil
+}
+
+// offlineChecksums は通信せずに全員の入力を知っている場合の、各フレーム開始時のチェックサムです
+func offlineChecksums(inputDelay, frames int) map[int]uint32 {
+	sim := newFakeSim()
+	sums := make(map[int]uint32)
+	for f := 0; f < frames; f++ {
+		state, _ := sim.SaveState()
+		sums[f] = crc32.ChecksumIEEE(state)
+		
+		inputs := make([]Input, Players)
+		if f >= inputDelay {
+			for p := range inputs {
+				inputs[p] = scriptedInput(p, f-inputDelay)
+			}
+		}
+		sim.Step(inputs)
+	}
+	return sums
+}
+
+// checkConfirmed は両者が確定させたチェックサムが、入力をすべて知っている場合と一致するかを調べます
+func checkConfirmed(t *testing.T, m *testMatch, frames int) {
+	t.Helper()
+	want := offlineChecksums(m.peers[0].session.Config.InputDelay, frames)
+	for i, p := range m.peers {
+		if len(p.checksums) < frames/2 {
+			t.Errorf("peer %d confirmed only %d of %d frames", i, len(p.checksums), frames)
+		}
+		for f, sum := range p.checksums {
+			if f < frames && sum != want[f] {
+				t.Fatalf("peer %d: checksum of frame %d = %08x, want %08x", i, f, sum, want[f])
+			}
+		}
+	}
+}
+
+func TestSessionRollsBackMispredictions(t *testing.T) {
+	const frames = 240
+	m := newTestMatch(t, LinkConditions{Latency: 3 * frameDuration}, Config{InputDelay: 1})
+	if err := m.run(t, frames); err != nil {
+		t.Fatal(err)
+	}
+	
+	for i, p := range m.peers {
+		if p.session.Stats.Rollbacks == 0 || p.session.Stats.ResimulatedFrames == 0 {
+			t.Errorf("peer %d never rolled back: %+v", i, p.session.Stats)
+		}
+	}
+	checkConfirmed(t, m, frames)
+}
+
+func TestSessionSurvivesLossAndJitter(t *testing.T) {
+	const frames = 300
+	conditions := LinkConditions{
+		Latency: 4 * frameDuration,
+		Jitter:  3 * frameDuration,
+		Loss:    0.25,
+		Seed:    7,
+	}
+	
+	var stats [2][Players]Stats
+	for run := range stats {
+		m := newTestMatch(t, conditions, Config{InputDelay: 2})
+		if err := m.run(t, frames); err != nil {
+			t.Fatal(err)
+		}
+		for i, p := range m.peers {
+			if p.link.Dropped == 0 {
+				t.Errorf("peer %d: no packets were dropped", i)
+			}
+			stats[run][i] = p.session.Stats
+		}
+		checkConfirmed(t, m, frames)
+	}
+	
+	// 同じシードなら、損失と並び替えも含めて毎回同じ展開になる
+	if !reflect.DeepEqual(stats[0], stats[1]) {
+		t.Errorf("runs with the same seed differ:\n%+v\n%+v", stats[0], stats[1])
+	}
+}
+
+func TestSessionStallsWithoutRemoteInput(t *testing.T) {
+	const frames = 120
+	cfg := Config{InputDelay: 2, MaxRollback: 8}
+	m := newTestMatch(t, LinkConditions{}, cfg)
+	
+	// 相手が動いていない間は、入力遅延と先行できるフレーム数の分だけ進んで止まる
+	local := m.peers[0]
+	for i := 0; i < 30; i++ {
+		m.clock.now = m.clock.now.Add(frameDuration)
+		if err := local.tick(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := local.session.Frame(), cfg.InputDelay+cfg.MaxRollback; got != want {
+		t.Errorf("ran ahead to frame %d, want to stop at %d", got, want)
+	}
+	if got, want := local.session.Stats.Stalls, 30-local.session.Frame(); got != want {
+		t.Errorf("Stalls = %d, want %d", got, want)
+	}
+	
+	// 相手が動き出せば追いつき、同じ結果になる
+	if err := m.run(t, frames); err != nil {
+		t.Fatal(err)
+	}
+	checkConfirmed(t, m, frames)
+}
+
+func TestSessionDetectsDesync(t *testing.T) {
+	const desyncAt = 30
+	m := newTestMatch(t, LinkConditions{Latency: 2 * frameDuration}, Config{InputDelay: 2})
+	m.peers[1].sim.desyncAt = desyncAt
+	
+	err := m.run(t, 120)
+	if !errors.Is(err, ErrDesync) {
+		t.Fatalf("run = %v, want ErrDesync", err)
+	}
+	detected := false
+	for i, p := range m.peers {
+		frame, ok := p.session.Desynced()
+		if !ok {
+			continue
+		}
+		detected = true
+		if frame <= desyncAt {
+			t.Errorf("peer %d reported a desync at frame %d, before the states diverged", i, frame)
+		}
+	}
+	if !detected {
+		t.Error("no peer reported the desync")
+	}
+}
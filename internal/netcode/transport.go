@@ -0,0 +1,109 @@
+package netcode
+
+import (
+	"net"
+)
+
+// Transport はパケットを相手に届ける手段です。
+// Receive はブロックせず、届いているパケットをすべて返します
+type Transport interface {
+	Send(data []byte) error
+	Receive() [][]byte
+	Close() error
+}
+
+// receiveQueueSize は受信済みで未処理のパケットをためておける数です
+const receiveQueueSize = 256
+
+// maxPacketSize は受信バッファの大きさです
+const maxPacketSize = 1024
+
+// UDPTransport はUDPでパケットを送受信します
+type UDPTransport struct {
+	conn     *net.UDPConn
+	remote   *net.UDPAddr
+	received chan []byte
+}
+
+// NewUDPTransport は local（例: ":7000"）で待ち受けるUDPトランスポートを作成します。
+// 送信先は Connect で設定します
+func NewUDPTransport(local string) (*UDPTransport, error) {
+	addr, err := net.ResolveUDPAddr("udp", local)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.ListenUDP("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	
+	t := &UDPTransport{
+		conn:     conn,
+		received: make(chan []byte, receiveQueueSize),
+	}
+	go t.readLoop()
+	return t, nil
+}
+
+// Connect は送信先（例: "192.168.0.2:7000"）を設定します
+func (t *UDPTransport) Connect(remote string) error {
+	addr, err := net.ResolveUDPAddr("udp", remote)
+	if err != nil {
+		return err
+	}
+	t.remote = addr
+	return nil
+}
+
+// LocalAddr は待ち受けているアドレスを返します
+func (t *UDPTransport) LocalAddr() string {
+	return t.conn.LocalAddr().String()
+}
+
+// readLoop は受信したパケットをキューに積みます（あふれた分は捨てる）
+func (t *UDPTransport) readLoop() {
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, _, err := t.conn.ReadFromUDP(buf)
+		if err != nil {
+			close(t.received)
+			return
+		}
+		data := make([]byte, n)
+		copy(data, buf[:n])
+		select {
+		case t.received <- data:
+		default:
+		}
+	}
+}
+
+// Send はパケットを送信します
+func (t *UDPTransport) Send(data []byte) error {
+	if t.remote == nil {
+		return nil
+	}
+	_, err := t.conn.WriteToUDP(data, t.remote)
+	return err
+}
+
+// Receive は届いているパケットをすべて取り出します
+func (t *UDPTransport) Receive() [][]byte {
+	var packets [][]byte
+	for {
+		select {
+		case data, ok := <-t.received:
+			if !ok {
+				return packets
+			}
+			packets = append(packets, data)
+		default:
+			return packets
+		}
+	}
+}
+
+// Close は接続を閉じます
+func (t *UDPTransport) Close() error {
+	return t.conn.Close()
+}
@@ -0,0 +1,73 @@
+// Package rng はゲーム内で使う決定的な乱数を提供します。
+// 状態を1つの整数として取り出し・復元できるので、スナップショットや
+// ロールバックで巻き戻しても同じ乱数列を再現できます
+package rng
+
+// Source は xorshift64* による乱数生成器です
+type Source struct {
+	State uint64
+}
+
+// New はシードから乱数生成器を作成します
+func New(seed int64) *Source {
+	s := &Source{}
+	s.Seed(seed)
+	return s
+}
+
+// Seed はシードを設定します（0 は使えないので別の値に置き換えます）
+func (s *Source) Seed(seed int64) {
+	s.State = uint64(seed)
+	if s.State == 0 {
+		s.State = 0x9E3779B97F4A7C15
+	}
+}
+
+// Uint64 は次の乱数を返します
+func (s *Source) Uint64() uint64 {
+	s.State ^= s.State >> 12
+	s.State ^= s.State << 25
+	s.State ^= s.State >> 27
+	return s.State * 0x2545F4914F6CDD1D
+}
+
+// Float64 は [0, 1) の乱数を返します
+func (s *Source) Float64() float64 {
+	return float64(s.Uint64()>>11) / (1 << 53)
+}
+
+// Intn は [0, n) の乱数を返します
+func (s *Source) Intn(n int) int {
+	if n <= 0 {
+		panic("rng: invalid argument to Intn")
+	}
+	return int(s.Uint64() % uint64(n))
+}
+
+// global はゲーム全体で共有する乱数生成器です
+var global = New(1)
+
+// Seed は共有の乱数生成器にシードを設定します
+func Seed(seed int64) {
+	global.Seed(seed)
+}
+
+// Float64 は共有の乱数生成器から [0, 1) の乱数を返します
+func Float64() float64 {
+	return global.Float64()
+}
+
+// Intn は共有の乱数生成器から [0, n) の乱数を返します
+func Intn(n int) int {
+	return global.Intn(n)
+}
+
+// State は共有の乱数生成器の状態を返します
+func State() uint64 {
+	return global.State
+}
+
+// SetState は共有の乱数生成器の状態を復元します
+func SetState(state uint64) {
+	global.State = state
+}
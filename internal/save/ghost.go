@@ -0,0 +1,80 @@
+package save
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrNoGhost はステージのゴーストが保存されていない時のエラーです
+var ErrNoGhost = errors.New("save: no ghost for stage")
+
+// GhostFrame はゴーストの1コマ分の位置です
+type GhostFrame struct {
+	Room int
+	X, Y float64
+}
+
+// Ghost はタイムアタックの自己ベストの走りの位置の記録です。
+// 大きくなるのでセーブデータとは別のファイルに、スロットとステージごとに保存します
+type Ghost struct {
+	Stage     int
+	Character string
+	Frames    int          // ゴールまでのフレーム数（TimeAttackRecord.Frames と同じなら自己ベストのゴースト）
+	Every     int          // 何フレームごとに位置を記録したか
+	Trace     []GhostFrame // 1Pの位置（Every フレームごと）
+}
+
+// ghostPath はスロットとステージのゴーストのファイルのパスを返します
+func (s *Store) ghostPath(slot, stage int) string {
+	return filepath.Join(s.Dir, fmt.Sprintf("slot%d-ghost%d.json", slot+1, stage))
+}
+
+// LoadGhost はスロットに保存したステージのゴーストを読み込みます
+func (s *Store) LoadGhost(slot, stage int) (*Ghost, error) {
+	if err := checkSlot(slot); err != nil {
+		return nil, err
+	}
+	raw, err := os.ReadFile(s.ghostPath(slot, stage))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNoGhost
+	}
+	if err != nil {
+		return nil, err
+	}
+	
+	var g Ghost
+	if err := json.Unmarshal(raw, &g); err != nil {
+		return nil, err
+	}
+	if g.Stage != stage || g.Every <= 0 || len(g.Trace) == 0 {
+		return nil, fmt.Errorf("save: invalid ghost for stage %d", stage)
+	}
+	return &g, nil
+}
+
+// SaveGhost はステージのゴーストを書き込みます（前のゴーストは置き換えます）
+func (s *Store) SaveGhost(slot int, g *Ghost) error {
+	if err := checkSlot(slot); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return err
+	}
+	
+	raw, err := json.Marshal(g)
+	if err != nil {
+		return err
+	}
+	tmp, err := s.writeTemp(fmt.Sprintf("slot%d-ghost-*.tmp", slot+1), raw)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, s.ghostPath(slot, g.Stage)); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package save
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// migration はセーブデータを1つ新しいバージョンの形式に変換します
+type migration func(fields map[string]json.RawMessage) error
+
+// migrations は変換元のバージョン順に並べた変換処理です（migrations[n] はバージョン n → n+1）。
+// 形式を変えたら CurrentVersion を上げ、ここに変換処理を追加してください
+var migrations = []migration{
+	migrateV0,
+	migrateV1,
+}
+
+// migrateV0 はバージョンの書かれていないファイルに、足りない項目の既定値を補います
+func migrateV0(fields map[string]json.RawMessage) error {
+	if _, ok := fields["Unlocked"]; !ok {
+		fields["Unlocked"] = json.RawMessage("[1]")
+	}
+	return nil
+}
+
+// v1StageNumbers はワールドマップができる前のステージ番号と、今のボスステージの番号の対応です
+var v1StageNumbers = map[string]string{
+	"1": "4", // デデデ城
+	"2": "8", // ハルバードのブリッジ
+}
+
+// migrateV1 はステージ番号をワールドマップの番号に付け替えます。
+// 遊べるステージはクリアの記録から決まるようになったので Unlocked は捨てます
+func migrateV1(fields map[string]json.RawMessage) error {
+	delete(fields, "Unlocked")
+	
+	raw, ok := fields["Stages"]
+	if !ok {
+		return nil
+	}
+	var stages map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &stages); err != nil {
+		return err
+	}
+	renumbered := make(map[string]json.RawMessage, len(stages))
+	for stage, record := range stages {
+		if to, ok := v1StageNumbers[stage]; ok {
+			stage = to
+		}
+		renumbered[stage] = record
+	}
+	
+	data, err := json.Marshal(renumbered)
+	if err != nil {
+		return err
+	}
+	fields["Stages"] = data
+	return nil
+}
+
+// decode はセーブファイルの中身を読み取り、現在の形式に変換します
+func decode(raw []byte) (*Data, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	if fields == nil {
+		return nil, fmt.Errorf("save: empty save data")
+	}
+	
+	version := 0
+	if v, ok := fields["Version"]; ok {
+		if err := json.Unmarshal(v, &version); err != nil {
+			return nil, err
+		}
+	}
+	if version > CurrentVersion {
+		return nil, fmt.Errorf("%w: %d", ErrVersion, version)
+	}
+	if version < 0 || version > len(migrations) {
+		return nil, fmt.Errorf("save: unknown version %d", version)
+	}
+	
+	for v := version; v < CurrentVersion; v++ {
+		if err := migrations[v](fields); err != nil {
+			return nil, fmt.Errorf("save: migrating from version %d: %v", v, err)
+		}
+	}
+	migrated, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	
+	d := New()
+	if err := json.Unmarshal(migrated, d); err != nil {
+		return nil, err
+	}
+	d.Version = CurrentVersion
+	d.normalize()
+	return d, nil
+}
+
+// normalize は欠けている項目を補い、ありえない値を直します
+func (d *Data) normalize() {
+	if d.Stages == nil {
+		d.Stages = make(map[int]*StageRecord)
+	}
+	for stage, r := range d.Stages {
+		if r == nil {
+			delete(d.Stages, stage)
+		}
+	}
+	if d.Items == nil {
+		d.Items = make(map[string]int)
+	}
+	if d.PlayTime < 0 {
+		d.PlayTime = 0
+	}
+	if d.SurvivalBestWave < 0 {
+		d.SurvivalBestWave = 0
+	}
+	if d.ArenaBestTimes == nil {
+		d.ArenaBestTimes = make(map[string]float64)
+	}
+	for character, t := range d.ArenaBestTimes {
+		if t <= 0 {
+			delete(d.ArenaBestTimes, character)
+		}
+	}
+	if d.TimeAttack == nil {
+		d.TimeAttack = make(map[int]*TimeAttackRecord)
+	}
+	for stage, r := range d.TimeAttack {
+		if r == nil || r.Frames <= 0 {
+			delete(d.TimeAttack, stage)
+		}
+	}
+	if d.Characters == nil {
+		d.Characters = make(map[string]*CharacterProgress)
+	}
+	for character, p := range d.Characters {
+		if p == nil {
+			delete(d.Characters, character)
+			continue
+		}
+		if p.Level < 1 {
+			p.Level = 1
+		}
+		if p.Exp < 0 {
+			p.Exp = 0
+		}
+	}
+	if d.Inventory == nil {
+		d.Inventory = make(map[string]int)
+	}
+	for id, n := range d.Inventory {
+		if n <= 0 {
+			delete(d.Inventory, id)
+		}
+	}
+	if d.Equipment == nil {
+		d.Equipment = make(map[string]map[string]string)
+	}
+	for character, slots := range d.Equipment {
+		if slots == nil {
+			delete(d.Equipment, character)
+		}
+	}
+	if d.Coins < 0 {
+		d.Coins = 0
+	}
+}
@@ -0,0 +1,350 @@
+// Package save はストーリーの進行状況をセーブスロットのファイルに保存します。
+// ファイルはユーザー設定ディレクトリの下に置き、書き込みは一時ファイルからの
+// 置き換えで行うので、途中で終了しても壊れたファイルが残りません
+package save
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	Slots          = 3                  // セーブスロットの数
+	CurrentVersion = 2                  // セーブデータの形式のバージョン
+	DirName        = "kirby-inspired-go" // ユーザー設定ディレクトリの下のフォルダ名
+)
+
+var (
+	// ErrNoSave はスロットにセーブデータがない時のエラーです
+	ErrNoSave = errors.New("save: slot is empty")
+	// ErrCorrupted はセーブデータもバックアップも読めない時のエラーです
+	ErrCorrupted = errors.New("save: save data is corrupted")
+	// ErrVersion はこのバージョンより新しいゲームで保存されたデータを読もうとした時のエラーです
+	ErrVersion = errors.New("save: save data is from a newer version")
+)
+
+// StageRecord はステージごとの記録です
+type StageRecord struct {
+	Cleared   bool
+	BestScore int
+	BestTime  float64 // 最速クリア時間（秒、未クリアは0）
+}
+
+// FrameTime は記録のフレーム1つの長さ（秒）です。シミュレーションの固定フレーム（netcode.FixedStep）と同じ値にします
+const FrameTime = 1.0 / 60
+
+// FrameSeconds はフレーム数を秒数にします
+func FrameSeconds(frames int) float64 {
+	return float64(frames) * FrameTime
+}
+
+// TimeAttackRecord はステージごとのタイムアタックの自己ベストです。
+// 時間はシミュレーションの固定フレームの数で持つので、描画の速さによらず同じ操作なら同じ記録になります
+type TimeAttackRecord struct {
+	Frames    int    // ゴールまでのフレーム数
+	Splits    []int  // 中間ポイントに触れた時のフレーム数（触れた順）
+	Character string // 記録したキャラクターのID
+}
+
+// CharacterProgress はキャラクターごとのレベルと経験値です
+type CharacterProgress struct {
+	Level int
+	Exp   int // 今のレベルになってから貯めた経験値
+}
+
+// Settings はセーブデータと一緒に保存する設定です
+type Settings struct {
+	VersusRule    int
+	VersusStocks  int
+	VersusMinutes int
+}
+
+// Data は1スロット分のセーブデータです
+type Data struct {
+	Version          int
+	Stages           map[int]*StageRecord // ステージごとの記録（どの扉が開くかはワールドマップがここから決める）
+	Items            map[string]int       // 集めたアイテムの数（種類ごと）
+	Settings         Settings
+	SurvivalBestWave int                           // サバイバルでたどり着いた一番先の波（遊んでいなければ0）
+	ArenaBestTimes   map[string]float64            // ボスラッシュの最速クリア時間（1Pのキャラクターごと、秒）
+	TimeAttack       map[int]*TimeAttackRecord     // ステージごとのタイムアタックの自己ベスト（ゴーストは別のファイル）
+	Characters       map[string]*CharacterProgress // キャラクターごとのレベルと経験値
+	Inventory        map[string]int                // 持ち物の数（アイテムID → 数、装備中のものは含まない）
+	Equipment        map[string]map[string]string  // キャラクターごとの装備（キャラクターID → 枠 → アイテムID）
+	Coins            int                           // 持っているコイン
+	Abilities        []string                      // 店で覚えたコピー能力（覚えた順）
+	PlayTime         float64                       // 合計プレイ時間（秒）
+	UpdatedAt        time.Time
+}
+
+// New は新しいセーブデータを作成します
+func New() *Data {
+	return &Data{
+		Version:        CurrentVersion,
+		Stages:         make(map[int]*StageRecord),
+		Items:          make(map[string]int),
+		Settings:       Settings{VersusStocks: 3, VersusMinutes: 2},
+		ArenaBestTimes: make(map[string]float64),
+		TimeAttack:     make(map[int]*TimeAttackRecord),
+		Characters:     make(map[string]*CharacterProgress),
+		Inventory:      make(map[string]int),
+		Equipment:      make(map[string]map[string]string),
+	}
+}
+
+// Cleared はステージをクリアしたかを返します
+func (d *Data) Cleared(stage int) bool {
+	r, ok := d.Stages[stage]
+	return ok && r.Cleared
+}
+
+// Record はステージの記録を返します（まだなければ作成します）
+func (d *Data) Record(stage int) *StageRecord {
+	r, ok := d.Stages[stage]
+	if !ok {
+		r = &StageRecord{}
+		d.Stages[stage] = r
+	}
+	return r
+}
+
+// RecordClear はステージのクリアを記録し、スコアと時間が良ければ更新します
+func (d *Data) RecordClear(stage, score int, clearTime float64) {
+	r := d.Record(stage)
+	r.Cleared = true
+	if score > r.BestScore {
+		r.BestScore = score
+	}
+	if r.BestTime == 0 || clearTime < r.BestTime {
+		r.BestTime = clearTime
+	}
+}
+
+// ClearedCount はクリアしたステージの数を返します
+func (d *Data) ClearedCount() int {
+	count := 0
+	for _, r := range d.Stages {
+		if r.Cleared {
+			count++
+		}
+	}
+	return count
+}
+
+// RecordSurvival はサバイバルでたどり着いた波を記録し、記録を更新したかを返します
+func (d *Data) RecordSurvival(wave int) bool {
+	if wave <= d.SurvivalBestWave {
+		return false
+	}
+	d.SurvivalBestWave = wave
+	return true
+}
+
+// RecordArena はボスラッシュのクリア時間をキャラクターごとに記録し、記録を更新したかを返します
+func (d *Data) RecordArena(character string, clearTime float64) bool {
+	best, ok := d.ArenaBestTimes[character]
+	if ok && best <= clearTime {
+		return false
+	}
+	d.ArenaBestTimes[character] = clearTime
+	return true
+}
+
+// RecordTimeAttack はタイムアタックのゴールを記録し、自己ベストを更新したかを返します
+func (d *Data) RecordTimeAttack(stage int, character string, frames int, splits []int) bool {
+	if best, ok := d.TimeAttack[stage]; ok && best.Frames <= frames {
+		return false
+	}
+	d.TimeAttack[stage] = &TimeAttackRecord{
+		Frames:    frames,
+		Splits:    append([]int(nil), splits...),
+		Character: character,
+	}
+	return true
+}
+
+// Progress はキャラクターのレベルと経験値を返します（まだなければレベル1で作成します）
+func (d *Data) Progress(character string) *CharacterProgress {
+	p, ok := d.Characters[character]
+	if !ok {
+		p = &CharacterProgress{Level: 1}
+		d.Characters[character] = p
+	}
+	return p
+}
+
+// CollectItem は集めたアイテムを数えます
+func (d *Data) CollectItem(kind string) {
+	d.Items[kind]++
+}
+
+// Store はセーブファイルを置くディレクトリです
+type Store struct {
+	Dir string
+}
+
+// NewStore は dir にセーブファイルを置く Store を作成します
+func NewStore(dir string) *Store {
+	return &Store{Dir: dir}
+}
+
+// DefaultStore はユーザー設定ディレクトリ（Linux なら ~/.config/kirby-inspired-go）の Store を返します
+func DefaultStore() (*Store, error) {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	return NewStore(filepath.Join(base, DirName)), nil
+}
+
+// path はスロットのファイルのパスを返します
+func (s *Store) path(slot int) string {
+	return filepath.Join(s.Dir, fmt.Sprintf("slot%d.json", slot+1))
+}
+
+// backupPath は1つ前に保存したファイルのパスを返します
+func (s *Store) backupPath(slot int) string {
+	return s.path(slot) + ".bak"
+}
+
+// checkSlot はスロット番号が範囲内かを確かめます
+func checkSlot(slot int) error {
+	if slot < 0 || slot >= Slots {
+		return fmt.Errorf("save: invalid slot %d", slot)
+	}
+	return nil
+}
+
+// Load はスロットのセーブデータを読み込みます。
+// ファイルが壊れていれば1つ前に保存したバックアップから読み込みます
+func (s *Store) Load(slot int) (*Data, error) {
+	if err := checkSlot(slot); err != nil {
+		return nil, err
+	}
+	
+	data, err := readFile(s.path(slot))
+	if err == nil {
+		return data, nil
+	}
+	if errors.Is(err, ErrVersion) {
+		return nil, err
+	}
+	
+	backup, backupErr := readFile(s.backupPath(slot))
+	if backupErr == nil {
+		return backup, nil
+	}
+	if errors.Is(err, os.ErrNotExist) && errors.Is(backupErr, os.ErrNotExist) {
+		return nil, ErrNoSave
+	}
+	return nil, fmt.Errorf("%w: %v", ErrCorrupted, err)
+}
+
+// readFile はセーブファイルを読み込み、古い形式なら現在の形式に変換します
+func readFile(path string) (*Data, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return decode(raw)
+}
+
+// Save はスロットにセーブデータを書き込みます。
+// 一時ファイルに書いてから置き換え、それまでのファイルはバックアップとして残します
+func (s *Store) Save(slot int, d *Data) error {
+	if err := checkSlot(slot); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return err
+	}
+	
+	d.Version = CurrentVersion
+	d.UpdatedAt = time.Now()
+	raw, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	
+	tmp, err := s.writeTemp(fmt.Sprintf("slot%d-*.tmp", slot+1), raw)
+	if err != nil {
+		return err
+	}
+	
+	// 読めるファイルだけをバックアップにする（壊れたファイルで良いバックアップを上書きしない）
+	if _, err := readFile(s.path(slot)); err == nil {
+		if err := os.Rename(s.path(slot), s.backupPath(slot)); err != nil {
+			os.Remove(tmp)
+			return err
+		}
+	}
+	if err := os.Rename(tmp, s.path(slot)); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// writeTemp は raw を書き込んだ一時ファイルを作成し、そのパスを返します（失敗したら一時ファイルは残しません）
+func (s *Store) writeTemp(pattern string, raw []byte) (string, error) {
+	tmp, err := os.CreateTemp(s.Dir, pattern)
+	if err != nil {
+		return "", err
+	}
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), nil
+}
+
+// Delete はスロットのセーブデータとバックアップ、タイムアタックのゴーストを削除します
+func (s *Store) Delete(slot int) error {
+	if err := checkSlot(slot); err != nil {
+		return err
+	}
+	ghosts, err := filepath.Glob(filepath.Join(s.Dir, fmt.Sprintf("slot%d-ghost*.json", slot+1)))
+	if err != nil {
+		return err
+	}
+	for _, path := range append([]string{s.path(slot), s.backupPath(slot)}, ghosts...) {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// SlotInfo はスロット選択画面に表示するスロットの状態です
+type SlotInfo struct {
+	Slot int
+	Data *Data // 空のスロットは nil
+	Err  error // 読み込めなかった場合のエラー（空のスロットは nil）
+}
+
+// List はすべてのスロットの状態を返します
+func (s *Store) List() []SlotInfo {
+	infos := make([]SlotInfo, Slots)
+	for slot := range infos {
+		data, err := s.Load(slot)
+		if errors.Is(err, ErrNoSave) {
+			err = nil
+		}
+		infos[slot] = SlotInfo{Slot: slot, Data: data, Err: err}
+	}
+	return infos
+}
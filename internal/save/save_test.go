@@ -0,0 +1,157 @@
+package save
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeSlot はスロットのファイルに raw をそのまま書き込みます
+func writeSlot(t *testing.T, s *Store, slot int, raw string) {
+	t.Helper()
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(s.path(slot), []byte(raw), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadMigratesOldVersions(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{
+			// バージョンのないワールドマップ以前のファイル（ステージ1がデデデ城、2がハルバード）
+			name: "v0",
+			raw: `{
+				"Stages": {"1": {"Cleared": true, "BestScore": 1200, "BestTime": 95.5}, "2": {"Cleared": true, "BestScore": 800}},
+				"Items": {"MaxTomato": 2},
+				"PlayTime": 360
+			}`,
+		},
+		{
+			name: "v1",
+			raw: `{
+				"Version": 1,
+				"Unlocked": [1, 2],
+				"Stages": {"1": {"Cleared": true, "BestScore": 1200, "BestTime": 95.5}, "2": {"Cleared": true, "BestScore": 800}},
+				"Items": {"MaxTomato": 2},
+				"PlayTime": 360
+			}`,
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewStore(t.TempDir())
+			writeSlot(t, s, 0, tt.raw)
+			
+			d, err := s.Load(0)
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if d.Version != CurrentVersion {
+				t.Errorf("Version = %d, want %d", d.Version, CurrentVersion)
+			}
+			if r := d.Stages[4]; r == nil || !r.Cleared || r.BestScore != 1200 || r.BestTime != 95.5 {
+				t.Errorf("Stages[4] = %+v, want the old stage 1 record", r)
+			}
+			if r := d.Stages[8]; r == nil || !r.Cleared || r.BestScore != 800 {
+				t.Errorf("Stages[8] = %+v, want the old stage 2 record", r)
+			}
+			if len(d.Stages) != 2 {
+				t.Errorf("Stages = %v, want only stages 4 and 8", d.Stages)
+			}
+			if d.Items["MaxTomato"] != 2 || d.PlayTime != 360 {
+				t.Errorf("Items = %v, PlayTime = %v, want them kept", d.Items, d.PlayTime)
+			}
+			
+			// 保存し直しても同じ内容で読める（ステージ番号を二度付け替えない）
+			if err := s.Save(0, d); err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+			again, err := s.Load(0)
+			if err != nil {
+				t.Fatalf("Load after Save: %v", err)
+			}
+			again.UpdatedAt = d.UpdatedAt
+			if !reflect.DeepEqual(again, d) {
+				t.Errorf("round trip changed the data:\n got %+v\nwant %+v", again, d)
+			}
+		})
+	}
+}
+
+func TestLoadFallsBackToBackup(t *testing.T) {
+	s := NewStore(t.TempDir())
+	
+	first := New()
+	first.RecordClear(1, 100, 60)
+	if err := s.Save(0, first); err != nil {
+		t.Fatal(err)
+	}
+	second := New()
+	second.RecordClear(1, 200, 50)
+	if err := s.Save(0, second); err != nil {
+		t.Fatal(err)
+	}
+	
+	// 書き込みの途中で止まったように、ファイルを途中で切る
+	raw, err := os.ReadFile(s.path(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeSlot(t, s, 0, string(raw[:len(raw)/2]))
+	
+	d, err := s.Load(0)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := d.Record(1).BestScore; got != 100 {
+		t.Errorf("BestScore = %d, want 100 from the backup", got)
+	}
+	
+	// 壊れたファイルで良いバックアップを上書きしない
+	if err := s.Save(0, d); err != nil {
+		t.Fatal(err)
+	}
+	if backup, err := readFile(s.backupPath(0)); err != nil || backup.Record(1).BestScore != 100 {
+		t.Errorf("backup after saving over a corrupted file = %v, %v", backup, err)
+	}
+}
+
+func TestLoadCorruptedWithoutBackup(t *testing.T) {
+	s := NewStore(t.TempDir())
+	writeSlot(t, s, 0, `{"Version": 2, "Stages": {`)
+	
+	if _, err := s.Load(0); !errors.Is(err, ErrCorrupted) {
+		t.Errorf("Load = %v, want ErrCorrupted", err)
+	}
+	if _, err := s.Load(1); !errors.Is(err, ErrNoSave) {
+		t.Errorf("Load of an empty slot = %v, want ErrNoSave", err)
+	}
+}
+
+func TestLoadRejectsNewerVersion(t *testing.T) {
+	s := NewStore(t.TempDir())
+	if err := s.Save(0, New()); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save(0, New()); err != nil {
+		t.Fatal(err)
+	}
+	
+	// バックアップがあっても、新しいゲームのデータを古いバックアップで黙って置き換えない
+	writeSlot(t, s, 0, fmt.Sprintf(`{"Version": %d}`, CurrentVersion+1))
+	if _, err := os.Stat(filepath.Join(s.Dir, "slot1.json.bak")); err != nil {
+		t.Fatalf("no backup: %v", err)
+	}
+	if _, err := s.Load(0); !errors.Is(err, ErrVersion) {
+		t.Errorf("Load = %v, want ErrVersion", err)
+	}
+}
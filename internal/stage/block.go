@@ -0,0 +1,344 @@
+package stage
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+)
+
+// BlockKind は壊せるブロックの種類を表します
+type BlockKind int
+
+const (
+	BlockStar  BlockKind = iota // 星ブロック（どの攻撃でも壊れる）
+	BlockMetal                  // メタルブロック（ハンマーとストーンでだけ壊れる）
+	BlockBomb                   // 爆弾ブロック（壊すと同じグループのブロックを連鎖して壊す）
+	BlockFuse                   // 導火線（ファイアで火がつき、つながった導火線と爆弾ブロックへ燃え進む）
+	BlockGate                   // ゲート（攻撃では壊れず、同じグループのスイッチで開く）
+)
+
+// BlockHit はブロックに当たった攻撃の種類です。どの種類の攻撃で壊れるかはブロックごとに決まります
+type BlockHit int
+
+const (
+	HitNormal BlockHit = iota // ふつうの攻撃と飛び道具
+	HitHeavy                  // ハンマー・ストーン（メタルブロックも壊せる）
+	HitFire                   // ファイア（導火線に火をつけられる）
+)
+
+// ブロックとスイッチの大きさと連鎖の速さ
+const (
+	BlockSize      = 32.0
+	SwitchWidth    = 30.0
+	SwitchHeight   = 12.0
+	ChainSpeed     = 500.0 // 爆弾の連鎖が広がる速さ（距離/秒）
+	ChainMinDelay  = 0.05  // 連鎖で壊れるまでの最短の時間
+	FuseBurnTime   = 0.25  // 導火線1つが燃え尽きるまでの時間
+	BlockBurstTime = 0.3   // 壊れた時の破片の演出の時間
+)
+
+// Block はステージに置く壊せるブロックやゲートです
+type Block struct {
+	Rect      pixel.Rect
+	Kind      BlockKind
+	Group     int     // 爆弾の連鎖とスイッチのつながり（0 はつながりなし）
+	Broken    bool    // 壊れた（ゲートは開いた）
+	Timer     float64 // 連鎖で壊れる・導火線が燃え尽きるまでの残り時間（0 なら予定なし）
+	Burning   bool    // 導火線に火がついている
+	BreakTime float64 // 壊れてからの経過時間（破片の演出用）
+}
+
+// NewBlock は新しいブロックを作成します
+func NewBlock(kind BlockKind, rect pixel.Rect, group int) *Block {
+	return &Block{Rect: rect, Kind: kind, Group: group}
+}
+
+// Solid はブロックが足場や壁として当たり判定を持つかを返します（導火線は床に這うだけ）
+func (b *Block) Solid() bool {
+	return !b.Broken && b.Kind != BlockFuse
+}
+
+// BreaksBy は攻撃の種類でブロックが壊れる（導火線なら火がつく）かを返します
+func (b *Block) BreaksBy(hit BlockHit) bool {
+	if b.Broken {
+		return false
+	}
+	switch b.Kind {
+	case BlockStar, BlockBomb:
+		return true
+	case BlockMetal:
+		return hit == HitHeavy
+	case BlockFuse:
+		return hit == HitFire && !b.Burning
+	}
+	return false
+}
+
+// touches はブロック同士が接しているかを返します（導火線の燃え移りに使う）
+func (b *Block) touches(other *Block) bool {
+	return b.Rect.Resized(b.Rect.Center(), b.Rect.Size().Add(pixel.V(2, 2))).Intersects(other.Rect)
+}
+
+// Switch はゲートを開くスイッチです。乗るか攻撃すると押されたままになります
+type Switch struct {
+	Pos     pixel.Vec // 下端の中央
+	Group   int       // 開くゲートのグループ
+	Pressed bool
+}
+
+// NewSwitch は新しいスイッチを作成します
+func NewSwitch(pos pixel.Vec, group int) *Switch {
+	return &Switch{Pos: pos, Group: group}
+}
+
+// GetBounds はスイッチの当たり判定を返します
+func (sw *Switch) GetBounds() pixel.Rect {
+	return pixel.R(sw.Pos.X-SwitchWidth/2, sw.Pos.Y, sw.Pos.X+SwitchWidth/2, sw.Pos.Y+SwitchHeight)
+}
+
+// AddBlock はブロックを追加します
+func (s *Stage) AddBlock(block *Block) {
+	s.Blocks = append(s.Blocks, block)
+}
+
+// AddSwitch はスイッチを追加します
+func (s *Stage) AddSwitch(sw *Switch) {
+	s.Switches = append(s.Switches, sw)
+}
+
+// HitBlocks は中心 center・半径 radius の攻撃が届くブロックを攻撃の種類に応じて壊します。
+// 壊した（導火線なら火をつけた）ブロックの数を返します
+func (s *Stage) HitBlocks(center pixel.Vec, radius float64, hit BlockHit) int {
+	count := 0
+	for _, b := range s.Blocks {
+		if !circleIntersects(center, radius, b.Rect) || !b.BreaksBy(hit) {
+			continue
+		}
+		if b.Kind == BlockFuse {
+			b.Burning = true
+			b.Timer = FuseBurnTime
+		} else {
+			s.breakBlock(b)
+		}
+		count++
+	}
+	return count
+}
+
+// SolidBlockAt は中心 center・半径 radius の範囲に当たり判定のあるブロックがあるかを返します
+func (s *Stage) SolidBlockAt(center pixel.Vec, radius float64) bool {
+	for _, b := range s.Blocks {
+		if b.Solid() && circleIntersects(center, radius, b.Rect) {
+			return true
+		}
+	}
+	return false
+}
+
+// PressSwitch はスイッチを押し、同じグループのゲートを開きます。押したばかりなら true を返します
+func (s *Stage) PressSwitch(sw *Switch) bool {
+	if sw.Pressed {
+		return false
+	}
+	sw.Pressed = true
+	for _, b := range s.Blocks {
+		if b.Kind == BlockGate && b.Group == sw.Group && !b.Broken {
+			b.Broken = true
+			b.BreakTime = 0
+		}
+	}
+	return true
+}
+
+// breakBlock はブロックを壊します。爆弾ブロックなら同じグループのブロックに連鎖の予定を入れます
+func (s *Stage) breakBlock(b *Block) {
+	b.Broken = true
+	b.Burning = false
+	b.Timer = 0
+	b.BreakTime = 0
+	if b.Kind != BlockBomb || b.Group == 0 {
+		return
+	}
+	
+	for _, other := range s.Blocks {
+		if other.Broken || other.Timer > 0 || other.Group != b.Group || other.Kind == BlockGate {
+			continue
+		}
+		// 爆心から近い順に壊れていく
+		other.Timer = math.Max(ChainMinDelay, other.Rect.Center().Sub(b.Rect.Center()).Len()/ChainSpeed)
+	}
+}
+
+// UpdateBlocks は爆弾の連鎖と導火線の燃え進みを進めます
+func (s *Stage) UpdateBlocks(dt float64) {
+	for _, b := range s.Blocks {
+		if b.Broken {
+			b.BreakTime += dt
+			continue
+		}
+		if b.Timer <= 0 {
+			continue
+		}
+		b.Timer -= dt
+		if b.Timer > 0 {
+			continue
+		}
+		
+		if !b.Burning {
+			s.breakBlock(b)
+			continue
+		}
+		
+		// 燃え尽きた導火線は接している導火線に燃え移り、爆弾ブロックに引火する
+		s.breakBlock(b)
+		for _, other := range s.Blocks {
+			if other.Broken || !b.touches(other) {
+				continue
+			}
+			switch {
+			case other.Kind == BlockFuse && !other.Burning:
+				other.Burning = true
+				other.Timer = FuseBurnTime
+			case other.Kind == BlockBomb:
+				s.breakBlock(other)
+			}
+		}
+	}
+}
+
+// circleIntersects は円と矩形が重なっているかを返します
+func circleIntersects(center pixel.Vec, radius float64, r pixel.Rect) bool {
+	nearest := pixel.V(
+		math.Max(r.Min.X, math.Min(center.X, r.Max.X)),
+		math.Max(r.Min.Y, math.Min(center.Y, r.Max.Y)),
+	)
+	return nearest.Sub(center).Len() < radius
+}
+
+// groupColor はスイッチとゲートのグループの色を返します（同じグループは同じ色）
+func groupColor(group int) color.RGBA {
+	palette := []color.RGBA{
+		{R: 230, G: 70, B: 70, A: 255},
+		{R: 70, G: 120, B: 230, A: 255},
+		{R: 80, G: 190, B: 90, A: 255},
+		{R: 230, G: 190, B: 50, A: 255},
+	}
+	if group < 0 {
+		group = -group
+	}
+	return palette[group%len(palette)]
+}
+
+// Draw はブロックを描画します。壊れたブロックはしばらく破片を描きます
+func (b *Block) Draw(imd *imdraw.IMDraw) {
+	r := b.Rect
+	if b.Broken {
+		if b.Kind != BlockGate && b.BreakTime < BlockBurstTime {
+			b.drawBurst(imd)
+		}
+		return
+	}
+	
+	switch b.Kind {
+	case BlockStar:
+		// 黄色い地に星の模様
+		imd.Color = color.RGBA{R: 240, G: 200, B: 90, A: 255}
+		imd.Push(r.Min, r.Max)
+		imd.Rectangle(0)
+		imd.Color = color.RGBA{R: 255, G: 245, B: 180, A: 255}
+		drawStar(imd, r.Center(), math.Min(r.W(), r.H())*0.35)
+		
+	case BlockMetal:
+		// 灰色の鉄板と四隅の鋲
+		imd.Color = color.RGBA{R: 120, G: 125, B: 140, A: 255}
+		imd.Push(r.Min, r.Max)
+		imd.Rectangle(0)
+		imd.Color = color.RGBA{R: 190, G: 195, B: 210, A: 255}
+		for _, corner := range []pixel.Vec{r.Min, pixel.V(r.Max.X, r.Min.Y), r.Max, pixel.V(r.Min.X, r.Max.Y)} {
+			imd.Push(corner.Add(r.Center().Sub(corner).Unit().Scaled(7)))
+			imd.Circle(2.5, 0)
+		}
+		
+	case BlockBomb:
+		// 黒い爆弾の絵
+		imd.Color = color.RGBA{R: 90, G: 70, B: 60, A: 255}
+		imd.Push(r.Min, r.Max)
+		imd.Rectangle(0)
+		imd.Color = color.RGBA{R: 30, G: 30, B: 40, A: 255}
+		imd.Push(r.Center().Sub(pixel.V(0, 2)))
+		imd.Circle(math.Min(r.W(), r.H())*0.3, 0)
+		imd.Color = color.RGBA{R: 255, G: 120, B: 40, A: 255}
+		imd.Push(r.Center().Add(pixel.V(4, math.Min(r.W(), r.H())*0.3)))
+		imd.Circle(2.5, 0)
+		
+	case BlockFuse:
+		// 縄と、燃えていれば火花
+		imd.Color = color.RGBA{R: 150, G: 110, B: 60, A: 255}
+		imd.Push(r.Min, r.Max)
+		imd.Rectangle(0)
+		if b.Burning {
+			imd.Color = color.RGBA{R: 255, G: 200, B: 60, A: 255}
+			imd.Push(r.Center())
+			imd.Circle(4+math.Sin(b.Timer*60)*1.5, 0)
+		}
+		
+	case BlockGate:
+		// グループの色の鉄格子
+		imd.Color = color.RGBA{R: 60, G: 60, B: 70, A: 255}
+		imd.Push(r.Min, r.Max)
+		imd.Rectangle(0)
+		imd.Color = groupColor(b.Group)
+		for x := r.Min.X + 6; x < r.Max.X; x += 10 {
+			imd.Push(pixel.V(x, r.Min.Y), pixel.V(x, r.Max.Y))
+			imd.Line(3)
+		}
+	}
+}
+
+// drawBurst は壊れたブロックの飛び散る破片を描画します
+func (b *Block) drawBurst(imd *imdraw.IMDraw) {
+	t := b.BreakTime / BlockBurstTime
+	imd.Color = color.RGBA{R: 255, G: 230, B: 150, A: uint8(255 * (1 - t))}
+	if b.Kind == BlockBomb {
+		// 爆発の輪
+		imd.Push(b.Rect.Center())
+		imd.Circle(BlockSize*(0.5+t), 4)
+	}
+	for i := 0; i < 4; i++ {
+		angle := math.Pi/4 + float64(i)*math.Pi/2
+		p := b.Rect.Center().Add(pixel.V(math.Cos(angle), math.Sin(angle)).Scaled(BlockSize * t))
+		imd.Push(p)
+		imd.Circle(4, 0)
+	}
+}
+
+// Draw はスイッチを描画します（押されると沈む）
+func (sw *Switch) Draw(imd *imdraw.IMDraw) {
+	r := sw.GetBounds()
+	imd.Color = color.RGBA{R: 80, G: 80, B: 90, A: 255}
+	imd.Push(r.Min, pixel.V(r.Max.X, r.Min.Y+4))
+	imd.Rectangle(0)
+	
+	top := r.Max.Y
+	if sw.Pressed {
+		top = r.Min.Y + 6
+	}
+	imd.Color = groupColor(sw.Group)
+	imd.Push(pixel.V(r.Min.X+4, r.Min.Y+4), pixel.V(r.Max.X-4, top))
+	imd.Rectangle(0)
+}
+
+// drawStar は五芒星を描画します
+func drawStar(imd *imdraw.IMDraw, center pixel.Vec, radius float64) {
+	for i := 0; i < 10; i++ {
+		r := radius
+		if i%2 == 1 {
+			r = radius * 0.45
+		}
+		angle := math.Pi/2 + float64(i)*math.Pi/5
+		imd.Push(center.Add(pixel.V(math.Cos(angle)*r, math.Sin(angle)*r)))
+	}
+	imd.Polygon(0)
+}
@@ -0,0 +1,69 @@
+package stage
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+)
+
+// 中間ポイントの大きさ
+const (
+	CheckpointWidth  = 24.0
+	CheckpointHeight = 70.0
+	CheckpointSpawn  = 40.0 // 復帰する位置の足元からの高さ
+)
+
+// Checkpoint は中間ポイントです。触れると光り、やられた時や穴に落ちた時にここから復帰します
+type Checkpoint struct {
+	Pos    pixel.Vec // 足元の中央
+	Active bool
+	Time   float64 // 旗のアニメーション用の経過時間
+}
+
+// NewCheckpoint は新しい中間ポイントを作成します
+func NewCheckpoint(pos pixel.Vec) *Checkpoint {
+	return &Checkpoint{Pos: pos}
+}
+
+// GetBounds は中間ポイントの当たり判定を返します
+func (cp *Checkpoint) GetBounds() pixel.Rect {
+	return pixel.R(cp.Pos.X-CheckpointWidth/2, cp.Pos.Y, cp.Pos.X+CheckpointWidth/2, cp.Pos.Y+CheckpointHeight)
+}
+
+// SpawnPos は復帰する位置（キャラクターの中心）を返します
+func (cp *Checkpoint) SpawnPos() pixel.Vec {
+	return cp.Pos.Add(pixel.V(0, CheckpointSpawn))
+}
+
+// Update は旗をなびかせます
+func (cp *Checkpoint) Update(dt float64) {
+	cp.Time += dt
+}
+
+// Draw は中間ポイントを描画します（触れる前は灰色の旗、触れた後は星の旗）
+func (cp *Checkpoint) Draw(imd *imdraw.IMDraw) {
+	top := cp.Pos.Add(pixel.V(0, CheckpointHeight))
+	
+	// ポール
+	imd.Color = color.RGBA{R: 220, G: 220, B: 230, A: 255}
+	imd.Push(cp.Pos, top)
+	imd.Line(3)
+	
+	// 旗
+	wave := math.Sin(cp.Time*5) * 3
+	imd.Color = color.RGBA{R: 140, G: 140, B: 150, A: 255}
+	if cp.Active {
+		imd.Color = color.RGBA{R: 255, G: 120, B: 170, A: 255}
+	}
+	imd.Push(top, top.Add(pixel.V(28, -8+wave)), top.Sub(pixel.V(0, 18)))
+	imd.Polygon(0)
+	
+	// 触れた後はポールの先の星が光る
+	if cp.Active {
+		imd.Color = color.RGBA{R: 255, G: 230, B: 60, A: 255}
+		imd.Push(top.Add(pixel.V(0, 4)))
+		imd.Circle(5+math.Sin(cp.Time*4), 0)
+	}
+}
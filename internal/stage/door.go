@@ -0,0 +1,102 @@
+package stage
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+)
+
+// 扉とワープスターの大きさ
+const (
+	DoorWidth      = 40.0
+	DoorHeight     = 60.0
+	WarpStarRadius = 22.0
+)
+
+// Door は同じステージの別の部屋へつながる扉です（扉の前で上を押すと入る）
+type Door struct {
+	Pos    pixel.Vec // 扉の下端の中央
+	Target int       // 行き先の部屋の番号
+	Exit   pixel.Vec // 行き先の部屋で出てくる位置
+}
+
+// NewDoor は新しい扉を作成します
+func NewDoor(pos pixel.Vec, target int, exit pixel.Vec) *Door {
+	return &Door{Pos: pos, Target: target, Exit: exit}
+}
+
+// GetBounds は扉の当たり判定を返します
+func (d *Door) GetBounds() pixel.Rect {
+	return pixel.R(d.Pos.X-DoorWidth/2, d.Pos.Y, d.Pos.X+DoorWidth/2, d.Pos.Y+DoorHeight)
+}
+
+// Draw は扉を描画します
+func (d *Door) Draw(imd *imdraw.IMDraw) {
+	r := d.GetBounds()
+	
+	// 枠と扉板
+	imd.Color = color.RGBA{R: 80, G: 50, B: 30, A: 255}
+	imd.Push(r.Min.Sub(pixel.V(4, 0)), r.Max.Add(pixel.V(4, 4)))
+	imd.Rectangle(0)
+	imd.Color = color.RGBA{R: 150, G: 95, B: 50, A: 255}
+	imd.Push(r.Min, r.Max)
+	imd.Rectangle(0)
+	
+	// ドアノブ
+	imd.Color = color.RGBA{R: 255, G: 215, B: 0, A: 255}
+	imd.Push(pixel.V(d.Pos.X+DoorWidth/4, d.Pos.Y+DoorHeight/2))
+	imd.Circle(3, 0)
+}
+
+// WarpStar は触れると乗って別の部屋へ飛んでいくワープスターです
+type WarpStar struct {
+	Home   pixel.Vec // 置かれている位置
+	Pos    pixel.Vec // 今の位置（乗って飛んでいる間は Home から離れる）
+	Target int       // 行き先の部屋の番号
+	Exit   pixel.Vec // 行き先の部屋で降りる位置
+	Time   float64   // 揺れのアニメーション用の経過時間
+}
+
+// NewWarpStar は新しいワープスターを作成します
+func NewWarpStar(pos pixel.Vec, target int, exit pixel.Vec) *WarpStar {
+	return &WarpStar{Home: pos, Pos: pos, Target: target, Exit: exit}
+}
+
+// Update は置かれているワープスターを上下に揺らします
+func (w *WarpStar) Update(dt float64) {
+	w.Time += dt
+	w.Pos = w.Home.Add(pixel.V(0, math.Sin(w.Time*3)*6))
+}
+
+// Reset はワープスターを元の位置に戻します
+func (w *WarpStar) Reset() {
+	w.Pos = w.Home
+	w.Time = 0
+}
+
+// GetBounds はワープスターの当たり判定を返します
+func (w *WarpStar) GetBounds() pixel.Rect {
+	return pixel.R(w.Pos.X-WarpStarRadius, w.Pos.Y-WarpStarRadius, w.Pos.X+WarpStarRadius, w.Pos.Y+WarpStarRadius)
+}
+
+// Draw はワープスターを描画します
+func (w *WarpStar) Draw(imd *imdraw.IMDraw) {
+	// 光の輪
+	imd.Color = color.RGBA{R: 255, G: 255, B: 200, A: 90}
+	imd.Push(w.Pos)
+	imd.Circle(WarpStarRadius+6, 0)
+	
+	// 星
+	imd.Color = color.RGBA{R: 255, G: 220, B: 40, A: 255}
+	for i := 0; i < 10; i++ {
+		r := WarpStarRadius
+		if i%2 == 1 {
+			r = WarpStarRadius * 0.45
+		}
+		angle := math.Pi/2 + float64(i)*math.Pi/5 + math.Sin(w.Time*2)*0.2
+		imd.Push(w.Pos.Add(pixel.V(math.Cos(angle)*r, math.Sin(angle)*r)))
+	}
+	imd.Polygon(0)
+}
@@ -0,0 +1,100 @@
+package stage
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+)
+
+// HazardKind は地形の仕掛けの種類を表します
+type HazardKind int
+
+const (
+	HazardSpikes HazardKind = iota // トゲ（触れるとダメージと吹き飛ばし）
+	HazardLava                     // 溶岩（中にいる間ダメージを受け続ける）
+	HazardWater                    // 水（泳ぐ。重力と速さが変わる）
+	HazardWind                     // 風（中にいるものを流す）
+)
+
+// Hazard はステージに置く仕掛けの範囲です
+type Hazard struct {
+	Kind HazardKind
+	Rect pixel.Rect
+	Wind pixel.Vec // 風に流される速さ（風のみ）
+}
+
+// NewHazard は新しい仕掛けを作成します
+func NewHazard(kind HazardKind, rect pixel.Rect) *Hazard {
+	return &Hazard{Kind: kind, Rect: rect}
+}
+
+// NewWindZone は風の吹く範囲を作成します
+func NewWindZone(rect pixel.Rect, wind pixel.Vec) *Hazard {
+	return &Hazard{Kind: HazardWind, Rect: rect, Wind: wind}
+}
+
+// Contains は位置が仕掛けの範囲内にあるかを返します
+func (h *Hazard) Contains(pos pixel.Vec) bool {
+	return h.Rect.Contains(pos)
+}
+
+// Draw は仕掛けを描画します。time はステージの経過時間です（波や風の動きに使う）
+func (h *Hazard) Draw(imd *imdraw.IMDraw, time float64) {
+	r := h.Rect
+	switch h.Kind {
+	case HazardSpikes:
+		// 土台と三角のトゲ
+		imd.Color = color.RGBA{R: 90, G: 90, B: 100, A: 255}
+		imd.Push(r.Min, pixel.V(r.Max.X, r.Min.Y+r.H()*0.3))
+		imd.Rectangle(0)
+		imd.Color = color.RGBA{R: 200, G: 200, B: 215, A: 255}
+		count := int(math.Max(1, math.Round(r.W()/16)))
+		w := r.W() / float64(count)
+		for i := 0; i < count; i++ {
+			x := r.Min.X + w*float64(i)
+			imd.Push(pixel.V(x, r.Min.Y), pixel.V(x+w, r.Min.Y), pixel.V(x+w/2, r.Max.Y))
+			imd.Polygon(0)
+		}
+		
+	case HazardLava:
+		// 赤い溶岩と揺れる表面
+		imd.Color = color.RGBA{R: 200, G: 50, B: 20, A: 255}
+		imd.Push(r.Min, r.Max)
+		imd.Rectangle(0)
+		imd.Color = color.RGBA{R: 255, G: 160, B: 40, A: 255}
+		for x := r.Min.X; x < r.Max.X; x += 24 {
+			y := r.Max.Y - 4 + math.Sin(time*3+x*0.05)*3
+			imd.Push(pixel.V(x+12, y))
+			imd.Circle(6, 0)
+		}
+		
+	case HazardWater:
+		// 半透明の水と波
+		imd.Color = color.RGBA{R: 40, G: 110, B: 220, A: 110}
+		imd.Push(r.Min, r.Max)
+		imd.Rectangle(0)
+		imd.Color = color.RGBA{R: 200, G: 230, B: 255, A: 160}
+		for x := r.Min.X; x < r.Max.X; x += 20 {
+			y := r.Max.Y + math.Sin(time*2+x*0.08)*2
+			imd.Push(pixel.V(x, y), pixel.V(math.Min(x+12, r.Max.X), y))
+			imd.Line(2)
+		}
+		
+	case HazardWind:
+		// 流れる筋（風の向きに動く）
+		imd.Color = color.RGBA{R: 255, G: 255, B: 255, A: 70}
+		dir := h.Wind.Unit()
+		length := r.W() + r.H()
+		for i := 0; i < 8; i++ {
+			seed := float64(i) * 0.37
+			offset := math.Mod(time*h.Wind.Len()+seed*length, length)
+			start := pixel.V(r.Min.X+math.Mod(seed*r.W()*2.3, r.W()), r.Min.Y+math.Mod(seed*r.H()*3.1, r.H()))
+			p := start.Add(dir.Scaled(offset))
+			p = pixel.V(r.Min.X+math.Mod(p.X-r.Min.X+r.W(), r.W()), r.Min.Y+math.Mod(p.Y-r.Min.Y+r.H(), r.H()))
+			imd.Push(p, p.Add(dir.Scaled(30)))
+			imd.Line(2)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package stage
 
 import (
 	"image/color"
+	"math"
 
 	"github.com/faiface/pixel"
 	"github.com/faiface/pixel/imdraw"
@@ -11,6 +12,7 @@ import (
 type Platform struct {
 	Rect  pixel.Rect
 	Color color.RGBA
+	Ice   bool // 氷の足場（摩擦が小さく滑る）
 }
 
 // NewPlatform は新しいプラットフォームを作成します
@@ -26,14 +28,28 @@ func (p *Platform) Draw(imd *imdraw.IMDraw) {
 	imd.Color = p.Color
 	imd.Push(p.Rect.Min, p.Rect.Max)
 	imd.Rectangle(0)
+	
+	// 氷の足場は上面に光の筋
+	if p.Ice {
+		imd.Color = color.RGBA{R: 230, G: 250, B: 255, A: 255}
+		imd.Push(pixel.V(p.Rect.Min.X, p.Rect.Max.Y-3), p.Rect.Max)
+		imd.Rectangle(0)
+	}
 }
 
 // Stage はステージ全体を表します
 type Stage struct {
-	Width      float64
-	Height     float64
-	Platforms  []*Platform
-	Background color.RGBA
+	Width       float64
+	Height      float64
+	Platforms   []*Platform
+	Background  color.RGBA
+	Doors       []*Door       // 別の部屋への扉
+	WarpStars   []*WarpStar   // 別の部屋へ飛ぶワープスター
+	Hazards     []*Hazard     // トゲ・溶岩・水・風
+	Blocks      []*Block      // 壊せるブロック・導火線・ゲート
+	Switches    []*Switch     // ゲートを開くスイッチ
+	Checkpoints []*Checkpoint // 中間ポイント
+	Vendors     []*Vendor     // 店の屋台
 }
 
 // NewStage は新しいステージを作成します
@@ -51,12 +67,78 @@ func (s *Stage) AddPlatform(platform *Platform) {
 	s.Platforms = append(s.Platforms, platform)
 }
 
+// AddDoor は扉を追加します
+func (s *Stage) AddDoor(door *Door) {
+	s.Doors = append(s.Doors, door)
+}
+
+// AddCheckpoint は中間ポイントを追加します
+func (s *Stage) AddCheckpoint(cp *Checkpoint) {
+	s.Checkpoints = append(s.Checkpoints, cp)
+}
+
+// AddVendor は店の屋台を追加します
+func (s *Stage) AddVendor(v *Vendor) {
+	s.Vendors = append(s.Vendors, v)
+}
+
+// AddHazard は仕掛けを追加します
+func (s *Stage) AddHazard(hazard *Hazard) {
+	s.Hazards = append(s.Hazards, hazard)
+}
+
+// DrawHazards は仕掛けを描画します。水はキャラクターの手前に描くため front が true の時だけ描きます
+func (s *Stage) DrawHazards(imd *imdraw.IMDraw, time float64, front bool) {
+	for _, hazard := range s.Hazards {
+		if (hazard.Kind == HazardWater) == front {
+			hazard.Draw(imd, time)
+		}
+	}
+}
+
+// IceBelow は足元（bounds の下端のすぐ下）が氷の足場かを返します
+func (s *Stage) IceBelow(bounds pixel.Rect) bool {
+	for _, platform := range s.Platforms {
+		if !platform.Ice {
+			continue
+		}
+		r := platform.Rect
+		if bounds.Max.X > r.Min.X && bounds.Min.X < r.Max.X && math.Abs(bounds.Min.Y-r.Max.Y) < 4 {
+			return true
+		}
+	}
+	return false
+}
+
+// AddWarpStar はワープスターを追加します
+func (s *Stage) AddWarpStar(star *WarpStar) {
+	s.WarpStars = append(s.WarpStars, star)
+}
+
 // Draw はステージを描画します
 func (s *Stage) Draw(imd *imdraw.IMDraw) {
-	// 背景は別途描画されるため、ここではプラットフォームのみ
+	// 背景は別途描画されるため、ここではプラットフォームとブロック・扉・屋台・ワープスターのみ
 	for _, platform := range s.Platforms {
 		platform.Draw(imd)
 	}
+	for _, block := range s.Blocks {
+		block.Draw(imd)
+	}
+	for _, sw := range s.Switches {
+		sw.Draw(imd)
+	}
+	for _, door := range s.Doors {
+		door.Draw(imd)
+	}
+	for _, cp := range s.Checkpoints {
+		cp.Draw(imd)
+	}
+	for _, v := range s.Vendors {
+		v.Draw(imd)
+	}
+	for _, star := range s.WarpStars {
+		star.Draw(imd)
+	}
 	
 	// 地面
 	ground := pixel.R(0, 0, s.Width, 5)
@@ -93,6 +175,53 @@ func (s *Stage) CheckCollision(entityBounds pixel.Rect, velocity pixel.Vec) (pix
 	return newPos, collided
 }
 
+// SweepStep は SweepTo で当たり判定を調べる間隔です
+const SweepStep = 4.0
+
+// SweepTo は半径 radius の四角い当たり判定を from から to へまっすぐ動かし、
+// 途中の足場や壊れていないブロックにめり込む手前で止めた位置を返します
+// （一瞬で遠くへ移動する技が地形や閉じたゲートを抜けないように）。
+// 動き始めから重なっている足場とブロックは、乗っているだけなので無視します
+func (s *Stage) SweepTo(from, to pixel.Vec, radius float64) pixel.Vec {
+	boundsAt := func(pos pixel.Vec) pixel.Rect {
+		return pixel.R(pos.X-radius, pos.Y-radius, pos.X+radius, pos.Y+radius)
+	}
+	
+	var walls []pixel.Rect
+	for _, rect := range s.SolidRects() {
+		if !boundsAt(from).Intersects(rect) {
+			walls = append(walls, rect)
+		}
+	}
+	
+	steps := int(math.Ceil(to.Sub(from).Len() / SweepStep))
+	pos := from
+	for i := 1; i <= steps; i++ {
+		next := pixel.Lerp(from, to, float64(i)/float64(steps))
+		for _, wall := range walls {
+			if boundsAt(next).Intersects(wall) {
+				return pos
+			}
+		}
+		pos = next
+	}
+	return pos
+}
+
+// SolidRects は足場と壊れていないブロックの矩形一覧を返します（アイテムなどの簡易的な接地判定用）
+func (s *Stage) SolidRects() []pixel.Rect {
+	rects := make([]pixel.Rect, 0, len(s.Platforms)+len(s.Blocks))
+	for _, platform := range s.Platforms {
+		rects = append(rects, platform.Rect)
+	}
+	for _, block := range s.Blocks {
+		if block.Solid() {
+			rects = append(rects, block.Rect)
+		}
+	}
+	return rects
+}
+
 // CreateDefaultStage はデフォルトのステージを作成します
 func CreateDefaultStage(width, height float64) *Stage {
 	stage := NewStage(width, height)
@@ -108,3 +237,21 @@ func CreateDefaultStage(width, height float64) *Stage {
 	
 	return stage
 }
+
+// CreateArenaStage は対戦用の左右対称なアリーナを作成します
+func CreateArenaStage(width, height float64) *Stage {
+	stage := NewStage(width, height)
+	stage.Background = color.RGBA{R: 60, G: 40, B: 90, A: 255} // 夕暮れの紫
+	
+	// 中央の大きな足場と左右対称の浮き足場
+	stage.AddPlatform(NewPlatform(width/2-200, 140, 400, 20))
+	stage.AddPlatform(NewPlatform(width/2-350, 280, 180, 20))
+	stage.AddPlatform(NewPlatform(width/2+170, 280, 180, 20))
+	stage.AddPlatform(NewPlatform(width/2-90, 420, 180, 20))
+	
+	for _, platform := range stage.Platforms {
+		platform.Color = color.RGBA{R: 180, G: 160, B: 210, A: 255}
+	}
+	
+	return stage
+}
@@ -0,0 +1,99 @@
+package stage
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+)
+
+// 店の大きさ
+const (
+	VendorWidth  = 90.0
+	VendorHeight = 100.0
+)
+
+// Vendor は店番のワドルディがいる屋台です。前で上を押すと店が開きます
+type Vendor struct {
+	Pos  pixel.Vec // 足元の中央
+	Time float64   // 店番のアニメーション用の経過時間
+}
+
+// NewVendor は新しい屋台を作成します
+func NewVendor(pos pixel.Vec) *Vendor {
+	return &Vendor{Pos: pos}
+}
+
+// GetBounds は話しかけられる範囲を返します
+func (v *Vendor) GetBounds() pixel.Rect {
+	return pixel.R(v.Pos.X-VendorWidth/2, v.Pos.Y, v.Pos.X+VendorWidth/2, v.Pos.Y+VendorHeight)
+}
+
+// Update は店番を揺らします
+func (v *Vendor) Update(dt float64) {
+	v.Time += dt
+}
+
+// Draw は屋台と、カウンターの後ろの店番を描画します
+func (v *Vendor) Draw(imd *imdraw.IMDraw) {
+	left := v.Pos.X - VendorWidth/2
+	right := v.Pos.X + VendorWidth/2
+	counterTop := v.Pos.Y + 36
+	roof := v.Pos.Y + VendorHeight
+	
+	// 屋根を支える柱
+	imd.Color = color.RGBA{R: 120, G: 80, B: 40, A: 255}
+	for _, x := range []float64{left + 4, right - 4} {
+		imd.Push(pixel.V(x, v.Pos.Y), pixel.V(x, roof))
+		imd.Line(4)
+	}
+	
+	// 店番のワドルディ（カウンターから上半分が見える）
+	bob := math.Sin(v.Time*3) * 2
+	body := pixel.V(v.Pos.X, counterTop+10+bob)
+	imd.Color = color.RGBA{R: 240, G: 140, B: 60, A: 255}
+	imd.Push(body)
+	imd.Circle(20, 0)
+	imd.Color = color.RGBA{R: 255, G: 220, B: 180, A: 255}
+	imd.Push(body.Add(pixel.V(0, -2)))
+	imd.Ellipse(pixel.V(14, 11), 0)
+	imd.Color = color.RGBA{R: 30, G: 30, B: 40, A: 255}
+	for _, side := range []float64{-1, 1} {
+		imd.Push(body.Add(pixel.V(side*5, 0)))
+		imd.Ellipse(pixel.V(2.5, 5), 0)
+	}
+	// 店番の青いバンダナ
+	imd.Color = color.RGBA{R: 60, G: 110, B: 220, A: 255}
+	imd.Push(body.Add(pixel.V(-17, 10)), body.Add(pixel.V(17, 10)), body.Add(pixel.V(0, 22)))
+	imd.Polygon(0)
+	
+	// カウンターとコインの看板
+	imd.Color = color.RGBA{R: 170, G: 110, B: 60, A: 255}
+	imd.Push(pixel.V(left, v.Pos.Y), pixel.V(right, counterTop))
+	imd.Rectangle(0)
+	imd.Color = color.RGBA{R: 140, G: 90, B: 45, A: 255}
+	imd.Push(pixel.V(left, counterTop-4), pixel.V(right, counterTop))
+	imd.Rectangle(0)
+	imd.Color = color.RGBA{R: 255, G: 215, B: 60, A: 255}
+	imd.Push(pixel.V(v.Pos.X, v.Pos.Y+18))
+	imd.Circle(9, 0)
+	imd.Color = color.RGBA{R: 200, G: 150, B: 30, A: 255}
+	imd.Push(pixel.V(v.Pos.X, v.Pos.Y+18))
+	imd.Circle(9, 2)
+	
+	// 赤と白の縞の日よけ
+	stripes := 6
+	w := (VendorWidth + 16) / float64(stripes)
+	for i := 0; i < stripes; i++ {
+		imd.Color = color.RGBA{R: 230, G: 60, B: 60, A: 255}
+		if i%2 == 1 {
+			imd.Color = color.RGBA{R: 250, G: 245, B: 240, A: 255}
+		}
+		x := left - 8 + float64(i)*w
+		imd.Push(pixel.V(x, roof-14), pixel.V(x+w, roof+6))
+		imd.Rectangle(0)
+		imd.Push(pixel.V(x+w/2, roof-14))
+		imd.Circle(w/2, 0)
+	}
+}
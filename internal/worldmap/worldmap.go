@@ -0,0 +1,169 @@
+// Package worldmap はストーリーのワールドマップ（レベルとステージの扉の並び）を扱います。
+// マップの配置は worldmap.json に書かれていて、どの扉が開くかはクリアしたステージから決まります
+package worldmap
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+)
+
+//go:embed worldmap.json
+var defaultData []byte
+
+// Node はマップ上の扉（1つのステージ）です
+type Node struct {
+	Stage int     `json:"stage"` // ステージ番号
+	Name  string  `json:"name"`
+	X     float64 `json:"x"` // 画面上の位置
+	Y     float64 `json:"y"`
+}
+
+// Level は複数のステージと、最後のボスの扉をまとめたものです
+type Level struct {
+	Name   string   `json:"name"`
+	Color  [3]uint8 `json:"color"`
+	Stages []Node   `json:"stages"`
+	Boss   Node     `json:"boss"` // レベルのステージをすべてクリアすると開く
+}
+
+// Map はワールドマップ全体です
+type Map struct {
+	Levels []Level `json:"levels"`
+}
+
+// Progress はステージのクリア状況です（セーブデータが満たします）
+type Progress interface {
+	Cleared(stage int) bool
+}
+
+// Parse はマップのデータを読み込み、内容を確かめます
+func Parse(data []byte) (*Map, error) {
+	var m Map
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	if len(m.Levels) == 0 {
+		return nil, fmt.Errorf("worldmap: no levels")
+	}
+	
+	seen := make(map[int]bool)
+	for i, level := range m.Levels {
+		if len(level.Stages) == 0 {
+			return nil, fmt.Errorf("worldmap: level %q has no stages", level.Name)
+		}
+		for _, node := range m.Nodes(i) {
+			if node.Stage <= 0 {
+				return nil, fmt.Errorf("worldmap: level %q has a door without a stage", level.Name)
+			}
+			if seen[node.Stage] {
+				return nil, fmt.Errorf("worldmap: stage %d appears twice", node.Stage)
+			}
+			seen[node.Stage] = true
+		}
+	}
+	return &m, nil
+}
+
+// Default は組み込みのマップを返します
+func Default() *Map {
+	m, err := Parse(defaultData)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Nodes はレベルの扉を順番に返します（最後がボスの扉）
+func (m *Map) Nodes(level int) []Node {
+	l := m.Levels[level]
+	nodes := make([]Node, 0, len(l.Stages)+1)
+	nodes = append(nodes, l.Stages...)
+	return append(nodes, l.Boss)
+}
+
+// IsBoss はレベルの index 番目の扉がボスの扉かを返します
+func (m *Map) IsBoss(level, index int) bool {
+	return index == len(m.Levels[level].Stages)
+}
+
+// StageCount はマップにあるステージ（ボスを含む）の数を返します
+func (m *Map) StageCount() int {
+	count := 0
+	for _, level := range m.Levels {
+		count += len(level.Stages) + 1
+	}
+	return count
+}
+
+// Find はステージのあるレベルと扉の番号を返します
+func (m *Map) Find(stage int) (level, index int, ok bool) {
+	for i := range m.Levels {
+		for j, node := range m.Nodes(i) {
+			if node.Stage == stage {
+				return i, j, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// cleared はステージをクリアしたかを返します（進行状況がなければすべて未クリア）
+func cleared(p Progress, stage int) bool {
+	return p != nil && p.Cleared(stage)
+}
+
+// LevelUnlocked はレベルに入れるかを返します。最初のレベル以外は、1つ前のボスを倒すと開きます。
+// 進行状況が nil の場合はすべて開いているものとします
+func (m *Map) LevelUnlocked(level int, p Progress) bool {
+	if p == nil || level == 0 {
+		return true
+	}
+	return cleared(p, m.Levels[level-1].Boss.Stage)
+}
+
+// NodeUnlocked は扉が開いているかを返します。
+// ステージの扉は1つ前のステージをクリアすると開き、ボスの扉はレベルのステージをすべてクリアすると開きます
+func (m *Map) NodeUnlocked(level, index int, p Progress) bool {
+	if !m.LevelUnlocked(level, p) {
+		return false
+	}
+	if p == nil {
+		return true
+	}
+	
+	stages := m.Levels[level].Stages
+	if m.IsBoss(level, index) {
+		for _, node := range stages {
+			if !cleared(p, node.Stage) {
+				return false
+			}
+		}
+		return true
+	}
+	return index == 0 || cleared(p, stages[index-1].Stage)
+}
+
+// LevelComplete はレベルのステージとボスをすべてクリアしたかを返します
+func (m *Map) LevelComplete(level int, p Progress) bool {
+	for _, node := range m.Nodes(level) {
+		if !cleared(p, node.Stage) {
+			return false
+		}
+	}
+	return true
+}
+
+// NextNode は次に挑戦する扉（開いていて未クリアの最初の扉）を返します。
+// すべてクリアしていれば最後のボスの扉を返します
+func (m *Map) NextNode(p Progress) (level, index int) {
+	for i := range m.Levels {
+		for j, node := range m.Nodes(i) {
+			if m.NodeUnlocked(i, j, p) && !cleared(p, node.Stage) {
+				return i, j
+			}
+		}
+	}
+	last := len(m.Levels) - 1
+	return last, len(m.Levels[last].Stages)
+}